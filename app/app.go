@@ -2,22 +2,34 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"messanger/config"
 	"messanger/controller/http"
 	"messanger/data/cache/redis"
+	cmdmail "messanger/data/mail/cmd_mail"
+	smtp "messanger/data/mail/smtp"
 	"messanger/data/repository/mysql"
-	sms "messanger/data/sms/cmd_sms"
+	cmdsms "messanger/data/sms/cmd_sms"
+	smsru "messanger/data/sms/smsru"
+	twilio "messanger/data/sms/twilio"
+	storage "messanger/data/storage/local"
+	"messanger/domain/ports"
 	"messanger/domain/service/auth"
 	"messanger/domain/service/chats"
+	"messanger/domain/service/export"
 	"messanger/domain/service/groups"
+	"messanger/domain/service/importer"
 	"messanger/domain/service/messages"
 	"messanger/domain/service/phone"
+	"messanger/domain/service/sms"
+	"messanger/domain/service/stickers"
 	"messanger/domain/service/users"
 	"messanger/pkg/db"
 	"messanger/pkg/http_server"
 	"messanger/pkg/redis"
 	"os"
+	"time"
 )
 
 func Run(cfgPath string) {
@@ -42,8 +54,6 @@ func Run(cfgPath string) {
 	}
 	defer r.Close()
 
-	smsSender := sms.NewCmdSmsAdapter()
-
 	chatsRepo, err := mysql.NewChats(TxDB)
 	if err != nil {
 		log.Fatal("chats repo: ", err)
@@ -60,19 +70,88 @@ func Run(cfgPath string) {
 	if err != nil {
 		log.Fatal("users repo: ", err)
 	}
+	// messages reference stickers
+	stickersRepo, err := mysql.NewStickers(TxDB)
+	if err != nil {
+		log.Fatal("stickers repo: ", err)
+	}
 	messagesRepo, err := mysql.NewMessages(TxDB)
 	if err != nil {
 		log.Fatal("messages repo: ", err)
 	}
+	bookmarksRepo, err := mysql.NewBookmarks(TxDB)
+	if err != nil {
+		log.Fatal("bookmarks repo: ", err)
+	}
+	exportsRepo, err := mysql.NewExports(TxDB)
+	if err != nil {
+		log.Fatal("exports repo: ", err)
+	}
+	importsRepo, err := mysql.NewImports(TxDB)
+	if err != nil {
+		log.Fatal("imports repo: ", err)
+	}
+	invitesRepo, err := mysql.NewInvites(TxDB)
+	if err != nil {
+		log.Fatal("invites repo: ", err)
+	}
+	restrictionsRepo, err := mysql.NewRestrictions(TxDB)
+	if err != nil {
+		log.Fatal("restrictions repo: ", err)
+	}
+	auditRepo, err := mysql.NewAudit(TxDB)
+	if err != nil {
+		log.Fatal("audit repo: ", err)
+	}
+	sessionsRepo, err := mysql.NewSessions(TxDB)
+	if err != nil {
+		log.Fatal("sessions repo: ", err)
+	}
+	totpRepo, err := mysql.NewTOTP(TxDB)
+	if err != nil {
+		log.Fatal("totp repo: ", err)
+	}
+	smsRepo, err := mysql.NewSmsMessages(TxDB)
+	if err != nil {
+		log.Fatal("sms repo: ", err)
+	}
 	c := cache.NewCache(r)
 
-	phoneConf := phone.NewPhoneService(smsSender, c)
+	files, err := storage.NewStorage(cfg.Storage.Dir)
+	if err != nil {
+		log.Fatal("file storage: ", err)
+	}
 
-	authService := auth.NewAuthService(c, userRepo, phoneConf, cfg.AuthService)
-	userService := users.NewUsersService(userRepo, contactsRepo, chatsRepo, phoneConf)
+	smsSender, err := newSmsSender(smsRepo, cfg.Sms)
+	if err != nil {
+		log.Fatal("sms sender: ", err)
+	}
+	phoneConf := phone.NewPhoneService(smsSender, newMailSender(cfg.Mail), c, cfg.Phone)
+
+	authService, err := auth.NewAuthService(c, userRepo, sessionsRepo, totpRepo, phoneConf, cfg.AuthService)
+	if err != nil {
+		log.Fatal("auth service: ", err)
+	}
+	userService := users.NewUsersService(userRepo, contactsRepo, chatsRepo, groupsRepo, auditRepo, phoneConf, authService)
 	chatService := chats.NewChatService(chatsRepo, groupsRepo)
-	groupService := groups.NewGroupService(chatsRepo, groupsRepo)
-	messagesService := messages.NewMessagesService(messagesRepo, chatsRepo)
+	groupService := groups.NewGroupService(chatsRepo, groupsRepo, invitesRepo, messagesRepo, restrictionsRepo, auditRepo, files, cfg.Storage)
+	messagesService := messages.NewMessagesService(
+		messagesRepo,
+		chatsRepo,
+		groupsRepo,
+		bookmarksRepo,
+		stickersRepo,
+		restrictionsRepo,
+		c,
+		cfg.RateLimit,
+	)
+	connManager := messagesService.NewConnectionsManager()
+	groupService.SetEventsSender(connManager)
+	userService.SetEventsSender(connManager)
+	authService.SetDisconnector(connManager)
+	exportService := export.NewExportService(exportsRepo, messagesRepo, chatsRepo, userRepo, stickersRepo, cfg.Export)
+	importService := importer.NewImportService(importsRepo, userRepo, chatsRepo, groupsRepo, messagesRepo, cfg.Import)
+	stickersService := stickers.NewStickersService(stickersRepo, files, cfg.Storage)
 
 	h := http.NewHandler(
 		authService,
@@ -80,6 +159,10 @@ func Run(cfgPath string) {
 		messagesService,
 		chatService,
 		groupService,
+		exportService,
+		importService,
+		stickersService,
+		files,
 		errorsLogger,
 	)
 
@@ -97,3 +180,32 @@ func Run(cfgPath string) {
 		log.Fatal("http server error: ", err)
 	}
 }
+
+// newSmsSender prints sms to stdout when no providers are configured
+func newSmsSender(repo *mysql.SmsMessages, cfg *config.SmsConfig) (phone.SmsSender, error) {
+	if len(cfg.Providers) == 0 {
+		return cmdsms.NewCmdSmsAdapter(), nil
+	}
+	timeout := time.Duration(cfg.TimeoutSec) * time.Second
+	providers := make([]sms.Provider, 0, len(cfg.Providers))
+	for i := range cfg.Providers {
+		providerCfg := &cfg.Providers[i]
+		switch providerCfg.Type {
+		case "twilio":
+			providers = append(providers, twilio.NewTwilioClient(providerCfg, timeout))
+		case "smsru":
+			providers = append(providers, smsru.NewSmsRuClient(providerCfg, timeout))
+		default:
+			return nil, fmt.Errorf("unknown sms provider: %s", providerCfg.Type)
+		}
+	}
+	return sms.NewSmsService(repo, providers, cfg), nil
+}
+
+// newMailSender prints mails to stdout when SMTP server is not configured
+func newMailSender(cfg *config.MailConfig) ports.MailSender {
+	if len(cfg.Host) == 0 {
+		return cmdmail.NewCmdMailAdapter()
+	}
+	return smtp.NewSmtpSender(cfg)
+}
@@ -11,6 +11,13 @@ type Config struct {
 	AuthService *AuthServiceConfig `json:"auth_service" yaml:"auth_service"`
 	Redis       *RedisConfig       `json:"redis" yaml:"redis"`
 	MySQL       *MySQLConfig       `json:"mysql" yaml:"mysql"`
+	Export      *ExportConfig      `json:"export" yaml:"export"`
+	Import      *ImportConfig      `json:"import" yaml:"import"`
+	Storage     *StorageConfig     `json:"storage" yaml:"storage"`
+	RateLimit   *RateLimitConfig   `json:"rate_limit" yaml:"rate_limit"`
+	Phone       *PhoneConfig       `json:"phone" yaml:"phone"`
+	Sms         *SmsConfig         `json:"sms" yaml:"sms"`
+	Mail        *MailConfig        `json:"mail" yaml:"mail"`
 }
 
 type HttpServerConfig struct {
@@ -25,6 +32,12 @@ type AuthServiceConfig struct {
 	AccessTokenSignKey   string `json:"access_token_sign_key" yaml:"access_token_sign_key"`
 	DurationBlockUserMin int    `json:"duration_block_user_min" yaml:"duration_block_user_min"`
 	LoginAttempts        int    `json:"login_attempts" yaml:"login_attempts"`
+	// SigningAlg is HS512 (default, signed with AccessTokenSignKey), RS256 or EdDSA
+	SigningAlg string `json:"signing_alg" yaml:"signing_alg"`
+	// SigningKeysDir keeps private keys of RS256 and EdDSA
+	SigningKeysDir   string `json:"signing_keys_dir" yaml:"signing_keys_dir"`
+	KeyRotationHours int    `json:"key_rotation_hours" yaml:"key_rotation_hours"`
+	KeyOverlapMin    int    `json:"key_overlap_min" yaml:"key_overlap_min"`
 }
 
 type RedisConfig struct {
@@ -41,6 +54,66 @@ type MySQLConfig struct {
 	ConnectTimeoutSec int    `json:"connect_timeout_sec" yaml:"connect_timeout_sec"`
 }
 
+type ExportConfig struct {
+	// Dir must be shared between all instances, any of them can serve the download
+	Dir        string `json:"dir" yaml:"dir"`
+	LinkTTLMin int    `json:"link_ttl_min" yaml:"link_ttl_min"`
+}
+
+type ImportConfig struct {
+	AdminIds  []int `json:"admin_ids" yaml:"admin_ids"`
+	BatchSize int   `json:"batch_size" yaml:"batch_size"`
+}
+
+type StorageConfig struct {
+	Dir            string `json:"dir" yaml:"dir"`
+	MaxImageSizeKB int    `json:"max_image_size_kb" yaml:"max_image_size_kb"`
+}
+
+type RateLimitConfig struct {
+	UserMessagesPerMin int `json:"user_messages_per_min" yaml:"user_messages_per_min"`
+	UserMessagesBurst  int `json:"user_messages_burst" yaml:"user_messages_burst"`
+	ChatMessagesPerMin int `json:"chat_messages_per_min" yaml:"chat_messages_per_min"`
+	ChatMessagesBurst  int `json:"chat_messages_burst" yaml:"chat_messages_burst"`
+}
+
+type PhoneConfig struct {
+	CodeTTLSec int `json:"code_ttl_sec" yaml:"code_ttl_sec"`
+	// ResendIntervalSec is minimal interval between codes sent to phone, negative value disables the limit
+	ResendIntervalSec int `json:"resend_interval_sec" yaml:"resend_interval_sec"`
+	// MaxAttempts is number of invalid codes after which phone is locked for LockoutMin
+	MaxAttempts int `json:"max_attempts" yaml:"max_attempts"`
+	LockoutMin  int `json:"lockout_min" yaml:"lockout_min"`
+}
+
+type SmsConfig struct {
+	// Providers are tried in order, the next one is used when previous one fails.
+	// Without providers messages are printed to stdout
+	Providers           []SmsProviderConfig `json:"providers" yaml:"providers"`
+	AttemptsPerProvider int                 `json:"attempts_per_provider" yaml:"attempts_per_provider"`
+	RetryDelayMs        int                 `json:"retry_delay_ms" yaml:"retry_delay_ms"`
+	TimeoutSec          int                 `json:"timeout_sec" yaml:"timeout_sec"`
+}
+
+type SmsProviderConfig struct {
+	// Type is twilio or smsru
+	Type    string `json:"type" yaml:"type"`
+	BaseURL string `json:"base_url" yaml:"base_url"`
+	Account string `json:"account" yaml:"account"`
+	Token   string `json:"token" yaml:"token"`
+	From    string `json:"from" yaml:"from"`
+}
+
+type MailConfig struct {
+	// Host of SMTP server, without host mails are printed to stdout
+	Host       string `json:"host" yaml:"host"`
+	Port       int    `json:"port" yaml:"port"`
+	Username   string `json:"username" yaml:"username"`
+	Password   string `json:"password" yaml:"password"`
+	From       string `json:"from" yaml:"from"`
+	TimeoutSec int    `json:"timeout_sec" yaml:"timeout_sec"`
+}
+
 func GetConfig(path string) (*Config, error) {
 	cfg := new(Config)
 	if err := cleanenv.ReadConfig(path, cfg); err != nil {
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"messanger/domain/models"
+	"messanger/domain/service/groups"
+	"messanger/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) GetGroupAuditLog(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	dto := &groups.AuditLogDTO{
+		Action: r.Form.Get("action"),
+	}
+	dto.GroupId, _ = strconv.Atoi(r.Form.Get("group_id"))
+	dto.ActorId, _ = strconv.Atoi(r.Form.Get("actor_id"))
+	dto.TargetId, _ = strconv.Atoi(r.Form.Get("target_id"))
+	dto.LastId, _ = strconv.Atoi(r.Form.Get("last_id"))
+	dto.Count, _ = strconv.Atoi(r.Form.Get("count"))
+
+	entries, err := h.groups.GetAuditLog(r.Context(), dto)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, entries)
+}
@@ -3,7 +3,9 @@ package http
 import (
 	"messanger/domain/models"
 	"messanger/pkg/errors"
+	"net"
 	"net/http"
+	"strconv"
 )
 
 func (h *Handler) Login1FA(w http.ResponseWriter, r *http.Request) {
@@ -14,8 +16,9 @@ func (h *Handler) Login1FA(w http.ResponseWriter, r *http.Request) {
 
 	phone := r.Form.Get("phone")
 	password := r.Form.Get("password")
+	method := r.Form.Get("method")
 
-	err := h.auth.Login1FA(r.Context(), phone, password)
+	err := h.auth.Login1FA(r.Context(), phone, password, method)
 	if err != nil {
 		h.writeJSONError(w, err)
 		return
@@ -31,7 +34,7 @@ func (h *Handler) Login2FA(w http.ResponseWriter, r *http.Request) {
 	phone := r.Form.Get("phone")
 	code := r.Form.Get("code")
 
-	tokens, err := h.auth.Login2FA(r.Context(), phone, code)
+	tokens, err := h.auth.Login2FA(r.Context(), phone, code, clientInfo(r))
 	if err != nil {
 		h.writeJSONError(w, err)
 		return
@@ -46,7 +49,7 @@ func (h *Handler) UpdateTokens(w http.ResponseWriter, r *http.Request) {
 	}
 	refreshToken := r.Form.Get("refresh_token")
 
-	tokens, err := h.auth.UpdateTokens(r.Context(), refreshToken)
+	tokens, err := h.auth.UpdateTokens(r.Context(), refreshToken, clientInfo(r))
 	if err != nil {
 		h.writeJSONError(w, err)
 		return
@@ -54,3 +57,154 @@ func (h *Handler) UpdateTokens(w http.ResponseWriter, r *http.Request) {
 
 	h.writeJSON(w, http.StatusOK, tokens)
 }
+
+// clientInfo must be called after ParseForm
+func clientInfo(r *http.Request) *models.ClientInfo {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return &models.ClientInfo{
+		DeviceName: r.Form.Get("device_name"),
+		UserAgent:  r.UserAgent(),
+		IP:         ip,
+	}
+}
+
+func (h *Handler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.auth.GetSessions(r.Context())
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, sessions)
+}
+
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	sessionId, _ := strconv.Atoi(r.Form.Get("session_id"))
+
+	if err := h.auth.RevokeSession(r.Context(), sessionId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	if err := h.auth.RevokeOtherSessions(r.Context()); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	if err := h.auth.LogoutAll(r.Context()); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	enrollment, err := h.auth.EnrollTOTP(r.Context())
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, enrollment)
+}
+
+func (h *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+
+	codes, err := h.auth.ConfirmTOTP(r.Context(), r.Form.Get("code"))
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, codes)
+}
+
+func (h *Handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+
+	if err := h.auth.DisableTOTP(r.Context(), r.Form.Get("code")); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+
+	codes, err := h.auth.RegenerateRecoveryCodes(r.Context(), r.Form.Get("code"))
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, codes)
+}
+
+func (h *Handler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+
+	if err := h.auth.RequestPasswordReset(r.Context(), r.Form.Get("phone"), r.Form.Get("channel"), clientInfo(r)); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) VerifyPasswordReset(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+
+	token, err := h.auth.VerifyPasswordReset(r.Context(), r.Form.Get("phone"), r.Form.Get("code"), clientInfo(r))
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, token)
+}
+
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+
+	if err := h.auth.ResetPassword(r.Context(), r.Form.Get("reset_token"), r.Form.Get("new_password"), clientInfo(r)); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) GetSecurityEvents(w http.ResponseWriter, r *http.Request) {
+	events, err := h.auth.GetSecurityEvents(r.Context())
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, events)
+}
+
+// GetJWKS publishes public keys, so other services can verify access tokens
+func (h *Handler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	h.writeJSON(w, http.StatusOK, h.auth.JWKS())
+}
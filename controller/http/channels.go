@@ -0,0 +1,23 @@
+package http
+
+import (
+	"encoding/json"
+	"messanger/domain/models"
+	"messanger/domain/service/groups"
+	"messanger/pkg/errors"
+	"net/http"
+)
+
+func (h *Handler) CreateChannel(w http.ResponseWriter, r *http.Request) {
+	dto := new(groups.CreateChannelDTO)
+	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseJson, http.StatusBadRequest))
+		return
+	}
+	channel, err := h.groups.NewChannel(r.Context(), dto)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, channel)
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"path/filepath"
+	"strconv"
+)
+
+func (h *Handler) CreateExport(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	chatId, _ := strconv.Atoi(r.Form.Get("chat_id"))
+	format := r.Form.Get("format")
+
+	job, err := h.export.CreateExport(r.Context(), chatId, format)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, job)
+}
+
+func (h *Handler) GetExport(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	id := r.Form.Get("id")
+
+	job, err := h.export.GetExport(r.Context(), id)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, job)
+}
+
+func (h *Handler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	token := r.Form.Get("token")
+
+	path, err := h.export.GetExportFile(r.Context(), token)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", "attachment; filename=chat-export"+filepath.Ext(path))
+	http.ServeFile(w, r, path)
+}
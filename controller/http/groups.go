@@ -94,6 +94,24 @@ func (h *Handler) SetUsersRoleInGroup(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) SetGroupSlowMode(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+	seconds, e := strconv.Atoi(r.Form.Get("seconds"))
+	if e != nil {
+		h.writeJSONError(w, errors.New(e, "invalid seconds", http.StatusBadRequest))
+		return
+	}
+
+	if err := h.groups.SetSlowMode(r.Context(), groupId, seconds); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
 func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
@@ -121,3 +139,50 @@ func (h *Handler) GetUsersByGroup(w http.ResponseWriter, r *http.Request) {
 	}
 	h.writeJSON(w, http.StatusOK, u)
 }
+
+func (h *Handler) TransferGroupOwnership(w http.ResponseWriter, r *http.Request) {
+	req := new(userIdAndGroupIdRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseJson, http.StatusBadRequest))
+		return
+	}
+	if err := h.groups.TransferOwnership(r.Context(), req.GroupId, req.UserId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) SetGroupAvatar(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	image, _, e := r.FormFile("image")
+	if e != nil {
+		h.writeJSONError(w, errors.New(e, "missing image", http.StatusBadRequest))
+		return
+	}
+	defer image.Close()
+
+	group, err := h.groups.SetAvatar(r.Context(), groupId, image)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, group)
+}
+
+func (h *Handler) DeleteGroupAvatar(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	if err := h.groups.DeleteAvatar(r.Context(), groupId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
@@ -3,10 +3,14 @@ package http
 import (
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
+	"messanger/domain/ports"
 	"messanger/domain/service/auth"
 	"messanger/domain/service/chats"
+	"messanger/domain/service/export"
 	"messanger/domain/service/groups"
+	"messanger/domain/service/importer"
 	messages2 "messanger/domain/service/messages"
+	"messanger/domain/service/stickers"
 	"messanger/domain/service/users"
 	"messanger/pkg/errors"
 	"net/http"
@@ -25,6 +29,10 @@ type Handler struct {
 	messages *messages2.MessagesService
 	chats    *chats.ChatService
 	groups   *groups.GroupService
+	export   *export.ExportService
+	importer *importer.ImportService
+	stickers *stickers.StickersService
+	files    ports.FileStorage
 
 	logger Logger
 	info   *HttpLogger
@@ -39,6 +47,10 @@ func NewHandler(
 	messages *messages2.MessagesService,
 	chats *chats.ChatService,
 	groups *groups.GroupService,
+	export *export.ExportService,
+	importer *importer.ImportService,
+	stickers *stickers.StickersService,
+	files ports.FileStorage,
 
 	logger Logger,
 	// info io.Writer,
@@ -49,6 +61,10 @@ func NewHandler(
 		messages: messages,
 		chats:    chats,
 		groups:   groups,
+		export:   export,
+		importer: importer,
+		stickers: stickers,
+		files:    files,
 
 		logger:      logger,
 		info:        NewHttpLogger(),
@@ -69,13 +85,29 @@ func (h *Handler) InitRouter() {
 	h.router.HandleFunc("/register", h.MwLogging(h.Register)).Methods(http.MethodPost)
 	h.router.HandleFunc("/send-code", h.MwLogging(h.SendCode)).Methods(http.MethodPost) // if code not sent
 	h.router.HandleFunc("/confirm-phone", h.MwLogging(h.ConfirmPhone)).Methods(http.MethodPost)
+	h.router.HandleFunc("/.well-known/jwks.json", h.MwLogging(h.GetJWKS)).Methods(http.MethodGet)
 	h.router.HandleFunc("/auth/login/1fa", h.MwLogging(h.Login1FA)).Methods(http.MethodPost)
 	h.router.HandleFunc("/auth/login/2fa", h.MwLogging(h.Login2FA)).Methods(http.MethodPost)
 	h.router.HandleFunc("/auth/refresh-tokens", h.MwLogging(h.UpdateTokens)).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/password-reset/request", h.MwLogging(h.RequestPasswordReset)).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/password-reset/verify", h.MwLogging(h.VerifyPasswordReset)).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/password-reset/confirm", h.MwLogging(h.ResetPassword)).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/sessions", h.MwLogging(h.MwWithAuth(h.GetSessions))).Methods(http.MethodGet)
+	h.router.HandleFunc("/auth/sessions/revoke", h.MwLogging(h.MwWithAuth(h.RevokeSession))).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/sessions/revoke-others", h.MwLogging(h.MwWithAuth(h.RevokeOtherSessions))).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/logout-all", h.MwLogging(h.MwWithAuth(h.LogoutAll))).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/totp/enroll", h.MwLogging(h.MwWithAuth(h.EnrollTOTP))).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/totp/confirm", h.MwLogging(h.MwWithAuth(h.ConfirmTOTP))).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/totp/disable", h.MwLogging(h.MwWithAuth(h.DisableTOTP))).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/totp/recovery-codes", h.MwLogging(h.MwWithAuth(h.RegenerateRecoveryCodes))).Methods(http.MethodPost)
+	h.router.HandleFunc("/auth/security-events", h.MwLogging(h.MwWithAuth(h.GetSecurityEvents))).Methods(http.MethodGet)
 
 	h.router.HandleFunc("/self/update", h.MwLogging(h.MwWithAuth(h.UpdateUser))).Methods(http.MethodPost)
 	h.router.HandleFunc("/self/delete", h.MwLogging(h.MwWithAuth(h.DeleteUser))).Methods(http.MethodPost)
 	h.router.HandleFunc("/self/set-show-phone", h.MwLogging(h.MwWithAuth(h.SetShowPhone))).Methods(http.MethodPost)
+	h.router.HandleFunc("/self/email/update", h.MwLogging(h.MwWithAuth(h.UpdateEmail))).Methods(http.MethodPost)
+	h.router.HandleFunc("/self/email/send-code", h.MwLogging(h.MwWithAuth(h.SendEmailCode))).Methods(http.MethodPost)
+	h.router.HandleFunc("/self/email/confirm", h.MwLogging(h.MwWithAuth(h.ConfirmEmail))).Methods(http.MethodPost)
 
 	h.router.HandleFunc("/users/check-username", h.MwLogging(h.CheckUsername)).Methods(http.MethodGet)
 	h.router.HandleFunc("/users/status", h.MwLogging(h.MwWithAuth(h.CheckOnline))).Methods(http.MethodGet)
@@ -91,6 +123,39 @@ func (h *Handler) InitRouter() {
 	h.router.HandleFunc("/groups/add-user", h.MwLogging(h.MwWithAuth(h.AddUserToGroup))).Methods(http.MethodPost)
 	h.router.HandleFunc("/groups/delete-user", h.MwLogging(h.MwWithAuth(h.DeleteUserFromGroup))).Methods(http.MethodPost)
 	h.router.HandleFunc("/groups/set-role", h.MwLogging(h.MwWithAuth(h.SetUsersRoleInGroup))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/set-slow-mode", h.MwLogging(h.MwWithAuth(h.SetGroupSlowMode))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/set-avatar", h.MwLogging(h.MwWithAuth(h.SetGroupAvatar))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/delete-avatar", h.MwLogging(h.MwWithAuth(h.DeleteGroupAvatar))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/transfer-ownership", h.MwLogging(h.MwWithAuth(h.TransferGroupOwnership))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/check-username", h.MwLogging(h.MwWithAuth(h.CheckGroupUsername))).Methods(http.MethodGet)
+	h.router.HandleFunc("/groups/set-username", h.MwLogging(h.MwWithAuth(h.SetGroupUsername))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/find", h.MwLogging(h.MwWithAuth(h.FindPublicGroup))).Methods(http.MethodGet)
+	h.router.HandleFunc("/groups/search", h.MwLogging(h.MwWithAuth(h.SearchPublicGroups))).Methods(http.MethodGet)
+	h.router.HandleFunc("/groups/preview", h.MwLogging(h.MwWithAuth(h.PreviewPublicGroup))).Methods(http.MethodGet)
+	h.router.HandleFunc("/groups/join-public", h.MwLogging(h.MwWithAuth(h.JoinPublicGroup))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/leave", h.MwLogging(h.MwWithAuth(h.LeaveGroup))).Methods(http.MethodPost)
+	h.router.HandleFunc("/channels/create", h.MwLogging(h.MwWithAuth(h.CreateChannel))).Methods(http.MethodPost)
+
+	h.router.HandleFunc("/groups/roles/get", h.MwLogging(h.MwWithAuth(h.GetGroupRoles))).Methods(http.MethodGet)
+	h.router.HandleFunc("/groups/roles/create", h.MwLogging(h.MwWithAuth(h.CreateGroupRole))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/roles/update", h.MwLogging(h.MwWithAuth(h.UpdateGroupRole))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/roles/delete", h.MwLogging(h.MwWithAuth(h.DeleteGroupRole))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/set-permissions", h.MwLogging(h.MwWithAuth(h.SetMemberPermissions))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/get-permissions", h.MwLogging(h.MwWithAuth(h.GetMyPermissions))).Methods(http.MethodGet)
+	h.router.HandleFunc("/groups/invites/create", h.MwLogging(h.MwWithAuth(h.CreateGroupInvite))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/invites/get", h.MwLogging(h.MwWithAuth(h.GetGroupInvites))).Methods(http.MethodGet)
+	h.router.HandleFunc("/groups/invites/revoke", h.MwLogging(h.MwWithAuth(h.RevokeGroupInvite))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/join", h.MwLogging(h.MwWithAuth(h.JoinGroupByInvite))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/join-requests/get", h.MwLogging(h.MwWithAuth(h.GetJoinRequests))).Methods(http.MethodGet)
+	h.router.HandleFunc("/groups/join-requests/approve", h.MwLogging(h.MwWithAuth(h.ApproveJoinRequest))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/join-requests/reject", h.MwLogging(h.MwWithAuth(h.RejectJoinRequest))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/bans/create", h.MwLogging(h.MwWithAuth(h.BanGroupUser))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/bans/delete", h.MwLogging(h.MwWithAuth(h.UnbanGroupUser))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/bans/get", h.MwLogging(h.MwWithAuth(h.GetGroupBans))).Methods(http.MethodGet)
+	h.router.HandleFunc("/groups/mutes/create", h.MwLogging(h.MwWithAuth(h.MuteGroupUser))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/mutes/delete", h.MwLogging(h.MwWithAuth(h.UnmuteGroupUser))).Methods(http.MethodPost)
+	h.router.HandleFunc("/groups/mutes/get", h.MwLogging(h.MwWithAuth(h.GetGroupMutes))).Methods(http.MethodGet)
+	h.router.HandleFunc("/groups/audit-log", h.MwLogging(h.MwWithAuth(h.GetGroupAuditLog))).Methods(http.MethodGet)
 
 	h.router.HandleFunc("/contacts/add", h.MwLogging(h.MwWithAuth(h.AddContact))).Methods(http.MethodPost)
 	h.router.HandleFunc("/contacts/rename", h.MwLogging(h.MwWithAuth(h.RenameContact))).Methods(http.MethodPost)
@@ -103,6 +168,27 @@ func (h *Handler) InitRouter() {
 	h.router.HandleFunc("/messages/get-by-chat", h.MwLogging(h.MwWithAuth(h.GetMessages))).Methods(http.MethodGet)
 	h.router.HandleFunc("/messages/min-id-in-chat", h.MwLogging(h.MwWithAuth(h.GetMinMassageIdInChat))).Methods(http.MethodGet)
 	h.router.HandleFunc("/messages/ws", h.MwLogging(h.MwWithAuth(h.HandleWS)))
+
+	h.router.HandleFunc("/bookmarks/add", h.MwLogging(h.MwWithAuth(h.AddBookmark))).Methods(http.MethodPost)
+	h.router.HandleFunc("/bookmarks/get-all", h.MwLogging(h.MwWithAuth(h.GetBookmarks))).Methods(http.MethodGet)
+	h.router.HandleFunc("/bookmarks/delete", h.MwLogging(h.MwWithAuth(h.DeleteBookmark))).Methods(http.MethodPost)
+
+	h.router.HandleFunc("/stickers/create-pack", h.MwLogging(h.MwWithAuth(h.CreateStickerPack))).Methods(http.MethodPost)
+	h.router.HandleFunc("/stickers/get-pack", h.MwLogging(h.MwWithAuth(h.GetStickerPack))).Methods(http.MethodGet)
+	h.router.HandleFunc("/stickers/delete-pack", h.MwLogging(h.MwWithAuth(h.DeleteStickerPack))).Methods(http.MethodPost)
+	h.router.HandleFunc("/stickers/add", h.MwLogging(h.MwWithAuth(h.AddSticker))).Methods(http.MethodPost)
+	h.router.HandleFunc("/stickers/delete", h.MwLogging(h.MwWithAuth(h.DeleteSticker))).Methods(http.MethodPost)
+	h.router.HandleFunc("/stickers/install", h.MwLogging(h.MwWithAuth(h.InstallStickerPack))).Methods(http.MethodPost)
+	h.router.HandleFunc("/stickers/uninstall", h.MwLogging(h.MwWithAuth(h.UninstallStickerPack))).Methods(http.MethodPost)
+	h.router.HandleFunc("/stickers/get-my", h.MwLogging(h.MwWithAuth(h.GetInstalledStickerPacks))).Methods(http.MethodGet)
+
+	h.router.HandleFunc("/files/{name}", h.MwLogging(h.GetFile)).Methods(http.MethodGet)
+
+	h.router.HandleFunc("/exports/create", h.MwLogging(h.MwWithAuth(h.CreateExport))).Methods(http.MethodPost)
+	h.router.HandleFunc("/exports/get", h.MwLogging(h.MwWithAuth(h.GetExport))).Methods(http.MethodGet)
+	h.router.HandleFunc("/exports/download", h.MwLogging(h.DownloadExport)).Methods(http.MethodGet)
+
+	h.router.HandleFunc("/admin/import", h.MwLogging(h.MwWithAuth(h.ImportHistory))).Methods(http.MethodPost)
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
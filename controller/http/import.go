@@ -0,0 +1,23 @@
+package http
+
+import (
+	"encoding/json"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+)
+
+func (h *Handler) ImportHistory(w http.ResponseWriter, r *http.Request) {
+	data := new(models.ImportData)
+	if err := json.NewDecoder(r.Body).Decode(data); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseJson, http.StatusBadRequest))
+		return
+	}
+
+	report, err := h.importer.Import(r.Context(), data)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"messanger/domain/models"
+	"messanger/domain/service/groups"
+	"messanger/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) CreateGroupInvite(w http.ResponseWriter, r *http.Request) {
+	dto := new(groups.CreateInviteDTO)
+	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseJson, http.StatusBadRequest))
+		return
+	}
+	invite, err := h.groups.CreateInvite(r.Context(), dto)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, invite)
+}
+
+func (h *Handler) GetGroupInvites(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	invites, err := h.groups.GetInvites(r.Context(), groupId)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, invites)
+}
+
+func (h *Handler) RevokeGroupInvite(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	inviteId, _ := strconv.Atoi(r.Form.Get("invite_id"))
+
+	if err := h.groups.RevokeInvite(r.Context(), inviteId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) JoinGroupByInvite(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	resp, err := h.groups.JoinByInvite(r.Context(), r.Form.Get("token"))
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) GetJoinRequests(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	requests, err := h.groups.GetJoinRequests(r.Context(), groupId)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, requests)
+}
+
+func (h *Handler) ApproveJoinRequest(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	requestId, _ := strconv.Atoi(r.Form.Get("request_id"))
+
+	if err := h.groups.ApproveJoinRequest(r.Context(), requestId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) RejectJoinRequest(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	requestId, _ := strconv.Atoi(r.Form.Get("request_id"))
+
+	if err := h.groups.RejectJoinRequest(r.Context(), requestId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
@@ -92,3 +92,40 @@ func (h *Handler) GetMinMassageIdInChat(w http.ResponseWriter, r *http.Request)
 		Id: messageId,
 	})
 }
+
+func (h *Handler) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	messageId, _ := strconv.Atoi(r.Form.Get("message_id"))
+
+	bookmark, err := h.messages.AddBookmark(r.Context(), messageId)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, bookmark)
+}
+
+func (h *Handler) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	bookmarks, err := h.messages.GetBookmarks(r.Context())
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, bookmarks)
+}
+
+func (h *Handler) DeleteBookmark(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	messageId, _ := strconv.Atoi(r.Form.Get("message_id"))
+
+	if err := h.messages.RemoveBookmark(r.Context(), messageId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
@@ -22,7 +22,7 @@ func (h *Handler) MwWithAuth(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 		token := typeAndToken[1]
-		userId, err := h.auth.DecodeAccessToken(token)
+		userId, sessionId, err := h.auth.DecodeAccessToken(ctx, token)
 		if err != nil {
 			h.writeJSONError(w, err)
 			return
@@ -30,7 +30,7 @@ func (h *Handler) MwWithAuth(next http.HandlerFunc) http.HandlerFunc {
 
 		//user, _ := h.users.GetById(ctx, userId)
 
-		r = r.WithContext(auth.CtxWithUser(ctx, userId))
+		r = r.WithContext(auth.CtxWithSession(auth.CtxWithUser(ctx, userId), sessionId))
 
 		next(w, r)
 	}
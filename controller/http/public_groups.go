@@ -0,0 +1,103 @@
+package http
+
+import (
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) CheckGroupUsername(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	exist, err := h.groups.CheckUsername(r.Context(), r.Form.Get("username"))
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, CheckUsernameResponse{Exist: exist})
+}
+
+func (h *Handler) SetGroupUsername(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	if err := h.groups.SetUsername(r.Context(), groupId, r.Form.Get("username")); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) FindPublicGroup(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	group, err := h.groups.FindPublic(r.Context(), r.Form.Get("username"))
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, group)
+}
+
+func (h *Handler) SearchPublicGroups(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	limit, _ := strconv.Atoi(r.Form.Get("limit"))
+
+	groups, err := h.groups.Search(r.Context(), r.Form.Get("query"), limit)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, groups)
+}
+
+func (h *Handler) PreviewPublicGroup(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	count, _ := strconv.Atoi(r.Form.Get("count"))
+
+	messages, err := h.messages.GetPublicPreview(r.Context(), r.Form.Get("username"), count)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, messages)
+}
+
+func (h *Handler) JoinPublicGroup(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	group, err := h.groups.JoinPublic(r.Context(), r.Form.Get("username"))
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, group)
+}
+
+func (h *Handler) LeaveGroup(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	if err := h.groups.Leave(r.Context(), groupId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"encoding/json"
+	"messanger/domain/models"
+	"messanger/domain/service/groups"
+	"messanger/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) BanGroupUser(w http.ResponseWriter, r *http.Request) {
+	dto := new(groups.RestrictionDTO)
+	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseJson, http.StatusBadRequest))
+		return
+	}
+	ban, err := h.groups.BanUser(r.Context(), dto)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, ban)
+}
+
+func (h *Handler) UnbanGroupUser(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+	userId, _ := strconv.Atoi(r.Form.Get("user_id"))
+
+	if err := h.groups.UnbanUser(r.Context(), groupId, userId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) GetGroupBans(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	bans, err := h.groups.GetBans(r.Context(), groupId)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, bans)
+}
+
+func (h *Handler) MuteGroupUser(w http.ResponseWriter, r *http.Request) {
+	dto := new(groups.RestrictionDTO)
+	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseJson, http.StatusBadRequest))
+		return
+	}
+	mute, err := h.groups.MuteUser(r.Context(), dto)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, mute)
+}
+
+func (h *Handler) UnmuteGroupUser(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+	userId, _ := strconv.Atoi(r.Form.Get("user_id"))
+
+	if err := h.groups.UnmuteUser(r.Context(), groupId, userId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) GetGroupMutes(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	mutes, err := h.groups.GetMutes(r.Context(), groupId)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, mutes)
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/json"
+	"messanger/domain/models"
+	"messanger/domain/service/groups"
+	"messanger/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) GetGroupRoles(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	roles, err := h.groups.GetRoles(r.Context(), groupId)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, roles)
+}
+
+func (h *Handler) CreateGroupRole(w http.ResponseWriter, r *http.Request) {
+	dto := new(groups.RoleDTO)
+	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseJson, http.StatusBadRequest))
+		return
+	}
+	role, err := h.groups.CreateRole(r.Context(), dto)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, role)
+}
+
+func (h *Handler) UpdateGroupRole(w http.ResponseWriter, r *http.Request) {
+	dto := new(groups.RoleDTO)
+	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseJson, http.StatusBadRequest))
+		return
+	}
+	if err := h.groups.UpdateRole(r.Context(), dto); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) DeleteGroupRole(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	if err := h.groups.DeleteRole(r.Context(), groupId, r.Form.Get("name")); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) SetMemberPermissions(w http.ResponseWriter, r *http.Request) {
+	dto := new(groups.PermissionOverrideDTO)
+	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseJson, http.StatusBadRequest))
+		return
+	}
+	if err := h.groups.SetPermissionOverride(r.Context(), dto); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+type getPermissionsResponse struct {
+	Permissions models.Permissions `json:"permissions"`
+}
+
+func (h *Handler) GetMyPermissions(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	groupId, _ := strconv.Atoi(r.Form.Get("group_id"))
+
+	perms, err := h.groups.GetPermissions(r.Context(), groupId)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, getPermissionsResponse{Permissions: perms})
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type createStickerPackRequest struct {
+	Name string `json:"name"`
+}
+
+func (h *Handler) CreateStickerPack(w http.ResponseWriter, r *http.Request) {
+	req := new(createStickerPackRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseJson, http.StatusBadRequest))
+		return
+	}
+	pack, err := h.stickers.CreatePack(r.Context(), req.Name)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, pack)
+}
+
+func (h *Handler) GetStickerPack(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	packId, _ := strconv.Atoi(r.Form.Get("pack_id"))
+
+	pack, err := h.stickers.GetPack(r.Context(), packId)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, pack)
+}
+
+func (h *Handler) DeleteStickerPack(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	packId, _ := strconv.Atoi(r.Form.Get("pack_id"))
+
+	if err := h.stickers.DeletePack(r.Context(), packId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) AddSticker(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	packId, _ := strconv.Atoi(r.Form.Get("pack_id"))
+	emoji := r.Form.Get("emoji")
+
+	image, _, e := r.FormFile("image")
+	if e != nil {
+		h.writeJSONError(w, errors.New(e, "missing image", http.StatusBadRequest))
+		return
+	}
+	defer image.Close()
+
+	sticker, err := h.stickers.AddSticker(r.Context(), packId, emoji, image)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, sticker)
+}
+
+func (h *Handler) DeleteSticker(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	stickerId, _ := strconv.Atoi(r.Form.Get("sticker_id"))
+
+	if err := h.stickers.DeleteSticker(r.Context(), stickerId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) InstallStickerPack(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	packId, _ := strconv.Atoi(r.Form.Get("pack_id"))
+
+	if err := h.stickers.InstallPack(r.Context(), packId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) UninstallStickerPack(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	packId, _ := strconv.Atoi(r.Form.Get("pack_id"))
+
+	if err := h.stickers.UninstallPack(r.Context(), packId); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) GetInstalledStickerPacks(w http.ResponseWriter, r *http.Request) {
+	packs, err := h.stickers.GetInstalledPacks(r.Context())
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, packs)
+}
+
+func (h *Handler) GetFile(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	file, err := h.files.Open(r.Context(), name)
+	if err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+	defer file.Close()
+
+	http.ServeContent(w, r, name, time.Time{}, file)
+}
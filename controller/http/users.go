@@ -71,8 +71,9 @@ func (h *Handler) SendCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	phone := r.Form.Get("phone")
+	purpose := r.Form.Get("purpose")
 
-	if err := h.users.SendCode(r.Context(), phone); err != nil {
+	if err := h.users.SendCode(r.Context(), phone, purpose); err != nil {
 		h.writeJSONError(w, err)
 		return
 	}
@@ -85,8 +86,9 @@ func (h *Handler) ConfirmPhone(w http.ResponseWriter, r *http.Request) {
 	}
 	phone := r.Form.Get("phone")
 	code := r.Form.Get("code")
+	purpose := r.Form.Get("purpose")
 
-	if err := h.users.ConfirmPhone(r.Context(), phone, code); err != nil {
+	if err := h.users.ConfirmPhone(r.Context(), phone, code, purpose); err != nil {
 		h.writeJSONError(w, err)
 		return
 	}
@@ -126,6 +128,35 @@ func (h *Handler) SetShowPhone(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) UpdateEmail(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	if err := h.users.UpdateEmail(r.Context(), r.Form.Get("email")); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) SendEmailCode(w http.ResponseWriter, r *http.Request) {
+	if err := h.users.SendEmailCode(r.Context()); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
+func (h *Handler) ConfirmEmail(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeJSONError(w, errors.New(err, models.ErrParseForm, http.StatusBadRequest))
+		return
+	}
+	if err := h.users.ConfirmEmail(r.Context(), r.Form.Get("code")); err != nil {
+		h.writeJSONError(w, err)
+		return
+	}
+}
+
 func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	if err := h.users.DeleteUser(r.Context()); err != nil {
 		h.writeJSONError(w, err)
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"messanger/pkg/errors"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 func (h *Handler) writeJSON(w http.ResponseWriter, code int, v any) {
@@ -15,6 +17,8 @@ func (h *Handler) writeJSON(w http.ResponseWriter, code int, v any) {
 	}
 }
 
+const maxMultipartMemory = 8 << 20
+
 type responseError struct {
 	Error string `json:"error"`
 }
@@ -23,9 +27,16 @@ func (h *Handler) writeJSONError(w http.ResponseWriter, err *errors.Error) {
 	h.logger.Println(err.Error())
 
 	w.Header().Set("Content-Type", "application/json")
+	if err.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSec(err.RetryAfter)))
+	}
 	w.WriteHeader(err.Code)
 
 	if err := json.NewEncoder(w).Encode(responseError{err.UserMessage}); err != nil {
 		h.logger.Println(errors.Trace(err))
 	}
 }
+
+func retryAfterSec(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
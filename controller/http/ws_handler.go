@@ -1,20 +1,32 @@
 package http
 
 import (
+	"context"
 	"fmt"
 	"github.com/gorilla/websocket"
 	"messanger/domain/service/auth"
 	"messanger/domain/service/messages"
 	"messanger/pkg/errors"
 	"net/http"
+	"sync"
+	"time"
 )
 
 type WsConnAdapter struct {
-	conn   *websocket.Conn
-	logger Logger
+	conn      *websocket.Conn
+	sessionId int
+	logger    Logger
+	mu        sync.Mutex
+}
+
+func (c *WsConnAdapter) SessionId() int {
+	return c.sessionId
 }
 
 func (c *WsConnAdapter) Ping() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if err := c.conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
 		c.logger.Println(errors.Trace(fmt.Errorf("ws: ping error: %w", err)))
 		return false
@@ -23,13 +35,31 @@ func (c *WsConnAdapter) Ping() bool {
 }
 
 func (c *WsConnAdapter) Send(event *messages.Event) bool {
-	if err := c.conn.WriteJSON(event); err != nil {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := event.JSON()
+	if err != nil {
+		c.logger.Println(errors.Trace(fmt.Errorf("ws: encode event error: %w", err)))
+		return true
+	}
+	if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
 		c.logger.Println(errors.Trace(fmt.Errorf("ws: write json error: %w", err)))
 		return false
 	}
 	return true
 }
 
+// Close sends close frame and closes connection, client has to reconnect with new token
+func (c *WsConnAdapter) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "tokens revoked")
+	c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	c.conn.Close()
+}
+
 type WsHandler struct {
 	connManager *messages.ConnectionsManager
 	wsUpgrader  *websocket.Upgrader
@@ -46,11 +76,60 @@ func (h *Handler) HandleWS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	userId := auth.ExtractUser(r.Context())
+	sessionId := auth.ExtractSession(r.Context())
 
-	if err := h.connManager.InsertConn(r.Context(), userId, &WsConnAdapter{
-		conn:   conn,
-		logger: h.logger,
-	}); err != nil {
+	wsConn := &WsConnAdapter{
+		conn:      conn,
+		sessionId: sessionId,
+		logger:    h.logger,
+	}
+	if err := h.connManager.InsertConn(r.Context(), userId, wsConn); err != nil {
 		h.writeJSONError(w, err)
+		return
+	}
+
+	// request context is canceled after return from handler
+	go h.readWS(auth.CtxWithSession(auth.CtxWithUser(context.Background(), userId), sessionId), wsConn)
+}
+
+type wsRequest struct {
+	Type    string                    `json:"type"`
+	Message messages.CreateMessageDTO `json:"message"`
+}
+
+type wsError struct {
+	Error      string `json:"error"`
+	RetryAfter int    `json:"retry_after,omitempty"`
+}
+
+func (h *Handler) readWS(ctx context.Context, conn *WsConnAdapter) {
+	for {
+		req := new(wsRequest)
+		if err := conn.conn.ReadJSON(req); err != nil {
+			if _, ok := err.(*websocket.CloseError); !ok {
+				h.logger.Println(errors.Trace(fmt.Errorf("ws: read json error: %w", err)))
+			}
+			return
+		}
+
+		switch req.Type {
+		case messages.EventTypeCreate:
+			if err := h.messages.CreateMessage(ctx, &req.Message); err != nil {
+				h.logger.Println(err.Error())
+				conn.Send(&messages.Event{
+					Type:   messages.EventTypeError,
+					ChatId: req.Message.ChatId,
+					Data: wsError{
+						Error:      err.UserMessage,
+						RetryAfter: retryAfterSec(err.RetryAfter),
+					},
+				})
+			}
+		default:
+			conn.Send(&messages.Event{
+				Type: messages.EventTypeError,
+				Data: wsError{Error: "unknown request type: " + req.Type},
+			})
+		}
 	}
 }
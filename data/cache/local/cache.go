@@ -3,11 +3,13 @@ package cache
 import (
 	"context"
 	"messanger/pkg/errors"
+	"sync"
 	"time"
 )
 
 type Cache struct {
-	c map[string]value
+	c  map[string]value
+	mu sync.Mutex
 }
 
 type value struct {
@@ -24,11 +26,13 @@ func NewCache() *Cache {
 		for {
 			time.Sleep(time.Minute * 5)
 			now := time.Now()
+			c.mu.Lock()
 			for k, v := range c.c {
 				if now.After(v.t) {
 					delete(c.c, k)
 				}
 			}
+			c.mu.Unlock()
 		}
 	}()
 
@@ -36,6 +40,9 @@ func NewCache() *Cache {
 }
 
 func (c *Cache) Set(_ context.Context, key string, v int, ttl time.Duration) *errors.Error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.c[key] = value{
 		v: v,
 		t: time.Now().Add(ttl),
@@ -44,27 +51,54 @@ func (c *Cache) Set(_ context.Context, key string, v int, ttl time.Duration) *er
 }
 
 func (c *Cache) Get(_ context.Context, key string) (int, *errors.Error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.get(key), nil
+}
+
+func (c *Cache) get(key string) int {
 	v, ok := c.c[key]
 	if ok {
 		if time.Now().Before(v.t) {
-			return v.v, nil
+			return v.v
 		} else {
 			delete(c.c, key)
 		}
 	}
 
-	return 0, nil
+	return 0
 }
 
 func (c *Cache) Del(_ context.Context, key string) *errors.Error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	delete(c.c, key)
 	return nil
 }
 
 func (c *Cache) TTL(_ context.Context, key string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	v, ok := c.c[key]
 	if ok {
 		return v.t.Sub(time.Now())
 	}
 	return 0
 }
+
+func (c *Cache) CompareAndSet(_ context.Context, key string, old int, v int, ttl time.Duration) (bool, *errors.Error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.get(key) != old {
+		return false, nil
+	}
+	c.c[key] = value{
+		v: v,
+		t: time.Now().Add(ttl),
+	}
+	return true, nil
+}
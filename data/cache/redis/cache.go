@@ -50,3 +50,20 @@ func (c *Cache) Del(ctx context.Context, key string) *errors.Error {
 func (c *Cache) TTL(ctx context.Context, key string) time.Duration {
 	return c.client.TTL(ctx, key).Val()
 }
+
+var compareAndSetScript = redis.NewScript(`
+local v = redis.call("GET", KEYS[1]) or "0"
+if v ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+
+func (c *Cache) CompareAndSet(ctx context.Context, key string, old int, v int, ttl time.Duration) (bool, *errors.Error) {
+	ok, err := compareAndSetScript.Run(ctx, c.client, []string{key}, old, v, max(ttl.Milliseconds(), 1)).Int()
+	if err != nil {
+		return false, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return ok == 1, nil
+}
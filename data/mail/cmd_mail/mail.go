@@ -0,0 +1,19 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"messanger/pkg/errors"
+)
+
+type CmdMailAdapter struct {
+}
+
+func NewCmdMailAdapter() *CmdMailAdapter {
+	return &CmdMailAdapter{}
+}
+
+func (m *CmdMailAdapter) Send(_ context.Context, to, subject, body string) *errors.Error {
+	fmt.Printf("Mail Sender: to: %s, subject: %s, body: %s\n", to, subject, body)
+	return nil
+}
@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"messanger/pkg/errors"
+	"regexp"
+)
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type MailChan struct {
+	Chan chan Mail
+}
+
+func NewMailChan() *MailChan {
+	return &MailChan{
+		Chan: make(chan Mail, 10),
+	}
+}
+
+func (m *MailChan) Send(_ context.Context, to, subject, body string) *errors.Error {
+	m.Chan <- Mail{To: to, Subject: subject, Body: body}
+	fmt.Println(to, subject, body)
+	return nil
+}
+
+var codeRegexp = regexp.MustCompile(`\d+`)
+
+// Code waits for next mail and returns code from its body
+func (m *MailChan) Code() string {
+	return codeRegexp.FindString((<-m.Chan).Body)
+}
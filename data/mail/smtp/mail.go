@@ -0,0 +1,116 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"messanger/config"
+	"messanger/pkg/errors"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+const ErrSmtp = "send mail error"
+
+// SmtpSender sends plain text mails with SMTP server, STARTTLS is used when server supports it
+type SmtpSender struct {
+	host     string
+	addr     string
+	username string
+	password string
+	from     string
+	timeout  time.Duration
+}
+
+func NewSmtpSender(cfg *config.MailConfig) *SmtpSender {
+	return &SmtpSender{
+		host:     cfg.Host,
+		addr:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		username: cfg.Username,
+		password: cfg.Password,
+		from:     cfg.From,
+		timeout:  time.Duration(cfg.TimeoutSec) * time.Second,
+	}
+}
+
+func (s *SmtpSender) Send(ctx context.Context, to, subject, body string) *errors.Error {
+	msg, err := s.message(to, subject, body)
+	if err != nil {
+		return errors.New(err, ErrSmtp, http.StatusInternalServerError)
+	}
+	if err := s.send(ctx, to, msg); err != nil {
+		return errors.New(err, ErrSmtp, http.StatusServiceUnavailable)
+	}
+	return nil
+}
+
+func (s *SmtpSender) send(ctx context.Context, to string, msg []byte) error {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if s.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.timeout))
+	}
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if len(s.username) != 0 {
+		if err := c.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (s *SmtpSender) message(to, subject, body string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
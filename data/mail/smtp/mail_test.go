@@ -0,0 +1,119 @@
+package mail
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/stretchr/testify/require"
+	"io"
+	"messanger/config"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+type received struct {
+	auth string
+	from string
+	to   string
+	data string
+}
+
+// serveSMTP is SMTP stand-in which accepts one mail and rejects recipients in rejected
+func serveSMTP(t *testing.T, l net.Listener, rejected string, res chan<- received) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	c := textproto.NewConn(conn)
+	var r received
+	c.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			c.PrintfLine("250-localhost")
+			c.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			_, resp, _ := strings.Cut(arg, " ")
+			auth, _ := base64.StdEncoding.DecodeString(resp)
+			r.auth = string(auth)
+			c.PrintfLine("235 authenticated")
+		case "MAIL":
+			r.from = arg
+			c.PrintfLine("250 ok")
+		case "RCPT":
+			if strings.Contains(arg, rejected) {
+				c.PrintfLine("550 no such user")
+				continue
+			}
+			r.to = arg
+			c.PrintfLine("250 ok")
+		case "DATA":
+			c.PrintfLine("354 go ahead")
+			data, err := c.ReadDotBytes()
+			require.NoError(t, err)
+			r.data = string(data)
+			c.PrintfLine("250 queued")
+			res <- r
+		case "QUIT":
+			c.PrintfLine("221 bye")
+			return
+		default:
+			c.PrintfLine("250 ok")
+		}
+	}
+}
+
+func newTestSender(t *testing.T, rejected string) (*SmtpSender, chan received) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	res := make(chan received, 1)
+	go serveSMTP(t, l, rejected, res)
+
+	port := l.Addr().(*net.TCPAddr).Port
+	return NewSmtpSender(&config.MailConfig{
+		Host:       "localhost",
+		Port:       port,
+		Username:   "user",
+		Password:   "secret",
+		From:       "noreply@messanger.test",
+		TimeoutSec: 5,
+	}), res
+}
+
+func TestSend(t *testing.T) {
+	sender, res := newTestSender(t, "nobody")
+	require.Nil(t, sender.Send(context.Background(), "user@example.com", "Ваш код", "012345 - код"))
+
+	r := <-res
+	require.Equal(t, "\x00user\x00secret", r.auth)
+	require.Equal(t, "FROM:<noreply@messanger.test>", r.from)
+	require.Equal(t, "TO:<user@example.com>", r.to)
+
+	msg, err := mail.ReadMessage(strings.NewReader(r.data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Ваш код", subject)
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	require.NoError(t, err)
+	require.Equal(t, "012345 - код", strings.TrimSpace(string(body)))
+}
+
+func TestSendRejected(t *testing.T) {
+	sender, _ := newTestSender(t, "nobody")
+	err := sender.Send(context.Background(), "nobody@example.com", "subject", "body")
+	require.NotNil(t, err)
+}
@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+	errorsutils "errors"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Audit stores group audit log, entries have no foreign key to group so they are kept after group is deleted
+type Audit struct {
+	DB
+}
+
+func NewAudit(db DB) (*Audit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_group_audit.sql"); err != nil {
+		return nil, errorsutils.New("create table group_audit error: " + err.Error())
+	}
+	return &Audit{db}, nil
+}
+
+func (a *Audit) New(ctx context.Context, entry *models.AuditEntry) *errors.Error {
+	entry.CreateTime = time.Now()
+	res, err := a.DB.ExecContext(ctx, "INSERT INTO group_audit (group_id, actor_id, action, target_id, value_before, value_after, create_time) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		entry.GroupId, entry.ActorId, entry.Action, entry.TargetId, []byte(entry.Before), []byte(entry.After), entry.CreateTime)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	entry.Id = int(id)
+	return nil
+}
+
+func (a *Audit) GetByGroup(ctx context.Context, filter *models.AuditFilter) ([]models.AuditEntry, *errors.Error) {
+	conditions := []string{"group_id = ?"}
+	args := []any{filter.GroupId}
+	if filter.ActorId != 0 {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, filter.ActorId)
+	}
+	if filter.TargetId != 0 {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, filter.TargetId)
+	}
+	if len(filter.Action) != 0 {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.LastId > 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, filter.LastId)
+	}
+	args = append(args, filter.Count)
+
+	rows, err := a.DB.QueryContext(ctx, "SELECT * FROM group_audit WHERE "+strings.Join(conditions, " AND ")+" ORDER BY id DESC LIMIT ?", args...)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var entry models.AuditEntry
+		if err := entry.ScanRow(rows); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
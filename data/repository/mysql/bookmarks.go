@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+	errorsutils "errors"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+type Bookmarks struct {
+	DB
+}
+
+func NewBookmarks(db DB) (*Bookmarks, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_bookmarks.sql"); err != nil {
+		return nil, errorsutils.New("create table bookmarks error: " + err.Error())
+	}
+	return &Bookmarks{db}, nil
+}
+
+func (b *Bookmarks) New(ctx context.Context, bookmark *models.Bookmark) *errors.Error {
+	bookmark.CreateTime = time.Now()
+	res, err := b.DB.ExecContext(ctx, "INSERT INTO bookmarks (user_id, message_id, create_time) VALUES (?, ?, ?)",
+		bookmark.UserId, bookmark.MessageId, bookmark.CreateTime)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return errors.New(err, "message already saved", http.StatusBadRequest)
+		}
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	bookmark.Id = int(id)
+	return nil
+}
+
+const getBookmarksByUserQuery = `
+SELECT
+    m.*,
+    b.id,
+    b.user_id,
+    b.message_id,
+    b.create_time
+FROM bookmarks b
+INNER JOIN messages m ON m.id = b.message_id
+INNER JOIN user_2_chat uc ON uc.chat_id = m.chat_id AND uc.user_id = b.user_id
+WHERE b.user_id = ?
+ORDER BY b.create_time DESC
+`
+
+func (b *Bookmarks) GetByUser(ctx context.Context, userId int) ([]models.Bookmark, *errors.Error) {
+	rows, err := b.DB.QueryContext(ctx, getBookmarksByUserQuery, userId)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	bookmarks := make([]models.Bookmark, 0)
+	for rows.Next() {
+		var bookmark models.Bookmark
+		message := new(models.Message)
+		if err := message.ScanRow(rowWithTail{rows, []any{&bookmark.Id, &bookmark.UserId, &bookmark.MessageId, &bookmark.CreateTime}}); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		bookmark.Message = message
+		bookmarks = append(bookmarks, bookmark)
+	}
+	return bookmarks, nil
+}
+
+func (b *Bookmarks) Delete(ctx context.Context, userId int, messageId int) *errors.Error {
+	if _, err := b.DB.ExecContext(ctx, "DELETE FROM bookmarks WHERE user_id = ? AND message_id = ?", userId, messageId); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBookmarks(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestDB(t)
+	chats, users, messages := newTestTables(t, conn)
+	bookmarks, err := NewBookmarks(conn)
+	require.NoError(t, err)
+
+	user := newTestUser(t, users)
+	other := newTestUser(t, users)
+	chat := &models.Chat{Type: models.ChatTypeUser}
+	require.Nil(t, chats.New(ctx, chat))
+	require.Nil(t, chats.AddUserToChat(ctx, chat.Id, user.Id))
+	require.Nil(t, chats.AddUserToChat(ctx, chat.Id, other.Id))
+
+	message := &models.Message{ChatId: chat.Id, UserId: other.Id, Text: "hi", Time: time.Now().Truncate(time.Second)}
+	require.Nil(t, messages.New(ctx, message))
+
+	bookmark := &models.Bookmark{UserId: user.Id, MessageId: message.Id}
+	require.Nil(t, bookmarks.New(ctx, bookmark))
+	require.NotZero(t, bookmark.Id)
+
+	dbErr := bookmarks.New(ctx, &models.Bookmark{UserId: user.Id, MessageId: message.Id})
+	require.NotNil(t, dbErr, "message should be saved once")
+	require.Equal(t, http.StatusBadRequest, dbErr.Code)
+
+	saved, dbErr := bookmarks.GetByUser(ctx, user.Id)
+	require.Nil(t, dbErr)
+	require.Len(t, saved, 1)
+	require.Equal(t, bookmark.Id, saved[0].Id)
+	require.Equal(t, message.Id, saved[0].Message.Id)
+	require.Equal(t, "hi", saved[0].Message.Text)
+
+	// bookmarks of left chat are hidden
+	require.Nil(t, chats.RemoveUserFromChat(ctx, chat.Id, user.Id))
+	saved, dbErr = bookmarks.GetByUser(ctx, user.Id)
+	require.Nil(t, dbErr)
+	require.Empty(t, saved)
+	require.Nil(t, chats.AddUserToChat(ctx, chat.Id, user.Id))
+
+	require.Nil(t, bookmarks.Delete(ctx, user.Id, message.Id))
+	saved, dbErr = bookmarks.GetByUser(ctx, user.Id)
+	require.Nil(t, dbErr)
+	require.Empty(t, saved)
+
+	// bookmarks are removed with message
+	require.Nil(t, bookmarks.New(ctx, &models.Bookmark{UserId: user.Id, MessageId: message.Id}))
+	require.Nil(t, chats.Delete(ctx, chat.Id))
+	saved, dbErr = bookmarks.GetByUser(ctx, user.Id)
+	require.Nil(t, dbErr)
+	require.Empty(t, saved)
+}
@@ -24,6 +24,11 @@ func NewChats(db DB) (*Chats, error) {
 	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_user_2_chat.sql"); err != nil {
 		return nil, errorsutils.New("create table user_2_chat error: " + err.Error())
 	}
+	if err := migrateColumn(ctx, db, "chats", "saved_user_id",
+		"data/repository/mysql/scripts/alter_chats_add_saved_user_id.sql",
+		"data/repository/mysql/scripts/update_chats_saved_user_id.sql"); err != nil {
+		return nil, errorsutils.New("add column chats.saved_user_id error: " + err.Error())
+	}
 
 	return &Chats{db}, nil
 }
@@ -42,6 +47,24 @@ func (c *Chats) New(ctx context.Context, chat *models.Chat) (e *errors.Error) {
 	return nil
 }
 
+// NewSaved creates saved messages chat of user, it fails with conflict if user already has one
+func (c *Chats) NewSaved(ctx context.Context, chat *models.Chat, userId int) *errors.Error {
+	chat.Type = models.ChatTypeSaved
+	res, err := c.DB.ExecContext(ctx, "INSERT INTO chats (type, saved_user_id) VALUES (?, ?)", chat.Type, userId)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return errors.New(err, "saved messages chat already exists", http.StatusConflict)
+		}
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	chatId, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	chat.Id = int(chatId)
+	return nil
+}
+
 func (c *Chats) AddUserToChat(ctx context.Context, id int, userId int) *errors.Error {
 	if _, err := c.DB.ExecContext(ctx, "INSERT INTO user_2_chat (user_id, chat_id) VALUES (?, ?)", userId, id); err != nil {
 		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
@@ -150,7 +173,7 @@ func (c *Chats) GetUserCompanionByChatId(ctx context.Context, userId int, chatId
 
 func (c *Chats) GetById(ctx context.Context, id int) (*models.Chat, *errors.Error) {
 	chat := new(models.Chat)
-	if err := c.DB.QueryRowContext(ctx, "SELECT * FROM chats WHERE id=?", id).Scan(&chat.Id, &chat.Type, &chat.CreateTime); err != nil {
+	if err := c.DB.QueryRowContext(ctx, "SELECT id, type, create_time, last_message_time FROM chats WHERE id=?", id).Scan(&chat.Id, &chat.Type, &chat.CreateTime, &chat.LastMessageTime); err != nil {
 		if errorsutils.Is(err, sql.ErrNoRows) {
 			return chat, errors.New(err, "chat not found", http.StatusNotFound)
 		}
@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"net/http"
+	"testing"
+)
+
+func TestNewSavedChat(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestDB(t)
+	chats, users, _ := newTestTables(t, conn)
+	user := newTestUser(t, users)
+
+	chat := new(models.Chat)
+	require.Nil(t, chats.NewSaved(ctx, chat, user.Id))
+	require.Equal(t, models.ChatTypeSaved, chat.Type)
+
+	err := chats.NewSaved(ctx, new(models.Chat), user.Id)
+	require.NotNil(t, err, "user should have one saved chat")
+	require.Equal(t, http.StatusConflict, err.Code)
+
+	saved, err := chats.GetById(ctx, chat.Id)
+	require.Nil(t, err)
+	require.Equal(t, models.ChatTypeSaved, saved.Type)
+}
@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"messanger/pkg/db"
+	"os"
+	"testing"
+	"time"
+)
+
+// newTestDB connects to MySQL from MYSQL_TEST_DSN, e.g. "user:password@tcp(localhost:3306)/test?parseTime=true",
+// tests are skipped without it
+func newTestDB(t *testing.T) *db.DBWithTx {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if len(dsn) == 0 {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+	conn, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	// scripts are opened relative to the module root
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir("../../.."))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	return db.NewDBWithTx(conn)
+}
+
+// newTestTables creates tables in order of their references
+func newTestTables(t *testing.T, conn DB) (*Chats, *Users, *Messages) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, openAndExec(ctx, conn, "data/repository/mysql/scripts/create_users.sql"))
+
+	chats, err := NewChats(conn)
+	require.NoError(t, err)
+	users, err := NewUsers(conn)
+	require.NoError(t, err)
+	_, err = NewStickers(conn)
+	require.NoError(t, err)
+	messages, err := NewMessages(conn)
+	require.NoError(t, err)
+	return chats, users, messages
+}
+
+func newTestUser(t *testing.T, users *Users) *models.User {
+	id := uuid.NewString()
+	user := &models.User{
+		Phone:    id[:16],
+		Password: "password",
+		Name:     id[:32],
+		RealName: "test",
+	}
+	require.Nil(t, users.New(context.Background(), user))
+	return user
+}
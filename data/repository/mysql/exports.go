@@ -0,0 +1,107 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	errorsutils "errors"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+type Exports struct {
+	DB
+}
+
+func NewExports(db DB) (*Exports, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_export_jobs.sql"); err != nil {
+		return nil, errorsutils.New("create table export_jobs error: " + err.Error())
+	}
+	return &Exports{db}, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: len(s) != 0}
+}
+
+func (e *Exports) New(ctx context.Context, job *models.ExportJob) *errors.Error {
+	job.UpdateTime = job.CreateTime
+	if _, err := e.DB.ExecContext(ctx, "INSERT INTO export_jobs (id, user_id, chat_id, format, status, total, processed, error, token, expires_at, create_time, update_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		job.Id, job.UserId, job.ChatId, job.Format, job.Status, job.Total, job.Processed, job.Error,
+		nullString(job.Token), job.ExpiresAt, job.CreateTime, job.UpdateTime); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (e *Exports) GetById(ctx context.Context, id string) (*models.ExportJob, *errors.Error) {
+	job := new(models.ExportJob)
+	if err := job.ScanRow(e.DB.QueryRowContext(ctx, "SELECT * FROM export_jobs WHERE id = ?", id)); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "export not found", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return job, nil
+}
+
+func (e *Exports) GetByToken(ctx context.Context, token string) (*models.ExportJob, *errors.Error) {
+	job := new(models.ExportJob)
+	if err := job.ScanRow(e.DB.QueryRowContext(ctx, "SELECT * FROM export_jobs WHERE token = ?", token)); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "export not found or link expired", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return job, nil
+}
+
+// Update saves status and progress of job
+func (e *Exports) Update(ctx context.Context, job *models.ExportJob) *errors.Error {
+	job.UpdateTime = time.Now()
+	if _, err := e.DB.ExecContext(ctx, "UPDATE export_jobs SET status = ?, total = ?, processed = ?, error = ?, token = ?, expires_at = ?, update_time = ? WHERE id = ?",
+		job.Status, job.Total, job.Processed, job.Error, nullString(job.Token), job.ExpiresAt, job.UpdateTime, job.Id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// FailStale marks running jobs without progress since before as failed, their instance was stopped
+func (e *Exports) FailStale(ctx context.Context, before time.Time, reason string) *errors.Error {
+	if _, err := e.DB.ExecContext(ctx, "UPDATE export_jobs SET status = ?, error = ?, update_time = ? WHERE status = ? AND update_time < ?",
+		models.ExportStatusFailed, reason, time.Now(), models.ExportStatusRunning, before); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// GetExpired returns finished jobs with expired link and jobs failed before failedBefore
+func (e *Exports) GetExpired(ctx context.Context, now, failedBefore time.Time) ([]models.ExportJob, *errors.Error) {
+	rows, err := e.DB.QueryContext(ctx, "SELECT * FROM export_jobs WHERE expires_at < ? OR (status = ? AND update_time < ?)",
+		now, models.ExportStatusFailed, failedBefore)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	jobs := make([]models.ExportJob, 0)
+	for rows.Next() {
+		var job models.ExportJob
+		if err := job.ScanRow(rows); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (e *Exports) Delete(ctx context.Context, id string) *errors.Error {
+	if _, err := e.DB.ExecContext(ctx, "DELETE FROM export_jobs WHERE id = ?", id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
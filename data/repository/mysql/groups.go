@@ -22,15 +22,40 @@ func NewGroups(db DB) (*Groups, error) {
 	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_groups.sql"); err != nil {
 		return nil, errorsutils.New("create table groups error: " + err.Error())
 	}
+	if err := migrateColumn(ctx, db, "groups", "slow_mode_sec", "data/repository/mysql/scripts/alter_groups_add_slow_mode_sec.sql"); err != nil {
+		return nil, errorsutils.New("add column groups.slow_mode_sec error: " + err.Error())
+	}
+	if err := migrateColumn(ctx, db, "groups", "description", "data/repository/mysql/scripts/alter_groups_add_description.sql"); err != nil {
+		return nil, errorsutils.New("add column groups.description error: " + err.Error())
+	}
+	if err := migrateColumn(ctx, db, "groups", "avatar", "data/repository/mysql/scripts/alter_groups_add_avatar.sql"); err != nil {
+		return nil, errorsutils.New("add column groups.avatar error: " + err.Error())
+	}
+	if err := migrateColumn(ctx, db, "groups", "only_admins_post", "data/repository/mysql/scripts/alter_groups_add_only_admins_post.sql"); err != nil {
+		return nil, errorsutils.New("add column groups.only_admins_post error: " + err.Error())
+	}
+	if err := migrateColumn(ctx, db, "groups", "members_can_invite", "data/repository/mysql/scripts/alter_groups_add_members_can_invite.sql"); err != nil {
+		return nil, errorsutils.New("add column groups.members_can_invite error: " + err.Error())
+	}
+	if err := migrateColumn(ctx, db, "groups", "username", "data/repository/mysql/scripts/alter_groups_add_username.sql"); err != nil {
+		return nil, errorsutils.New("add column groups.username error: " + err.Error())
+	}
 	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_roles.sql"); err != nil {
 		return nil, errorsutils.New("create table roles error: " + err.Error())
 	}
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_group_roles.sql"); err != nil {
+		return nil, errorsutils.New("create table group_roles error: " + err.Error())
+	}
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_member_permissions.sql"); err != nil {
+		return nil, errorsutils.New("create table member_permissions error: " + err.Error())
+	}
 
 	return &Groups{DB: db}, nil
 }
 
 func (g *Groups) New(ctx context.Context, group *models.Group) *errors.Error {
-	res, err := g.DB.ExecContext(ctx, "INSERT INTO `groups` (chat_id, name) VALUES (?, ?)", group.ChatId, group.Name)
+	res, err := g.DB.ExecContext(ctx, "INSERT INTO `groups` (chat_id, name, description, only_admins_post, members_can_invite) VALUES (?, ?, ?, ?, ?)",
+		group.ChatId, group.Name, group.Description, group.OnlyAdminsPost, group.MembersCanInvite)
 	if err != nil {
 		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
 	}
@@ -43,16 +68,78 @@ func (g *Groups) New(ctx context.Context, group *models.Group) *errors.Error {
 }
 
 func (g *Groups) Update(ctx context.Context, group *models.Group) *errors.Error {
-	if len(group.Name) == 0 {
-		return nil
+	if _, err := g.DB.ExecContext(ctx, "UPDATE `groups` SET name = ?, description = ?, only_admins_post = ?, members_can_invite = ? WHERE id = ?",
+		group.Name, group.Description, group.OnlyAdminsPost, group.MembersCanInvite, group.Id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// SetUsername sets public username of group, empty username makes group private
+func (g *Groups) SetUsername(ctx context.Context, id int, username string) *errors.Error {
+	if _, err := g.DB.ExecContext(ctx, "UPDATE `groups` SET username = ? WHERE id = ?", sql.NullString{
+		String: username,
+		Valid:  len(username) != 0,
+	}, id); err != nil {
+		if isDuplicateEntry(err) {
+			return errors.New(err, "username is already taken", http.StatusBadRequest)
+		}
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (g *Groups) GetByUsername(ctx context.Context, username string) (*models.Group, *errors.Error) {
+	var group models.Group
+	if err := group.ScanRow(g.DB.QueryRowContext(ctx, "SELECT * FROM `groups` WHERE username = ?", username)); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "not found", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return &group, nil
+}
+
+const searchPublicQuery = `
+SELECT
+    g.*,
+    c.type,
+    (SELECT COUNT(*) FROM user_2_chat uc WHERE uc.chat_id = g.chat_id) AS members
+FROM ` + "`groups`" + ` g
+INNER JOIN chats c ON c.id = g.chat_id
+WHERE g.username IS NOT NULL AND (g.name LIKE ? OR g.username LIKE ?)
+ORDER BY members DESC, g.id
+LIMIT ?
+`
+
+// SearchPublic searches groups and channels with username by name or username
+func (g *Groups) SearchPublic(ctx context.Context, query string, limit int) ([]models.PublicGroup, *errors.Error) {
+	pattern := containsPattern(query)
+	rows, err := g.DB.QueryContext(ctx, searchPublicQuery, pattern, pattern, limit)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	groups := make([]models.PublicGroup, 0)
+	for rows.Next() {
+		group := models.PublicGroup{Group: new(models.Group)}
+		if err := group.ScanRow(rowWithTail{rows, []any{&group.Type, &group.Members}}); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		groups = append(groups, group)
 	}
-	if _, err := g.DB.ExecContext(ctx, "UPDATE `groups` SET name = ? WHERE id = ?", group.Name, group.Id); err != nil {
+	return groups, nil
+}
+
+func (g *Groups) SetAvatar(ctx context.Context, id int, avatar string) *errors.Error {
+	if _, err := g.DB.ExecContext(ctx, "UPDATE `groups` SET avatar = ? WHERE id = ?", avatar, id); err != nil {
 		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
 	}
 	return nil
 }
 
-const getGroupsByUserQuery = "SELECT g.id, g.chat_id, g.name FROM user_2_chat INNER JOIN `groups` g ON g.chat_id = user_2_chat.chat_id WHERE user_2_chat.user_id = ?"
+const getGroupsByUserQuery = "SELECT g.* FROM user_2_chat INNER JOIN `groups` g ON g.chat_id = user_2_chat.chat_id WHERE user_2_chat.user_id = ?"
 
 func (g *Groups) GetGroupsByUser(ctx context.Context, userId int) ([]models.Group, *errors.Error) {
 	rows, err := g.DB.QueryContext(ctx, getGroupsByUserQuery, userId)
@@ -66,7 +153,7 @@ func (g *Groups) GetGroupsByUser(ctx context.Context, userId int) ([]models.Grou
 	var groups []models.Group
 	for rows.Next() {
 		var group models.Group
-		if err := rows.Scan(&group.Id, &group.ChatId, &group.Name); err != nil {
+		if err := group.ScanRow(rows); err != nil {
 			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
 		}
 		groups = append(groups, group)
@@ -76,7 +163,7 @@ func (g *Groups) GetGroupsByUser(ctx context.Context, userId int) ([]models.Grou
 
 func (g *Groups) GetGroupByChatId(ctx context.Context, chatId int) (*models.Group, *errors.Error) {
 	var group models.Group
-	if err := g.DB.QueryRowContext(ctx, "SELECT * FROM `groups` WHERE chat_id = ?", chatId).Scan(&group.Id, &group.ChatId, &group.Name); err != nil {
+	if err := group.ScanRow(g.DB.QueryRowContext(ctx, "SELECT * FROM `groups` WHERE chat_id = ?", chatId)); err != nil {
 		if errorsutils.Is(err, sql.ErrNoRows) {
 			return nil, errors.New(err, fmt.Sprintf("group not found by chat id = %d", chatId), http.StatusNotFound)
 		}
@@ -91,8 +178,10 @@ SELECT
 FROM user_2_chat
 INNER JOIN ` + "`groups`" + ` g ON g.chat_id = user_2_chat.chat_id
 WHERE g.id = ?
+ORDER BY user_2_chat.id
 `
 
+// GetUsersByGroup returns users in group ordered by join time
 func (g *Groups) GetUsersByGroup(ctx context.Context, id int) ([]int, *errors.Error) {
 	var users []int
 	rows, err := g.DB.QueryContext(ctx, getUsersByGroupQuery, id)
@@ -133,7 +222,7 @@ func (g *Groups) CheckUserInGroup(ctx context.Context, userId int, groupId int)
 
 func (g *Groups) GetById(ctx context.Context, id int) (*models.Group, *errors.Error) {
 	var group models.Group
-	if err := g.DB.QueryRowContext(ctx, "SELECT * FROM `groups` WHERE id = ?", id).Scan(&group.Id, &group.ChatId, &group.Name); err != nil {
+	if err := group.ScanRow(g.DB.QueryRowContext(ctx, "SELECT * FROM `groups` WHERE id = ?", id)); err != nil {
 		if errorsutils.Is(err, sql.ErrNoRows) {
 			return nil, errors.New(err, "group not found", http.StatusNotFound)
 		}
@@ -142,6 +231,13 @@ func (g *Groups) GetById(ctx context.Context, id int) (*models.Group, *errors.Er
 	return &group, nil
 }
 
+func (g *Groups) SetSlowMode(ctx context.Context, id int, seconds int) *errors.Error {
+	if _, err := g.DB.ExecContext(ctx, "UPDATE `groups` SET slow_mode_sec = ? WHERE id = ?", seconds, id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
 func (g *Groups) SetRole(ctx context.Context, userId int, groupId int, role string) *errors.Error {
 	var existRole string
 	if err := g.DB.QueryRowContext(ctx, "SELECT role FROM roles WHERE user_id=? AND group_id=?", userId, groupId).Scan(&existRole); err != nil {
@@ -172,6 +268,100 @@ func (g *Groups) GetRole(ctx context.Context, userId int, groupId int) (string,
 	return role, nil
 }
 
+func (g *Groups) NewCustomRole(ctx context.Context, role *models.GroupRole) *errors.Error {
+	res, err := g.DB.ExecContext(ctx, "INSERT INTO group_roles (group_id, name, permissions) VALUES (?, ?, ?)",
+		role.GroupId, role.Name, role.Permissions)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return errors.New(err, "role already exists", http.StatusBadRequest)
+		}
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	role.Id = int(id)
+	return nil
+}
+
+func (g *Groups) GetCustomRole(ctx context.Context, groupId int, name string) (*models.GroupRole, *errors.Error) {
+	var role models.GroupRole
+	if err := g.DB.QueryRowContext(ctx, "SELECT id, group_id, name, permissions FROM group_roles WHERE group_id = ? AND name = ?", groupId, name).Scan(
+		&role.Id, &role.GroupId, &role.Name, &role.Permissions); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "role not found", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return &role, nil
+}
+
+func (g *Groups) GetCustomRoles(ctx context.Context, groupId int) ([]models.GroupRole, *errors.Error) {
+	rows, err := g.DB.QueryContext(ctx, "SELECT id, group_id, name, permissions FROM group_roles WHERE group_id = ? ORDER BY id", groupId)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	roles := make([]models.GroupRole, 0)
+	for rows.Next() {
+		var role models.GroupRole
+		if err := rows.Scan(&role.Id, &role.GroupId, &role.Name, &role.Permissions); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func (g *Groups) UpdateCustomRole(ctx context.Context, role *models.GroupRole) *errors.Error {
+	if _, err := g.DB.ExecContext(ctx, "UPDATE group_roles SET permissions = ? WHERE group_id = ? AND name = ?",
+		role.Permissions, role.GroupId, role.Name); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// DeleteCustomRole deletes role and sets member role to members who had it
+func (g *Groups) DeleteCustomRole(ctx context.Context, groupId int, name string) *errors.Error {
+	if _, err := g.DB.ExecContext(ctx, "DELETE FROM group_roles WHERE group_id = ? AND name = ?", groupId, name); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	if _, err := g.DB.ExecContext(ctx, "UPDATE roles SET role = ? WHERE group_id = ? AND role = ?", models.RoleMember, groupId, name); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (g *Groups) SetPermissionOverride(ctx context.Context, override *models.PermissionOverride) *errors.Error {
+	if _, err := g.DB.ExecContext(ctx, "INSERT INTO member_permissions (group_id, user_id, allow, deny) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE allow = VALUES(allow), deny = VALUES(deny)",
+		override.GroupId, override.UserId, override.Allow, override.Deny); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// GetPermissionOverride returns empty override if member has no override
+func (g *Groups) GetPermissionOverride(ctx context.Context, userId int, groupId int) (*models.PermissionOverride, *errors.Error) {
+	override := &models.PermissionOverride{GroupId: groupId, UserId: userId}
+	if err := g.DB.QueryRowContext(ctx, "SELECT allow, deny FROM member_permissions WHERE group_id = ? AND user_id = ?", groupId, userId).Scan(
+		&override.Allow, &override.Deny); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return override, nil
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return override, nil
+}
+
+func (g *Groups) DeletePermissionOverride(ctx context.Context, userId int, groupId int) *errors.Error {
+	if _, err := g.DB.ExecContext(ctx, "DELETE FROM member_permissions WHERE group_id = ? AND user_id = ?", groupId, userId); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
 func (g *Groups) Delete(ctx context.Context, id int) (e *errors.Error) {
 	if _, err := g.DB.ExecContext(ctx, "DELETE FROM `groups` WHERE id = ?", id); err != nil {
 		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
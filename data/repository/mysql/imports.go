@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"context"
+	errorsutils "errors"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+type Imports struct {
+	DB
+}
+
+func NewImports(db DB) (*Imports, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_imported_chats.sql"); err != nil {
+		return nil, errorsutils.New("create table imported_chats error: " + err.Error())
+	}
+	return &Imports{db}, nil
+}
+
+// New records that chat was imported from source chat, it fails with conflict if source chat was already imported
+func (i *Imports) New(ctx context.Context, sourceId string, chatId int) *errors.Error {
+	if _, err := i.DB.ExecContext(ctx, "INSERT INTO imported_chats (source_id, chat_id, create_time) VALUES (?, ?, ?)",
+		sourceId, chatId, time.Now()); err != nil {
+		if isDuplicateEntry(err) {
+			return errors.New(err, "chat is already imported", http.StatusConflict)
+		}
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
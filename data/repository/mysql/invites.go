@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	errorsutils "errors"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+type Invites struct {
+	DB
+}
+
+func NewInvites(db DB) (*Invites, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_group_invites.sql"); err != nil {
+		return nil, errorsutils.New("create table group_invites error: " + err.Error())
+	}
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_join_requests.sql"); err != nil {
+		return nil, errorsutils.New("create table join_requests error: " + err.Error())
+	}
+
+	return &Invites{db}, nil
+}
+
+func (i *Invites) New(ctx context.Context, invite *models.GroupInvite) *errors.Error {
+	invite.CreateTime = time.Now()
+	res, err := i.DB.ExecContext(ctx, "INSERT INTO group_invites (group_id, creator_id, token, expire_time, max_uses, need_approval, create_time) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		invite.GroupId, invite.CreatorId, invite.Token, invite.ExpireTime, invite.MaxUses, invite.NeedApproval, invite.CreateTime)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	invite.Id = int(id)
+	return nil
+}
+
+func (i *Invites) GetById(ctx context.Context, id int) (*models.GroupInvite, *errors.Error) {
+	invite := new(models.GroupInvite)
+	if err := invite.ScanRow(i.DB.QueryRowContext(ctx, "SELECT * FROM group_invites WHERE id = ?", id)); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "invite not found", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return invite, nil
+}
+
+func (i *Invites) GetByToken(ctx context.Context, token string) (*models.GroupInvite, *errors.Error) {
+	invite := new(models.GroupInvite)
+	if err := invite.ScanRow(i.DB.QueryRowContext(ctx, "SELECT * FROM group_invites WHERE token = ?", token)); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "invite not found", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return invite, nil
+}
+
+func (i *Invites) GetByGroup(ctx context.Context, groupId int) ([]models.GroupInvite, *errors.Error) {
+	rows, err := i.DB.QueryContext(ctx, "SELECT * FROM group_invites WHERE group_id = ? ORDER BY id DESC", groupId)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	invites := make([]models.GroupInvite, 0)
+	for rows.Next() {
+		var invite models.GroupInvite
+		if err := invite.ScanRow(rows); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		invites = append(invites, invite)
+	}
+	return invites, nil
+}
+
+// Use increments invite uses, returns false if invite usage limit is reached
+func (i *Invites) Use(ctx context.Context, id int) (bool, *errors.Error) {
+	res, err := i.DB.ExecContext(ctx, "UPDATE group_invites SET uses = uses + 1 WHERE id = ? AND (max_uses = 0 OR uses < max_uses)", id)
+	if err != nil {
+		return false, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return n != 0, nil
+}
+
+func (i *Invites) Revoke(ctx context.Context, id int) *errors.Error {
+	if _, err := i.DB.ExecContext(ctx, "UPDATE group_invites SET revoked = 1 WHERE id = ?", id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (i *Invites) NewJoinRequest(ctx context.Context, request *models.JoinRequest) *errors.Error {
+	request.CreateTime = time.Now()
+	res, err := i.DB.ExecContext(ctx, "INSERT INTO join_requests (group_id, user_id, invite_id, create_time) VALUES (?, ?, ?, ?)",
+		request.GroupId, request.UserId, request.InviteId, request.CreateTime)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return errors.New(err, "join request already sent", http.StatusBadRequest)
+		}
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	request.Id = int(id)
+	return nil
+}
+
+func (i *Invites) GetJoinRequestById(ctx context.Context, id int) (*models.JoinRequest, *errors.Error) {
+	var request models.JoinRequest
+	if err := i.DB.QueryRowContext(ctx, "SELECT id, group_id, user_id, invite_id, create_time FROM join_requests WHERE id = ?", id).Scan(
+		&request.Id, &request.GroupId, &request.UserId, &request.InviteId, &request.CreateTime); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "join request not found", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return &request, nil
+}
+
+func (i *Invites) GetJoinRequestsByGroup(ctx context.Context, groupId int) ([]models.JoinRequest, *errors.Error) {
+	rows, err := i.DB.QueryContext(ctx, "SELECT id, group_id, user_id, invite_id, create_time FROM join_requests WHERE group_id = ? ORDER BY id", groupId)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	requests := make([]models.JoinRequest, 0)
+	for rows.Next() {
+		var request models.JoinRequest
+		if err := rows.Scan(&request.Id, &request.GroupId, &request.UserId, &request.InviteId, &request.CreateTime); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+func (i *Invites) DeleteJoinRequest(ctx context.Context, id int) *errors.Error {
+	if _, err := i.DB.ExecContext(ctx, "DELETE FROM join_requests WHERE id = ?", id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	errorsutils "errors"
 	"messanger/domain/models"
 	"messanger/pkg/errors"
@@ -21,12 +22,42 @@ func NewMessages(db DB) (*Messages, error) {
 	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_messages.sql"); err != nil {
 		return nil, errorsutils.New("create table messages error: " + err.Error())
 	}
+	if err := migrateColumn(ctx, db, "messages", "kind", "data/repository/mysql/scripts/alter_messages_add_kind.sql"); err != nil {
+		return nil, errorsutils.New("add column messages.kind error: " + err.Error())
+	}
+	if err := migrateColumn(ctx, db, "messages", "sticker_id", "data/repository/mysql/scripts/alter_messages_add_sticker_id.sql"); err != nil {
+		return nil, errorsutils.New("add column messages.sticker_id error: " + err.Error())
+	}
+	if err := migrateColumn(ctx, db, "messages", "payload", "data/repository/mysql/scripts/alter_messages_add_payload.sql"); err != nil {
+		return nil, errorsutils.New("add column messages.payload error: " + err.Error())
+	}
+	// stickers could be deleted before the foreign key was added
+	if err := migrateConstraint(ctx, db, "messages", "messages_sticker_key",
+		"data/repository/mysql/scripts/clear_messages_deleted_stickers.sql",
+		"data/repository/mysql/scripts/alter_messages_add_sticker_key.sql",
+	); err != nil {
+		return nil, errorsutils.New("add foreign key messages_sticker_key error: " + err.Error())
+	}
 	return &Messages{db}, nil
 }
 
 func (m *Messages) New(ctx context.Context, message *models.Message) *errors.Error {
-	res, err := m.DB.ExecContext(ctx, "INSERT INTO messages (chat_id, user_id, value, time) VALUE (?, ?, ?, ?)",
-		message.ChatId, message.UserId, message.Text, message.Time)
+	if len(message.Kind) == 0 {
+		message.Kind = models.MessageKindText
+	}
+	var stickerId *int
+	if message.StickerId != 0 {
+		stickerId = &message.StickerId
+	}
+	var payload []byte
+	if message.Payload != nil {
+		var err error
+		if payload, err = json.Marshal(message.Payload); err != nil {
+			return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+	}
+	res, err := m.DB.ExecContext(ctx, "INSERT INTO messages (chat_id, user_id, value, time, kind, sticker_id, payload) VALUE (?, ?, ?, ?, ?, ?, ?)",
+		message.ChatId, message.UserId, message.Text, message.Time, message.Kind, stickerId, payload)
 	if err != nil {
 		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
 	}
@@ -62,7 +93,7 @@ func (m *Messages) GetByChat(ctx context.Context, chatId int, lastId int, count
 
 	for rows.Next() {
 		var message models.Message
-		if err := rows.Scan(&message.Id, &message.ChatId, &message.UserId, &message.Text, &message.Time); err != nil {
+		if err := message.ScanRow(rows); err != nil {
 			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
 		}
 		messages = append(messages, message)
@@ -70,6 +101,33 @@ func (m *Messages) GetByChat(ctx context.Context, chatId int, lastId int, count
 	return messages, nil
 }
 
+func (m *Messages) GetByChatAfter(ctx context.Context, chatId int, afterId int, count int) ([]models.Message, *errors.Error) {
+	messages := make([]models.Message, 0, count)
+
+	rows, err := m.DB.QueryContext(ctx, "SELECT * FROM messages WHERE chat_id = ? AND id > ? ORDER BY id LIMIT ?", chatId, afterId, count)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var message models.Message
+		if err := message.ScanRow(rows); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+func (m *Messages) CountByChat(ctx context.Context, chatId int) (int, *errors.Error) {
+	var count int
+	if err := m.DB.QueryRowContext(ctx, "SELECT COUNT(id) FROM messages WHERE chat_id = ?", chatId).Scan(&count); err != nil {
+		return 0, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return count, nil
+}
+
 func (m *Messages) GetMinMassageIdInChat(ctx context.Context, chatId int) (int, *errors.Error) {
 	var id int
 	if err := m.DB.QueryRowContext(ctx, "SELECT IFNULL(MIN(id), -1) FROM messages WHERE chat_id = ?", chatId).Scan(&id); err != nil {
@@ -80,8 +138,7 @@ func (m *Messages) GetMinMassageIdInChat(ctx context.Context, chatId int) (int,
 
 func (m *Messages) GetLastMessage(ctx context.Context, chatId int) (*models.Message, *errors.Error) {
 	var message models.Message
-	if err := m.DB.QueryRowContext(ctx, "SELECT * FROM messages WHERE chat_id = ? ORDER BY time DESC", chatId).Scan(
-		&message.Id, &message.ChatId, &message.UserId, &message.Text, &message.Time); err != nil {
+	if err := message.ScanRow(m.DB.QueryRowContext(ctx, "SELECT * FROM messages WHERE chat_id = ? ORDER BY time DESC", chatId)); err != nil {
 		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
 	}
 	return &message, nil
@@ -97,8 +154,7 @@ func (m *Messages) IsUserMessage(ctx context.Context, id int, userId int) (bool,
 
 func (m *Messages) GetById(ctx context.Context, id int) (*models.Message, *errors.Error) {
 	var message models.Message
-	if err := m.DB.QueryRowContext(ctx, "SELECT * FROM messages WHERE id = ?", id).Scan(
-		&message.Id, &message.ChatId, &message.UserId, &message.Text, &message.Time); err != nil {
+	if err := message.ScanRow(m.DB.QueryRowContext(ctx, "SELECT * FROM messages WHERE id = ?", id)); err != nil {
 		if errorsutils.Is(err, sql.ErrNoRows) {
 			return nil, errors.New(err, "message not found", http.StatusNotFound)
 		}
@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	errorsutils "errors"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+type Restrictions struct {
+	DB
+}
+
+func NewRestrictions(db DB) (*Restrictions, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_group_restrictions.sql"); err != nil {
+		return nil, errorsutils.New("create table group_restrictions error: " + err.Error())
+	}
+	return &Restrictions{db}, nil
+}
+
+// Set creates restriction or replaces existing restriction of the same type
+func (r *Restrictions) Set(ctx context.Context, restriction *models.Restriction) *errors.Error {
+	restriction.CreateTime = time.Now()
+	if _, err := r.DB.ExecContext(ctx, `INSERT INTO group_restrictions (group_id, user_id, actor_id, type, reason, expire_time, create_time) VALUES (?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE actor_id = VALUES(actor_id), reason = VALUES(reason), expire_time = VALUES(expire_time), create_time = VALUES(create_time)`,
+		restriction.GroupId, restriction.UserId, restriction.ActorId, restriction.Type, restriction.Reason, restriction.ExpireTime, restriction.CreateTime); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	if err := restriction.ScanRow(r.DB.QueryRowContext(ctx, "SELECT * FROM group_restrictions WHERE group_id = ? AND user_id = ? AND type = ?",
+		restriction.GroupId, restriction.UserId, restriction.Type)); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// Get returns active restriction, expired restrictions are not found
+func (r *Restrictions) Get(ctx context.Context, groupId int, userId int, restrictionType string) (*models.Restriction, *errors.Error) {
+	restriction := new(models.Restriction)
+	if err := restriction.ScanRow(r.DB.QueryRowContext(ctx, "SELECT * FROM group_restrictions WHERE group_id = ? AND user_id = ? AND type = ? AND (expire_time IS NULL OR expire_time > ?)",
+		groupId, userId, restrictionType, time.Now())); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, restrictionType+" not found", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return restriction, nil
+}
+
+func (r *Restrictions) GetByGroup(ctx context.Context, groupId int, restrictionType string) ([]models.Restriction, *errors.Error) {
+	rows, err := r.DB.QueryContext(ctx, "SELECT * FROM group_restrictions WHERE group_id = ? AND type = ? AND (expire_time IS NULL OR expire_time > ?) ORDER BY id DESC",
+		groupId, restrictionType, time.Now())
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	restrictions := make([]models.Restriction, 0)
+	for rows.Next() {
+		var restriction models.Restriction
+		if err := restriction.ScanRow(rows); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		restrictions = append(restrictions, restriction)
+	}
+	return restrictions, nil
+}
+
+// Delete returns not found error if user has no restriction of this type
+func (r *Restrictions) Delete(ctx context.Context, groupId int, userId int, restrictionType string) *errors.Error {
+	res, err := r.DB.ExecContext(ctx, "DELETE FROM group_restrictions WHERE group_id = ? AND user_id = ? AND type = ?", groupId, userId, restrictionType)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	if n == 0 {
+		return errors.New1Msg(restrictionType+" not found", http.StatusNotFound)
+	}
+	return nil
+}
+
+func (r *Restrictions) DeleteExpired(ctx context.Context) *errors.Error {
+	if _, err := r.DB.ExecContext(ctx, "DELETE FROM group_restrictions WHERE expire_time <= ?", time.Now()); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
@@ -0,0 +1,4 @@
+alter table chats
+    add column saved_user_id int null,
+    add constraint chats_saved_user_uindex
+        unique (saved_user_id);
\ No newline at end of file
@@ -0,0 +1,2 @@
+alter table `groups`
+    add column avatar varchar(64) default '' not null;
\ No newline at end of file
@@ -0,0 +1,2 @@
+alter table `groups`
+    add column description varchar(255) default '' not null;
\ No newline at end of file
@@ -0,0 +1,2 @@
+alter table `groups`
+    add column members_can_invite tinyint(1) default 0 not null;
\ No newline at end of file
@@ -0,0 +1,2 @@
+alter table `groups`
+    add column only_admins_post tinyint(1) default 0 not null;
\ No newline at end of file
@@ -0,0 +1,2 @@
+alter table `groups`
+    add column slow_mode_sec int default 0 not null;
\ No newline at end of file
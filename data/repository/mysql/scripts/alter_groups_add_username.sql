@@ -0,0 +1,4 @@
+alter table `groups`
+    add column username varchar(32) null,
+    add constraint groups_username_uindex
+        unique (username);
\ No newline at end of file
@@ -0,0 +1,2 @@
+alter table messages
+    add column kind varchar(16) default 'text' not null;
\ No newline at end of file
@@ -0,0 +1,2 @@
+alter table messages
+    add column payload json null;
\ No newline at end of file
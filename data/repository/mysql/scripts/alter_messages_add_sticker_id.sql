@@ -0,0 +1,2 @@
+alter table messages
+    add column sticker_id int null;
\ No newline at end of file
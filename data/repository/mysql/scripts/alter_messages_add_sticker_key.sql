@@ -0,0 +1,4 @@
+alter table messages
+    add constraint messages_sticker_key
+        foreign key (sticker_id) references stickers (id)
+            on delete set null;
\ No newline at end of file
@@ -0,0 +1,2 @@
+alter table users
+    add column email varchar(254) default '' not null;
\ No newline at end of file
@@ -0,0 +1,2 @@
+alter table users
+    add column email_confirmed tinyint default 0 not null;
\ No newline at end of file
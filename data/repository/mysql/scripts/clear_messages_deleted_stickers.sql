@@ -0,0 +1,4 @@
+update messages
+set sticker_id = null
+where sticker_id is not null
+  and sticker_id not in (select id from stickers);
\ No newline at end of file
@@ -0,0 +1,16 @@
+create table if not exists bookmarks
+(
+    id          int auto_increment
+        primary key,
+    user_id     int                                not null,
+    message_id  int                                not null,
+    create_time datetime default CURRENT_TIMESTAMP not null,
+    constraint bookmarks_user_message_idx
+        unique (user_id, message_id),
+    constraint bookmarks_user_key
+        foreign key (user_id) references users (id)
+            on delete cascade,
+    constraint bookmarks_message_key
+        foreign key (message_id) references messages (id)
+            on delete cascade
+);
\ No newline at end of file
@@ -4,5 +4,8 @@ create table if not exists chats
         primary key,
     type              varchar(32) default 'group'               not null,
     create_time       datetime    default CURRENT_TIMESTAMP     not null,
-    last_message_time datetime    default '0001-01-01 00:00:00' not null
+    last_message_time datetime    default '0001-01-01 00:00:00' not null,
+    saved_user_id     int                                       null,
+    constraint chats_saved_user_uindex
+        unique (saved_user_id)
 );
\ No newline at end of file
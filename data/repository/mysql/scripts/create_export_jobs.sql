@@ -0,0 +1,21 @@
+create table if not exists export_jobs
+(
+    id          char(36)                           not null
+        primary key,
+    user_id     int                                not null,
+    chat_id     int                                not null,
+    format      varchar(8)                         not null,
+    status      varchar(16)                        not null,
+    total       int                                not null,
+    processed   int                                not null,
+    error       varchar(255)                       not null,
+    token       char(36)                           null,
+    expires_at  datetime                           null,
+    create_time datetime default CURRENT_TIMESTAMP not null,
+    update_time datetime default CURRENT_TIMESTAMP not null,
+    constraint export_jobs_token_UNIQUE
+        unique (token),
+    constraint export_jobs_user_key
+        foreign key (user_id) references users (id)
+            on delete cascade
+);
\ No newline at end of file
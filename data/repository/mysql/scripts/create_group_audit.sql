@@ -0,0 +1,13 @@
+create table if not exists group_audit
+(
+    id           int auto_increment
+        primary key,
+    group_id     int                                not null,
+    actor_id     int                                not null,
+    action       varchar(32)                        not null,
+    target_id    int      default 0                 not null,
+    value_before json                               null,
+    value_after  json                               null,
+    create_time  datetime default CURRENT_TIMESTAMP not null,
+    index group_audit_group_index (group_id, id)
+);
\ No newline at end of file
@@ -0,0 +1,22 @@
+create table if not exists group_invites
+(
+    id            int auto_increment
+        primary key,
+    group_id      int                                not null,
+    creator_id    int                                not null,
+    token         varchar(64)                        not null,
+    expire_time   datetime                           null,
+    max_uses      int      default 0                 not null,
+    uses          int      default 0                 not null,
+    need_approval tinyint  default 0                 not null,
+    revoked       tinyint  default 0                 not null,
+    create_time   datetime default CURRENT_TIMESTAMP not null,
+    constraint token_UNIQUE
+        unique (token),
+    constraint group_invites_group_key
+        foreign key (group_id) references `groups` (id)
+            on delete cascade,
+    constraint group_invites_creator_key
+        foreign key (creator_id) references users (id)
+            on delete cascade
+);
\ No newline at end of file
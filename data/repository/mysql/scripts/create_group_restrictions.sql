@@ -0,0 +1,20 @@
+create table if not exists group_restrictions
+(
+    id          int auto_increment
+        primary key,
+    group_id    int                                not null,
+    user_id     int                                not null,
+    actor_id    int                                not null,
+    type        varchar(10)                        not null,
+    reason      varchar(255)                       not null,
+    expire_time datetime                           null,
+    create_time datetime default CURRENT_TIMESTAMP not null,
+    constraint group_restrictions_group_user_type_uindex
+        unique (group_id, user_id, type),
+    constraint group_restrictions_group_key
+        foreign key (group_id) references `groups` (id)
+            on delete cascade,
+    constraint group_restrictions_user_key
+        foreign key (user_id) references users (id)
+            on delete cascade
+);
\ No newline at end of file
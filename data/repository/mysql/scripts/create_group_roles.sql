@@ -0,0 +1,13 @@
+create table if not exists group_roles
+(
+    id          int auto_increment
+        primary key,
+    group_id    int           not null,
+    name        varchar(45)   not null,
+    permissions bigint        not null,
+    constraint group_roles_group_name_uindex
+        unique (group_id, name),
+    constraint group_roles_group_key
+        foreign key (group_id) references `groups` (id)
+            on delete cascade
+);
\ No newline at end of file
@@ -1,11 +1,19 @@
 create table if not exists `groups`
 (
-    id      int auto_increment
+    id                 int auto_increment
         primary key,
-    chat_id int         not null,
-    name    varchar(45) not null,
+    chat_id            int                     not null,
+    name               varchar(45)             not null,
+    slow_mode_sec      int          default 0  not null,
+    description        varchar(255) default '' not null,
+    avatar             varchar(64)  default '' not null,
+    only_admins_post   tinyint(1)   default 0  not null,
+    members_can_invite tinyint(1)   default 0  not null,
+    username           varchar(32)             null,
     constraint chat_id_UNIQUE
         unique (chat_id),
+    constraint groups_username_uindex
+        unique (username),
     constraint groups_chat_key
         foreign key (chat_id) references chats (id)
             on delete cascade
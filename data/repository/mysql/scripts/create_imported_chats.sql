@@ -0,0 +1,10 @@
+create table if not exists imported_chats
+(
+    source_id   varchar(255)                       not null
+        primary key,
+    chat_id     int                                not null,
+    create_time datetime default CURRENT_TIMESTAMP not null,
+    constraint imported_chats_chat_key
+        foreign key (chat_id) references chats (id)
+            on delete cascade
+);
\ No newline at end of file
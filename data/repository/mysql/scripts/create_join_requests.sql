@@ -0,0 +1,20 @@
+create table if not exists join_requests
+(
+    id          int auto_increment
+        primary key,
+    group_id    int                                not null,
+    user_id     int                                not null,
+    invite_id   int                                not null,
+    create_time datetime default CURRENT_TIMESTAMP not null,
+    constraint group_user_idx
+        unique (group_id, user_id),
+    constraint join_requests_group_key
+        foreign key (group_id) references `groups` (id)
+            on delete cascade,
+    constraint join_requests_user_key
+        foreign key (user_id) references users (id)
+            on delete cascade,
+    constraint join_requests_invite_key
+        foreign key (invite_id) references group_invites (id)
+            on delete cascade
+);
\ No newline at end of file
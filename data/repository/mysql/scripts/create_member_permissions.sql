@@ -0,0 +1,17 @@
+create table if not exists member_permissions
+(
+    id       int auto_increment
+        primary key,
+    group_id int              not null,
+    user_id  int              not null,
+    allow    bigint default 0 not null,
+    deny     bigint default 0 not null,
+    constraint member_permissions_group_user_uindex
+        unique (group_id, user_id),
+    constraint member_permissions_group_key
+        foreign key (group_id) references `groups` (id)
+            on delete cascade,
+    constraint member_permissions_user_key
+        foreign key (user_id) references users (id)
+            on delete cascade
+);
\ No newline at end of file
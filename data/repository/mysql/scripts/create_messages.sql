@@ -1,15 +1,21 @@
 create table if not exists messages
 (
-    id      int auto_increment
+    id         int auto_increment
         primary key,
-    chat_id int                not null,
-    user_id int                not null,
-    value   text charset utf32 not null,
-    time    datetime           not null,
+    chat_id    int                         not null,
+    user_id    int                         not null,
+    value      text charset utf32          not null,
+    time       datetime                    not null,
+    kind       varchar(16) default 'text'  not null,
+    sticker_id int                         null,
+    payload    json                        null,
     constraint messages_chat_key
         foreign key (chat_id) references chats (id)
             on delete cascade,
     constraint messages_user_key
         foreign key (user_id) references users (id)
-            on delete cascade
+            on delete cascade,
+    constraint messages_sticker_key
+        foreign key (sticker_id) references stickers (id)
+            on delete set null
 );
\ No newline at end of file
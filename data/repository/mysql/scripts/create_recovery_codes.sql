@@ -0,0 +1,12 @@
+create table if not exists recovery_codes
+(
+    id        int auto_increment
+        primary key,
+    user_id   int         not null,
+    code_hash char(64)    not null,
+    constraint recovery_codes_user_key
+        foreign key (user_id) references users (id)
+            on delete cascade,
+    constraint recovery_codes_code_key
+        unique (user_id, code_hash)
+);
\ No newline at end of file
@@ -0,0 +1,14 @@
+create table if not exists security_events
+(
+    id          int auto_increment
+        primary key,
+    user_id     int                                not null,
+    type        varchar(32)                        not null,
+    session_id  int                                not null,
+    ip          varchar(45)                        not null,
+    user_agent  varchar(255)                       not null,
+    create_time datetime default CURRENT_TIMESTAMP not null,
+    constraint security_events_user_key
+        foreign key (user_id) references users (id)
+            on delete cascade
+);
\ No newline at end of file
@@ -0,0 +1,15 @@
+create table if not exists sessions
+(
+    id             int auto_increment
+        primary key,
+    user_id        int                                not null,
+    device_name    varchar(64)                        not null,
+    user_agent     varchar(255)                       not null,
+    ip             varchar(45)                        not null,
+    create_time    datetime default CURRENT_TIMESTAMP not null,
+    last_used_time datetime default CURRENT_TIMESTAMP not null,
+    expire_time    datetime                           not null,
+    constraint sessions_user_key
+        foreign key (user_id) references users (id)
+            on delete cascade
+);
\ No newline at end of file
@@ -0,0 +1,13 @@
+create table if not exists sms_messages
+(
+    id                  int auto_increment
+        primary key,
+    phone               varchar(16)                        not null,
+    status              varchar(16)                        not null,
+    provider            varchar(32)                        not null,
+    provider_message_id varchar(64)                        not null,
+    attempts            int                                not null,
+    error               varchar(255)                       not null,
+    create_time         datetime default CURRENT_TIMESTAMP not null,
+    update_time         datetime default CURRENT_TIMESTAMP not null
+);
\ No newline at end of file
@@ -0,0 +1,11 @@
+create table if not exists sticker_packs
+(
+    id          int auto_increment
+        primary key,
+    owner_id    int                                not null,
+    name        varchar(64)                        not null,
+    create_time datetime default CURRENT_TIMESTAMP not null,
+    constraint sticker_packs_owner_key
+        foreign key (owner_id) references users (id)
+            on delete cascade
+);
\ No newline at end of file
@@ -0,0 +1,11 @@
+create table if not exists stickers
+(
+    id      int auto_increment
+        primary key,
+    pack_id int                 not null,
+    emoji   varchar(16)         not null,
+    image   varchar(64)         not null,
+    constraint stickers_pack_key
+        foreign key (pack_id) references sticker_packs (id)
+            on delete cascade
+);
\ No newline at end of file
@@ -0,0 +1,15 @@
+create table if not exists user_2_sticker_pack
+(
+    id      int auto_increment
+        primary key,
+    user_id int not null,
+    pack_id int not null,
+    constraint user_pack_idx
+        unique (user_id, pack_id),
+    constraint user_2_sticker_pack_user_key
+        foreign key (user_id) references users (id)
+            on delete cascade,
+    constraint user_2_sticker_pack_pack_key
+        foreign key (pack_id) references sticker_packs (id)
+            on delete cascade
+);
\ No newline at end of file
@@ -0,0 +1,12 @@
+create table if not exists user_totp
+(
+    user_id     int                                  not null
+        primary key,
+    secret      varchar(64)                          not null,
+    enabled     tinyint(1) default 0                 not null,
+    last_step   bigint     default 0                 not null,
+    create_time datetime   default CURRENT_TIMESTAMP not null,
+    constraint user_totp_user_key
+        foreign key (user_id) references users (id)
+            on delete cascade
+);
\ No newline at end of file
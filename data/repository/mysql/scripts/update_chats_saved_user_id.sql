@@ -0,0 +1,7 @@
+update chats c
+    inner join (select uc.user_id, min(uc.chat_id) as chat_id
+                from user_2_chat uc
+                         inner join chats s on s.id = uc.chat_id
+                where s.type = 'saved'
+                group by uc.user_id) saved on saved.chat_id = c.id
+set c.saved_user_id = saved.user_id;
\ No newline at end of file
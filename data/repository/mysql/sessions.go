@@ -0,0 +1,121 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	errorsutils "errors"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+type Sessions struct {
+	DB
+}
+
+func NewSessions(db DB) (*Sessions, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_sessions.sql"); err != nil {
+		return nil, errorsutils.New("create table sessions error: " + err.Error())
+	}
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_security_events.sql"); err != nil {
+		return nil, errorsutils.New("create table security_events error: " + err.Error())
+	}
+	return &Sessions{db}, nil
+}
+
+func (s *Sessions) New(ctx context.Context, session *models.Session) *errors.Error {
+	res, err := s.DB.ExecContext(ctx, "INSERT INTO sessions (user_id, device_name, user_agent, ip, create_time, last_used_time, expire_time) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		session.UserId, session.DeviceName, session.UserAgent, session.IP, session.CreateTime, session.LastUsedTime, session.ExpireTime)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	session.Id = int(id)
+	return nil
+}
+
+// GetById returns not found error for revoked and expired sessions
+func (s *Sessions) GetById(ctx context.Context, id int) (*models.Session, *errors.Error) {
+	session := new(models.Session)
+	if err := session.ScanRow(s.DB.QueryRowContext(ctx, "SELECT * FROM sessions WHERE id = ? AND expire_time > ?", id, time.Now())); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "session not found", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return session, nil
+}
+
+func (s *Sessions) GetByUser(ctx context.Context, userId int) ([]models.Session, *errors.Error) {
+	rows, err := s.DB.QueryContext(ctx, "SELECT * FROM sessions WHERE user_id = ? AND expire_time > ? ORDER BY last_used_time DESC", userId, time.Now())
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	sessions := make([]models.Session, 0)
+	for rows.Next() {
+		var session models.Session
+		if err := session.ScanRow(rows); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// Touch updates session on token refresh
+func (s *Sessions) Touch(ctx context.Context, session *models.Session) *errors.Error {
+	if _, err := s.DB.ExecContext(ctx, "UPDATE sessions SET user_agent = ?, ip = ?, last_used_time = ?, expire_time = ? WHERE id = ?",
+		session.UserAgent, session.IP, session.LastUsedTime, session.ExpireTime, session.Id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (s *Sessions) Delete(ctx context.Context, id int) *errors.Error {
+	if _, err := s.DB.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (s *Sessions) NewSecurityEvent(ctx context.Context, event *models.SecurityEvent) *errors.Error {
+	event.CreateTime = time.Now()
+	res, err := s.DB.ExecContext(ctx, "INSERT INTO security_events (user_id, type, session_id, ip, user_agent, create_time) VALUES (?, ?, ?, ?, ?, ?)",
+		event.UserId, event.Type, event.SessionId, event.IP, event.UserAgent, event.CreateTime)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	event.Id = int(id)
+	return nil
+}
+
+func (s *Sessions) GetSecurityEvents(ctx context.Context, userId int, count int) ([]models.SecurityEvent, *errors.Error) {
+	rows, err := s.DB.QueryContext(ctx, "SELECT * FROM security_events WHERE user_id = ? ORDER BY id DESC LIMIT ?", userId, count)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	events := make([]models.SecurityEvent, 0)
+	for rows.Next() {
+		var event models.SecurityEvent
+		if err := event.ScanRow(rows); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"context"
+	errorsutils "errors"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+type SmsMessages struct {
+	DB
+}
+
+func NewSmsMessages(db DB) (*SmsMessages, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_sms_messages.sql"); err != nil {
+		return nil, errorsutils.New("create table sms_messages error: " + err.Error())
+	}
+	return &SmsMessages{db}, nil
+}
+
+func (s *SmsMessages) New(ctx context.Context, msg *models.SmsMessage) *errors.Error {
+	msg.CreateTime = time.Now()
+	msg.UpdateTime = msg.CreateTime
+	res, err := s.DB.ExecContext(ctx, "INSERT INTO sms_messages (phone, status, provider, provider_message_id, attempts, error, create_time, update_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		msg.Phone, msg.Status, msg.Provider, msg.ProviderMessageId, msg.Attempts, msg.Error, msg.CreateTime, msg.UpdateTime)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	msg.Id = int(id)
+	return nil
+}
+
+// Update saves send status of message
+func (s *SmsMessages) Update(ctx context.Context, msg *models.SmsMessage) *errors.Error {
+	msg.UpdateTime = time.Now()
+	if _, err := s.DB.ExecContext(ctx, "UPDATE sms_messages SET status = ?, provider = ?, provider_message_id = ?, attempts = ?, error = ?, update_time = ? WHERE id = ?",
+		msg.Status, msg.Provider, msg.ProviderMessageId, msg.Attempts, msg.Error, msg.UpdateTime, msg.Id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
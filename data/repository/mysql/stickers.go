@@ -0,0 +1,188 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	errorsutils "errors"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+type Stickers struct {
+	DB
+}
+
+func NewStickers(db DB) (*Stickers, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_sticker_packs.sql"); err != nil {
+		return nil, errorsutils.New("create table sticker_packs error: " + err.Error())
+	}
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_stickers.sql"); err != nil {
+		return nil, errorsutils.New("create table stickers error: " + err.Error())
+	}
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_user_2_sticker_pack.sql"); err != nil {
+		return nil, errorsutils.New("create table user_2_sticker_pack error: " + err.Error())
+	}
+
+	return &Stickers{db}, nil
+}
+
+func (s *Stickers) NewPack(ctx context.Context, pack *models.StickerPack) *errors.Error {
+	pack.CreateTime = time.Now()
+	res, err := s.DB.ExecContext(ctx, "INSERT INTO sticker_packs (owner_id, name, create_time) VALUES (?, ?, ?)",
+		pack.OwnerId, pack.Name, pack.CreateTime)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	pack.Id = int(id)
+	return nil
+}
+
+func (s *Stickers) GetPackById(ctx context.Context, id int) (*models.StickerPack, *errors.Error) {
+	pack := new(models.StickerPack)
+	if err := s.DB.QueryRowContext(ctx, "SELECT id, owner_id, name, create_time FROM sticker_packs WHERE id = ?", id).Scan(
+		&pack.Id, &pack.OwnerId, &pack.Name, &pack.CreateTime); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "sticker pack not found", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+
+	stickers, err := s.getStickersByPack(ctx, pack.Id)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	pack.Stickers = stickers
+	return pack, nil
+}
+
+func (s *Stickers) getStickersByPack(ctx context.Context, packId int) ([]models.Sticker, *errors.Error) {
+	rows, err := s.DB.QueryContext(ctx, "SELECT id, pack_id, emoji, image FROM stickers WHERE pack_id = ? ORDER BY id", packId)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	stickers := make([]models.Sticker, 0)
+	for rows.Next() {
+		var sticker models.Sticker
+		if err := rows.Scan(&sticker.Id, &sticker.PackId, &sticker.Emoji, &sticker.Image); err != nil {
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		stickers = append(stickers, sticker)
+	}
+	return stickers, nil
+}
+
+func (s *Stickers) DeletePack(ctx context.Context, id int) *errors.Error {
+	if _, err := s.DB.ExecContext(ctx, "DELETE FROM sticker_packs WHERE id = ?", id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (s *Stickers) AddSticker(ctx context.Context, sticker *models.Sticker) *errors.Error {
+	res, err := s.DB.ExecContext(ctx, "INSERT INTO stickers (pack_id, emoji, image) VALUES (?, ?, ?)",
+		sticker.PackId, sticker.Emoji, sticker.Image)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	sticker.Id = int(id)
+	return nil
+}
+
+func (s *Stickers) GetStickerById(ctx context.Context, id int) (*models.Sticker, *errors.Error) {
+	sticker := new(models.Sticker)
+	if err := s.DB.QueryRowContext(ctx, "SELECT id, pack_id, emoji, image FROM stickers WHERE id = ?", id).Scan(
+		&sticker.Id, &sticker.PackId, &sticker.Emoji, &sticker.Image); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "sticker not found", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return sticker, nil
+}
+
+func (s *Stickers) DeleteSticker(ctx context.Context, id int) *errors.Error {
+	if _, err := s.DB.ExecContext(ctx, "DELETE FROM stickers WHERE id = ?", id); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (s *Stickers) InstallPack(ctx context.Context, userId int, packId int) *errors.Error {
+	if _, err := s.DB.ExecContext(ctx, "INSERT INTO user_2_sticker_pack (user_id, pack_id) VALUES (?, ?)", userId, packId); err != nil {
+		if isDuplicateEntry(err) {
+			return errors.New(err, "sticker pack already installed", http.StatusBadRequest)
+		}
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (s *Stickers) UninstallPack(ctx context.Context, userId int, packId int) *errors.Error {
+	if _, err := s.DB.ExecContext(ctx, "DELETE FROM user_2_sticker_pack WHERE user_id = ? AND pack_id = ?", userId, packId); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (s *Stickers) CheckPackInstalled(ctx context.Context, userId int, packId int) (bool, *errors.Error) {
+	var exist bool
+	if err := s.DB.QueryRowContext(ctx, "SELECT COUNT(id) != 0 FROM user_2_sticker_pack WHERE user_id = ? AND pack_id = ?",
+		userId, packId).Scan(&exist); err != nil {
+		return false, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return exist, nil
+}
+
+const getInstalledPacksQuery = `
+SELECT
+    p.id,
+    p.owner_id,
+    p.name,
+    p.create_time
+FROM user_2_sticker_pack up
+INNER JOIN sticker_packs p ON p.id = up.pack_id
+WHERE up.user_id = ?
+ORDER BY up.id
+`
+
+func (s *Stickers) GetInstalledPacks(ctx context.Context, userId int) ([]models.StickerPack, *errors.Error) {
+	rows, err := s.DB.QueryContext(ctx, getInstalledPacksQuery, userId)
+	if err != nil {
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+
+	packs := make([]models.StickerPack, 0)
+	for rows.Next() {
+		var pack models.StickerPack
+		if err := rows.Scan(&pack.Id, &pack.OwnerId, &pack.Name, &pack.CreateTime); err != nil {
+			rows.Close()
+			return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+		}
+		packs = append(packs, pack)
+	}
+	rows.Close()
+
+	for i := range packs {
+		stickers, err := s.getStickersByPack(ctx, packs[i].Id)
+		if err != nil {
+			return nil, err.Trace()
+		}
+		packs[i].Stickers = stickers
+	}
+	return packs, nil
+}
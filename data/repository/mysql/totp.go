@@ -0,0 +1,122 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	errorsutils "errors"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type TOTP struct {
+	DB
+}
+
+func NewTOTP(db DB) (*TOTP, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_user_totp.sql"); err != nil {
+		return nil, errorsutils.New("create table user_totp error: " + err.Error())
+	}
+	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_recovery_codes.sql"); err != nil {
+		return nil, errorsutils.New("create table recovery_codes error: " + err.Error())
+	}
+	return &TOTP{db}, nil
+}
+
+// Set replaces secret of user, new secret is disabled until enrollment is confirmed
+func (t *TOTP) Set(ctx context.Context, totp *models.TOTP) *errors.Error {
+	totp.Enabled = false
+	totp.LastStep = 0
+	totp.CreateTime = time.Now()
+	if _, err := t.DB.ExecContext(ctx, "INSERT INTO user_totp (user_id, secret, enabled, last_step, create_time) VALUES (?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE secret = VALUES(secret), enabled = VALUES(enabled), last_step = VALUES(last_step), create_time = VALUES(create_time)",
+		totp.UserId, totp.Secret, totp.Enabled, totp.LastStep, totp.CreateTime); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (t *TOTP) Get(ctx context.Context, userId int) (*models.TOTP, *errors.Error) {
+	totp := new(models.TOTP)
+	if err := totp.ScanRow(t.DB.QueryRowContext(ctx, "SELECT * FROM user_totp WHERE user_id = ?", userId)); err != nil {
+		if errorsutils.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(err, "authenticator app is not enrolled", http.StatusNotFound)
+		}
+		return nil, errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return totp, nil
+}
+
+func (t *TOTP) Enable(ctx context.Context, userId int) *errors.Error {
+	if _, err := t.DB.ExecContext(ctx, "UPDATE user_totp SET enabled = 1 WHERE user_id = ?", userId); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// UseStep marks code of step as used, returns unauthorized error if the same or later code was already used
+func (t *TOTP) UseStep(ctx context.Context, userId int, step int64) *errors.Error {
+	res, err := t.DB.ExecContext(ctx, "UPDATE user_totp SET last_step = ? WHERE user_id = ? AND last_step < ?", step, userId, step)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errors.New1Msg("code already used", http.StatusUnauthorized)
+	}
+	return nil
+}
+
+// Delete removes secret and recovery codes of user
+func (t *TOTP) Delete(ctx context.Context, userId int) *errors.Error {
+	if _, err := t.DB.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?", userId); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	if _, err := t.DB.ExecContext(ctx, "DELETE FROM user_totp WHERE user_id = ?", userId); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// SetRecoveryCodes replaces recovery codes of user with hashes
+func (t *TOTP) SetRecoveryCodes(ctx context.Context, userId int, hashes []string) *errors.Error {
+	if _, err := t.DB.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?", userId); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	args := make([]any, 0, 2*len(hashes))
+	for _, hash := range hashes {
+		args = append(args, userId, hash)
+	}
+	query := "INSERT INTO recovery_codes (user_id, code_hash) VALUES " + strings.Repeat("(?, ?), ", len(hashes)-1) + "(?, ?)"
+	if _, err := t.DB.ExecContext(ctx, query, args...); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// UseRecoveryCode deletes recovery code, returns unauthorized error if user has no such code
+func (t *TOTP) UseRecoveryCode(ctx context.Context, userId int, hash string) *errors.Error {
+	res, err := t.DB.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ? AND code_hash = ?", userId, hash)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errors.New1Msg("invalid recovery code", http.StatusUnauthorized)
+	}
+	return nil
+}
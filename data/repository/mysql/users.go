@@ -2,7 +2,6 @@ package mysql
 
 import (
 	"context"
-	"crypto/sha256"
 	"database/sql"
 	errorsutils "errors"
 	"messanger/domain/models"
@@ -22,6 +21,12 @@ func NewUsers(db DB) (*Users, error) {
 	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_users.sql"); err != nil {
 		return nil, errorsutils.New("create table users error: " + err.Error())
 	}
+	if err := migrateColumn(ctx, db, "users", "email", "data/repository/mysql/scripts/alter_users_add_email.sql"); err != nil {
+		return nil, errorsutils.New("add column users.email error: " + err.Error())
+	}
+	if err := migrateColumn(ctx, db, "users", "email_confirmed", "data/repository/mysql/scripts/alter_users_add_email_confirmed.sql"); err != nil {
+		return nil, errorsutils.New("add column users.email_confirmed error: " + err.Error())
+	}
 	if err := openAndExec(ctx, db, "data/repository/mysql/scripts/create_user_2_chat.sql"); err != nil {
 		return nil, errorsutils.New("create table user_2_chat error: " + err.Error())
 	}
@@ -31,7 +36,7 @@ func NewUsers(db DB) (*Users, error) {
 
 func (u *Users) New(ctx context.Context, user *models.User) *errors.Error {
 	res, err := u.DB.ExecContext(ctx, "INSERT INTO users (phone, password, name, real_name, show_phone) VALUES (?, ?, ?, ?, ?)",
-		user.Phone, hashPassword(user.Password), user.Name, user.RealName, user.ShowPhone)
+		user.Phone, user.Password, user.Name, user.RealName, user.ShowPhone)
 
 	if err != nil {
 		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
@@ -62,8 +67,9 @@ func (u *Users) UpdateRealName(ctx context.Context, userId int, realName string)
 	return nil
 }
 
-func (u *Users) UpdatePassword(ctx context.Context, userId int, password string) *errors.Error {
-	if _, err := u.DB.ExecContext(ctx, "UPDATE users SET password = ? WHERE id = ?", hashPassword(password), userId); err != nil {
+// UpdatePassword stores password hash of user
+func (u *Users) UpdatePassword(ctx context.Context, userId int, hash string) *errors.Error {
+	if _, err := u.DB.ExecContext(ctx, "UPDATE users SET password = ? WHERE id = ?", hash, userId); err != nil {
 		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
 	}
 	return nil
@@ -76,6 +82,26 @@ func (u *Users) UpdatePhone(ctx context.Context, userId int, phone string) *erro
 	return nil
 }
 
+// UpdateEmail sets unconfirmed email of user, empty email removes it
+func (u *Users) UpdateEmail(ctx context.Context, userId int, email string) *errors.Error {
+	if _, err := u.DB.ExecContext(ctx, "UPDATE users SET email = ?, email_confirmed = 0 WHERE id = ?", email, userId); err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// ConfirmEmail confirms email of user if it was not changed after code was sent
+func (u *Users) ConfirmEmail(ctx context.Context, userId int, email string) *errors.Error {
+	res, err := u.DB.ExecContext(ctx, "UPDATE users SET email_confirmed = 1 WHERE id = ? AND email = ?", userId, email)
+	if err != nil {
+		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New1Msg("email was changed", http.StatusConflict)
+	}
+	return nil
+}
+
 func (u *Users) UpdateLastOnlineTime(ctx context.Context, userId int, time time.Time) *errors.Error {
 	if _, err := u.DB.ExecContext(ctx, "UPDATE users SET last_online = ? WHERE id = ?", time, userId); err != nil {
 		return errors.New(err, models.ErrDatabaseError, http.StatusInternalServerError)
@@ -123,10 +149,10 @@ func (u *Users) FindByName(ctx context.Context, name string) (*models.User, *err
 	return &user, nil
 }
 
-func (u *Users) GetByPhoneWithPass(ctx context.Context, phone, password string) (*models.User, *errors.Error) {
+// GetByPhoneWithPass returns confirmed user with password hash, password is verified by caller
+func (u *Users) GetByPhoneWithPass(ctx context.Context, phone string) (*models.User, *errors.Error) {
 	user := new(models.User)
-	if err := user.ScanRow(u.DB.QueryRowContext(ctx, "SELECT * FROM users WHERE phone = ? AND password = ? AND confirmed",
-		phone, hashPassword(password))); err != nil {
+	if err := user.ScanRow(u.DB.QueryRowContext(ctx, "SELECT * FROM users WHERE phone = ? AND confirmed", phone)); err != nil {
 		if errorsutils.Is(err, sql.ErrNoRows) {
 			return nil, errors.New(err, "invalid phone or password", http.StatusUnauthorized)
 		}
@@ -135,10 +161,10 @@ func (u *Users) GetByPhoneWithPass(ctx context.Context, phone, password string)
 	return user, nil
 }
 
-func (u *Users) GetByIdWithPass(ctx context.Context, id int, password string) (*models.User, *errors.Error) {
+// GetByIdWithPass returns confirmed user with password hash, password is verified by caller
+func (u *Users) GetByIdWithPass(ctx context.Context, id int) (*models.User, *errors.Error) {
 	user := new(models.User)
-	if err := user.ScanRow(u.DB.QueryRowContext(ctx, "SELECT * FROM users WHERE id = ? AND password = ? AND confirmed",
-		id, hashPassword(password))); err != nil {
+	if err := user.ScanRow(u.DB.QueryRowContext(ctx, "SELECT * FROM users WHERE id = ? AND confirmed", id)); err != nil {
 		if errorsutils.Is(err, sql.ErrNoRows) {
 			return nil, errors.New(err, "invalid password", http.StatusUnauthorized)
 		}
@@ -164,11 +190,3 @@ func (u *Users) GetLastOnline(ctx context.Context, userId int) (time.Time, *erro
 	}
 	return t, nil
 }
-
-var passwordSalt = []byte("nwy9837ctp5")
-
-func hashPassword(password string) []byte {
-	h := sha256.New()
-	h.Write([]byte(password))
-	return h.Sum(passwordSalt)
-}
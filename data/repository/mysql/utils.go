@@ -2,8 +2,12 @@ package mysql
 
 import (
 	"context"
+	errorsutils "errors"
+	"github.com/go-sql-driver/mysql"
 	"io"
+	"messanger/domain/models"
 	"os"
+	"strings"
 )
 
 func openAndExec(ctx context.Context, db DB, filepath string) error {
@@ -23,3 +27,55 @@ func openAndExec(ctx context.Context, db DB, filepath string) error {
 	}
 	return nil
 }
+
+// migrateColumn runs scripts if column is missing, tables created by older scripts are not changed by "create table if not exists"
+func migrateColumn(ctx context.Context, db DB, table string, column string, scripts ...string) error {
+	return migrate(ctx, db, "SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?",
+		table, column, scripts)
+}
+
+// migrateConstraint runs scripts if constraint of table is missing
+func migrateConstraint(ctx context.Context, db DB, table string, constraint string, scripts ...string) error {
+	return migrate(ctx, db, "SELECT COUNT(*) FROM information_schema.table_constraints WHERE table_schema = DATABASE() AND table_name = ? AND constraint_name = ?",
+		table, constraint, scripts)
+}
+
+func migrate(ctx context.Context, db DB, query string, table string, name string, scripts []string) error {
+	var count int
+	if err := db.QueryRowContext(ctx, query, table, name).Scan(&count); err != nil {
+		return err
+	}
+	if count != 0 {
+		return nil
+	}
+	for _, script := range scripts {
+		if err := openAndExec(ctx, db, script); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const errDuplicateEntry = 1062
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errorsutils.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry
+}
+
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns LIKE pattern matching strings which contain s
+func containsPattern(s string) string {
+	return "%" + likeReplacer.Replace(s) + "%"
+}
+
+// rowWithTail scans columns selected after model columns into tail
+type rowWithTail struct {
+	models.RowScanner
+	tail []any
+}
+
+func (r rowWithTail) Scan(dest ...any) error {
+	return r.RowScanner.Scan(append(dest, r.tail...)...)
+}
@@ -1,6 +1,7 @@
 package sms
 
 import (
+	"context"
 	"fmt"
 	"messanger/pkg/errors"
 )
@@ -12,7 +13,7 @@ func NewCmdSmsAdapter() *CmdSmsAdapter {
 	return &CmdSmsAdapter{}
 }
 
-func (s *CmdSmsAdapter) Send(to string, text string) *errors.Error {
+func (s *CmdSmsAdapter) Send(_ context.Context, to string, text string) *errors.Error {
 	fmt.Printf("Sms Sender: to: %s, text: %s\n", to, text)
 	return nil
 }
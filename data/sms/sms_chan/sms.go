@@ -1,8 +1,10 @@
 package sms
 
 import (
+	"context"
 	"fmt"
 	"messanger/pkg/errors"
+	"regexp"
 )
 
 type SmsChan struct {
@@ -15,8 +17,15 @@ func NewSmsChan() *SmsChan {
 	}
 }
 
-func (s *SmsChan) Send(to string, msg string) *errors.Error {
+func (s *SmsChan) Send(_ context.Context, to string, msg string) *errors.Error {
 	s.Chan <- msg
 	fmt.Println(to, msg)
 	return nil
 }
+
+var codeRegexp = regexp.MustCompile(`\d+`)
+
+// Code waits for next message and returns code from its text
+func (s *SmsChan) Code() string {
+	return codeRegexp.FindString(<-s.Chan)
+}
@@ -0,0 +1,116 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"messanger/config"
+	"messanger/pkg/errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ErrSmsRu       = "sms.ru error"
+	defaultBaseURL = "https://sms.ru"
+
+	statusOK = "OK"
+	// codeUnavailable is returned when service is temporarily unavailable
+	codeUnavailable = 220
+)
+
+// SmsRuClient sends sms with sms.ru API, token is api_id of account
+type SmsRuClient struct {
+	client  *http.Client
+	baseURL string
+	token   string
+	from    string
+}
+
+func NewSmsRuClient(cfg *config.SmsProviderConfig, timeout time.Duration) *SmsRuClient {
+	baseURL := cfg.BaseURL
+	if len(baseURL) == 0 {
+		baseURL = defaultBaseURL
+	}
+	return &SmsRuClient{
+		client:  &http.Client{Timeout: timeout},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   cfg.Token,
+		from:    cfg.From,
+	}
+}
+
+func (c *SmsRuClient) Name() string {
+	return "smsru"
+}
+
+type smsRuStatus struct {
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+	StatusText string `json:"status_text"`
+}
+
+type smsRuResponse struct {
+	smsRuStatus
+	Sms map[string]struct {
+		smsRuStatus
+		SmsId string `json:"sms_id"`
+	} `json:"sms"`
+}
+
+func (s smsRuStatus) err() *errors.Error {
+	if s.Status == statusOK {
+		return nil
+	}
+	code := http.StatusBadRequest
+	if s.StatusCode == codeUnavailable {
+		code = http.StatusServiceUnavailable
+	}
+	return errors.New(fmt.Sprintf("status code %d: %s", s.StatusCode, s.StatusText), ErrSmsRu, code)
+}
+
+// Send returns sms_id of message
+func (c *SmsRuClient) Send(ctx context.Context, phone string, text string) (string, *errors.Error) {
+	to := strings.TrimPrefix(phone, "+")
+	form := url.Values{"api_id": {c.token}, "to": {to}, "msg": {text}, "json": {"1"}}
+	if len(c.from) != 0 {
+		form.Set("from", c.from)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/sms/send", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.New(err, ErrSmsRu, http.StatusInternalServerError)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", errors.New(err, ErrSmsRu, http.StatusServiceUnavailable)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		code := http.StatusBadRequest
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			code = resp.StatusCode
+		}
+		return "", errors.New(fmt.Sprintf("status %d", resp.StatusCode), ErrSmsRu, code)
+	}
+
+	var res smsRuResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", errors.New(err, ErrSmsRu, http.StatusBadGateway)
+	}
+	if err := res.err(); err != nil {
+		return "", err.Trace()
+	}
+	sms, ok := res.Sms[to]
+	if !ok {
+		return "", errors.New("no status for "+to, ErrSmsRu, http.StatusBadGateway)
+	}
+	if err := sms.err(); err != nil {
+		return "", err.Trace()
+	}
+	return sms.SmsId, nil
+}
@@ -0,0 +1,54 @@
+package sms
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"messanger/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, response string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/sms/send", r.URL.Path)
+		require.Equal(t, "api-id", r.FormValue("api_id"))
+		require.Equal(t, "79161234567", r.FormValue("to"))
+		require.Equal(t, "123456", r.FormValue("msg"))
+		w.Write([]byte(response))
+	}))
+}
+
+func TestSend(t *testing.T) {
+	server := newTestServer(t, `{"status":"OK","status_code":100,"sms":{"79161234567":{"status":"OK","status_code":100,"sms_id":"000000-10000000"}}}`)
+	defer server.Close()
+
+	client := NewSmsRuClient(&config.SmsProviderConfig{BaseURL: server.URL, Token: "api-id"}, time.Second)
+	id, err := client.Send(context.Background(), "+79161234567", "123456")
+	require.Nil(t, err)
+	require.Equal(t, "000000-10000000", id)
+}
+
+func TestSendErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		response string
+		code     int
+	}{
+		{"invalid phone", `{"status":"OK","status_code":100,"sms":{"79161234567":{"status":"ERROR","status_code":202,"status_text":"invalid phone"}}}`, http.StatusBadRequest},
+		{"invalid api_id", `{"status":"ERROR","status_code":200,"status_text":"invalid api_id"}`, http.StatusBadRequest},
+		{"unavailable", `{"status":"ERROR","status_code":220,"status_text":"service unavailable"}`, http.StatusServiceUnavailable},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := newTestServer(t, c.response)
+			defer server.Close()
+
+			client := NewSmsRuClient(&config.SmsProviderConfig{BaseURL: server.URL, Token: "api-id"}, time.Second)
+			_, err := client.Send(context.Background(), "+79161234567", "123456")
+			require.NotNil(t, err)
+			require.Equal(t, c.code, err.Code)
+		})
+	}
+}
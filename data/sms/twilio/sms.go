@@ -0,0 +1,82 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"messanger/config"
+	"messanger/pkg/errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ErrTwilio      = "twilio error"
+	defaultBaseURL = "https://api.twilio.com"
+)
+
+// TwilioClient sends sms with Twilio Messages API
+type TwilioClient struct {
+	client  *http.Client
+	baseURL string
+	account string
+	token   string
+	from    string
+}
+
+func NewTwilioClient(cfg *config.SmsProviderConfig, timeout time.Duration) *TwilioClient {
+	baseURL := cfg.BaseURL
+	if len(baseURL) == 0 {
+		baseURL = defaultBaseURL
+	}
+	return &TwilioClient{
+		client:  &http.Client{Timeout: timeout},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		account: cfg.Account,
+		token:   cfg.Token,
+		from:    cfg.From,
+	}
+}
+
+func (c *TwilioClient) Name() string {
+	return "twilio"
+}
+
+type twilioResponse struct {
+	Sid     string `json:"sid"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Send returns sid of created message
+func (c *TwilioClient) Send(ctx context.Context, phone string, text string) (string, *errors.Error) {
+	form := url.Values{"To": {phone}, "From": {c.from}, "Body": {text}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.baseURL+"/2010-04-01/Accounts/"+url.PathEscape(c.account)+"/Messages.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.New(err, ErrTwilio, http.StatusInternalServerError)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(c.account, c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", errors.New(err, ErrTwilio, http.StatusServiceUnavailable)
+	}
+	defer resp.Body.Close()
+
+	var res twilioResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&res)
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return "", errors.New(fmt.Sprintf("status %d: %s", resp.StatusCode, res.Message), ErrTwilio, resp.StatusCode)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", errors.New(fmt.Sprintf("status %d, code %d: %s", resp.StatusCode, res.Code, res.Message), ErrTwilio, http.StatusBadRequest)
+	}
+	if decodeErr != nil {
+		return "", errors.New(decodeErr, ErrTwilio, http.StatusBadGateway)
+	}
+	return res.Sid, nil
+}
@@ -0,0 +1,51 @@
+package sms
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"messanger/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+		user, pass, _ := r.BasicAuth()
+		if user != "AC123" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":20003,"message":"Authenticate","status":401}`))
+			return
+		}
+		require.Equal(t, "+79161234567", r.FormValue("To"))
+		require.Equal(t, "+15005550006", r.FormValue("From"))
+		require.Equal(t, "123456", r.FormValue("Body"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM1","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.SmsProviderConfig{BaseURL: server.URL, Account: "AC123", Token: "secret", From: "+15005550006"}
+	id, err := NewTwilioClient(cfg, time.Second).Send(context.Background(), "+79161234567", "123456")
+	require.Nil(t, err)
+	require.Equal(t, "SM1", id)
+
+	cfg.Token = "invalid"
+	_, err = NewTwilioClient(cfg, time.Second).Send(context.Background(), "+79161234567", "123456")
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code, "auth error should not be retried")
+}
+
+func TestSendUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := &config.SmsProviderConfig{BaseURL: server.URL, Account: "AC123"}
+	_, err := NewTwilioClient(cfg, time.Second).Send(context.Background(), "+79161234567", "123456")
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, err.Code)
+}
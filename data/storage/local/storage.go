@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	errorsutils "errors"
+	"github.com/google/uuid"
+	"io"
+	"messanger/pkg/errors"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const ErrStorage = "storage error"
+
+type Storage struct {
+	dir string
+}
+
+func NewStorage(dir string) (*Storage, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Storage{dir: dir}, nil
+}
+
+func (s *Storage) Save(_ context.Context, ext string, r io.Reader) (string, *errors.Error) {
+	name := uuid.NewString() + ext
+
+	file, err := os.Create(filepath.Join(s.dir, name))
+	if err != nil {
+		return "", errors.New(err, ErrStorage, http.StatusInternalServerError)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, r); err != nil {
+		os.Remove(file.Name())
+		return "", errors.New(err, ErrStorage, http.StatusInternalServerError)
+	}
+	return name, nil
+}
+
+func (s *Storage) Open(_ context.Context, name string) (io.ReadSeekCloser, *errors.Error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	file, e := os.Open(path)
+	if e != nil {
+		if errorsutils.Is(e, os.ErrNotExist) {
+			return nil, errors.New(e, "file not found", http.StatusNotFound)
+		}
+		return nil, errors.New(e, ErrStorage, http.StatusInternalServerError)
+	}
+	return file, nil
+}
+
+func (s *Storage) Delete(_ context.Context, name string) *errors.Error {
+	path, err := s.path(name)
+	if err != nil {
+		return err.Trace()
+	}
+	if e := os.Remove(path); e != nil && !errorsutils.Is(e, os.ErrNotExist) {
+		return errors.New(e, ErrStorage, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (s *Storage) path(name string) (string, *errors.Error) {
+	if len(name) == 0 || filepath.Base(name) != name || name == "." || name == ".." {
+		return "", errors.New1Msg("invalid file name", http.StatusBadRequest)
+	}
+	return filepath.Join(s.dir, name), nil
+}
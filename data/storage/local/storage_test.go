@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveOpenDelete(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "files")
+	s, err := NewStorage(dir)
+	require.NoError(t, err)
+
+	name, e := s.Save(ctx, ".png", strings.NewReader("image"))
+	require.Nil(t, e)
+	require.True(t, strings.HasSuffix(name, ".png"))
+	require.FileExists(t, filepath.Join(dir, name))
+
+	file, e := s.Open(ctx, name)
+	require.Nil(t, e)
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, "image", string(data))
+
+	require.Nil(t, s.Delete(ctx, name))
+	require.NoFileExists(t, filepath.Join(dir, name))
+	require.Nil(t, s.Delete(ctx, name), "deleting missing file is not an error")
+
+	_, e = s.Open(ctx, name)
+	require.NotNil(t, e)
+	require.Equal(t, http.StatusNotFound, e.Code)
+}
+
+func TestInvalidName(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	s, err := NewStorage(filepath.Join(root, "files"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "secret"), []byte("secret"), 0644))
+
+	for _, name := range []string{"", ".", "..", "../secret", "a/b", "/etc/passwd"} {
+		_, e := s.Open(ctx, name)
+		require.NotNil(t, e, name)
+		require.Equal(t, http.StatusBadRequest, e.Code, name)
+
+		e = s.Delete(ctx, name)
+		require.NotNil(t, e, name)
+		require.Equal(t, http.StatusBadRequest, e.Code, name)
+	}
+	require.FileExists(t, filepath.Join(root, "secret"))
+}
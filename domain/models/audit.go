@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionAddMember         = "add_member"
+	AuditActionRemoveMember      = "remove_member"
+	AuditActionRoleChange        = "role_change"
+	AuditActionTransferOwnership = "transfer_ownership"
+	AuditActionUpdateInfo        = "update_info"
+	AuditActionSlowMode          = "slow_mode"
+	AuditActionAvatar            = "avatar"
+	AuditActionUsername          = "username"
+	AuditActionDeleteGroup       = "delete_group"
+	AuditActionBan               = "ban"
+	AuditActionUnban             = "unban"
+	AuditActionMute              = "mute"
+	AuditActionUnmute            = "unmute"
+	AuditActionCreateRole        = "create_role"
+	AuditActionUpdateRole        = "update_role"
+	AuditActionDeleteRole        = "delete_role"
+	AuditActionSetPermissions    = "set_permissions"
+	AuditActionCreateInvite      = "create_invite"
+	AuditActionRevokeInvite      = "revoke_invite"
+	AuditActionApproveJoin       = "approve_join"
+	AuditActionRejectJoin        = "reject_join"
+	AuditActionJoin              = "join"
+	AuditActionLeave             = "leave"
+	// AuditActionSuccession is ownership passed to TargetId when owner or the last admin left
+	AuditActionSuccession = "succession"
+)
+
+func ValidateAuditAction(action string) bool {
+	switch action {
+	case AuditActionAddMember, AuditActionRemoveMember, AuditActionRoleChange, AuditActionTransferOwnership,
+		AuditActionUpdateInfo, AuditActionSlowMode, AuditActionAvatar, AuditActionUsername, AuditActionDeleteGroup,
+		AuditActionBan, AuditActionUnban, AuditActionMute, AuditActionUnmute,
+		AuditActionCreateRole, AuditActionUpdateRole, AuditActionDeleteRole, AuditActionSetPermissions,
+		AuditActionCreateInvite, AuditActionRevokeInvite, AuditActionApproveJoin, AuditActionRejectJoin,
+		AuditActionJoin, AuditActionLeave, AuditActionSuccession:
+		return true
+	}
+	return false
+}
+
+// AuditEntry is administrative action in group, Before and After contain changed values as JSON
+type AuditEntry struct {
+	Id         int             `json:"id"`
+	GroupId    int             `json:"group_id"`
+	ActorId    int             `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetId   int             `json:"target_id,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreateTime time.Time       `json:"create_time"`
+}
+
+func (e *AuditEntry) ScanRow(row RowScanner) error {
+	var before, after []byte
+	if err := row.Scan(
+		&e.Id,
+		&e.GroupId,
+		&e.ActorId,
+		&e.Action,
+		&e.TargetId,
+		&before,
+		&after,
+		&e.CreateTime,
+	); err != nil {
+		return err
+	}
+	e.Before = before
+	e.After = after
+	return nil
+}
+
+// AuditFilter selects audit entries of group, zero fields are not filtered.
+// Entries are returned from newest, LastId is id of the last entry of previous page
+type AuditFilter struct {
+	GroupId  int
+	ActorId  int
+	TargetId int
+	Action   string
+	LastId   int
+	Count    int
+}
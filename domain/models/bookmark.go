@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type Bookmark struct {
+	Id         int       `json:"id"`
+	UserId     int       `json:"user_id"`
+	MessageId  int       `json:"message_id"`
+	CreateTime time.Time `json:"create_time"`
+	Message    *Message  `json:"message,omitempty"`
+}
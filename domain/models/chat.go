@@ -10,6 +10,8 @@ type Chat struct {
 }
 
 const (
-	ChatTypeGroup = "group"
-	ChatTypeUser  = "user"
+	ChatTypeGroup   = "group"
+	ChatTypeUser    = "user"
+	ChatTypeSaved   = "saved"
+	ChatTypeChannel = "channel"
 )
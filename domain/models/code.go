@@ -0,0 +1,24 @@
+package models
+
+// Purposes of verification codes, code sent for one purpose can't be used for another
+const (
+	CodePurposeRegistration = "registration"
+	CodePurposeLogin        = "login"
+	CodePurposePhoneChange  = "phone_change"
+	CodePurposeReset        = "reset"
+	CodePurposeEmail        = "email"
+)
+
+// Channels which deliver codes of login and password reset
+const (
+	CodeChannelSMS   = "sms"
+	CodeChannelEmail = "email"
+)
+
+func ValidateCodePurpose(purpose string) bool {
+	switch purpose {
+	case CodePurposeRegistration, CodePurposeLogin, CodePurposePhoneChange, CodePurposeReset, CodePurposeEmail:
+		return true
+	}
+	return false
+}
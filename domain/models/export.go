@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ExportJob struct {
+	Id          string     `json:"id"`
+	UserId      int        `json:"user_id"`
+	ChatId      int        `json:"chat_id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	Total       int        `json:"total"`
+	Processed   int        `json:"processed"`
+	Error       string     `json:"error,omitempty"`
+	DownloadUrl string     `json:"download_url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreateTime  time.Time  `json:"create_time"`
+	// Token authorizes download of finished export, it is not shown in job
+	Token      string    `json:"-"`
+	UpdateTime time.Time `json:"-"`
+}
+
+func (j *ExportJob) ScanRow(row RowScanner) error {
+	var token sql.NullString
+	var expiresAt sql.NullTime
+	if err := row.Scan(
+		&j.Id,
+		&j.UserId,
+		&j.ChatId,
+		&j.Format,
+		&j.Status,
+		&j.Total,
+		&j.Processed,
+		&j.Error,
+		&token,
+		&expiresAt,
+		&j.CreateTime,
+		&j.UpdateTime,
+	); err != nil {
+		return err
+	}
+	j.Token = token.String
+	j.ExpiresAt = nil
+	if expiresAt.Valid {
+		j.ExpiresAt = &expiresAt.Time
+	}
+	return nil
+}
+
+const (
+	ExportFormatJSON = "json"
+	ExportFormatHTML = "html"
+)
+
+const (
+	ExportStatusRunning = "running"
+	ExportStatusDone    = "done"
+	ExportStatusFailed  = "failed"
+)
+
+func ValidateExportFormat(format string) bool {
+	switch format {
+	case ExportFormatJSON, ExportFormatHTML:
+		return true
+	}
+	return false
+}
@@ -1,7 +1,63 @@
 package models
 
+import (
+	"database/sql"
+	"regexp"
+)
+
 type Group struct {
 	Id     int    `json:"id"`
 	ChatId int    `json:"chat_id"`
 	Name   string `json:"name"`
+
+	SlowModeSec int `json:"slow_mode_sec"`
+
+	Description string `json:"description"`
+	Avatar      string `json:"avatar"`
+
+	// OnlyAdminsPost forbids sending messages to everyone except owner and admins unless allowed by override
+	OnlyAdminsPost bool `json:"only_admins_post"`
+	// MembersCanInvite gives invite permission to members
+	MembersCanInvite bool `json:"members_can_invite"`
+
+	// Username is public handle, chats with username can be found and joined by anyone
+	Username string `json:"username,omitempty"`
+}
+
+const maxGroupDescriptionLen = 255
+
+func ValidateGroupDescription(description string) bool {
+	return len(description) <= maxGroupDescriptionLen
+}
+
+var groupUsernameRegexp = regexp.MustCompile("^[a-zA-Z0-9_]{4,32}$")
+
+func ValidateGroupUsername(username string) bool {
+	return groupUsernameRegexp.MatchString(username)
+}
+
+func (g *Group) ScanRow(row RowScanner) error {
+	var username sql.NullString
+	if err := row.Scan(
+		&g.Id,
+		&g.ChatId,
+		&g.Name,
+		&g.SlowModeSec,
+		&g.Description,
+		&g.Avatar,
+		&g.OnlyAdminsPost,
+		&g.MembersCanInvite,
+		&username,
+	); err != nil {
+		return err
+	}
+	g.Username = username.String
+	return nil
+}
+
+// PublicGroup is group or channel with username as shown in directory
+type PublicGroup struct {
+	*Group
+	Type    string `json:"type"`
+	Members int    `json:"members"`
 }
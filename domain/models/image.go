@@ -0,0 +1,18 @@
+package models
+
+import "net/http"
+
+// ImageExt returns file extension by image content, ok is false if data is not a supported image
+func ImageExt(data []byte) (ext string, ok bool) {
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return ".png", true
+	case "image/jpeg":
+		return ".jpg", true
+	case "image/gif":
+		return ".gif", true
+	case "image/webp":
+		return ".webp", true
+	}
+	return "", false
+}
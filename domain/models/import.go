@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// ImportData is the format accepted by the history import:
+//
+//	{
+//	  "chats": [
+//	    {
+//	      "id": "c1",
+//	      "type": "group",
+//	      "name": "Team",
+//	      "members": [{"id": "u1", "phone": "+79990000000", "role": "admin"}],
+//	      "messages": [{"id": "m1", "from": "u1", "text": "hi", "time": "2024-01-02T15:04:05Z"}]
+//	    }
+//	  ]
+//	}
+//
+// Chat type is "group" or "user" (the last must have exactly two members).
+// Chat id is required, chats imported before and user chats of users who already
+// have one are reported as conflicts.
+// Members are mapped to existing users by phone, role is optional and defaults to member.
+// Chat whose import failed is removed, or reported with number of imported messages
+// if removing fails too.
+type ImportData struct {
+	Chats []ImportChat `json:"chats"`
+}
+
+type ImportChat struct {
+	Id       string          `json:"id"`
+	Type     string          `json:"type"`
+	Name     string          `json:"name"`
+	Members  []ImportMember  `json:"members"`
+	Messages []ImportMessage `json:"messages"`
+}
+
+type ImportMember struct {
+	Id    string `json:"id"`
+	Phone string `json:"phone"`
+	Role  string `json:"role"`
+}
+
+type ImportMessage struct {
+	Id   string    `json:"id"`
+	From string    `json:"from"`
+	Text string    `json:"text"`
+	Time time.Time `json:"time"`
+}
+
+type ImportReport struct {
+	ChatsCreated     int            `json:"chats_created"`
+	MessagesImported int            `json:"messages_imported"`
+	Skipped          []ImportRecord `json:"skipped"`
+	Conflicts        []ImportRecord `json:"conflicts"`
+	Failed           []ImportRecord `json:"failed"`
+}
+
+type ImportRecord struct {
+	Kind   string `json:"kind"`
+	ChatId string `json:"chat_id"`
+	Id     string `json:"id,omitempty"`
+	Reason string `json:"reason"`
+}
+
+const (
+	ImportRecordChat    = "chat"
+	ImportRecordMember  = "member"
+	ImportRecordMessage = "message"
+)
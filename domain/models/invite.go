@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+type GroupInvite struct {
+	Id           int        `json:"id"`
+	GroupId      int        `json:"group_id"`
+	CreatorId    int        `json:"creator_id"`
+	Token        string     `json:"token"`
+	ExpireTime   *time.Time `json:"expire_time,omitempty"`
+	MaxUses      int        `json:"max_uses"`
+	Uses         int        `json:"uses"`
+	NeedApproval bool       `json:"need_approval"`
+	Revoked      bool       `json:"revoked"`
+	CreateTime   time.Time  `json:"create_time"`
+}
+
+func (i *GroupInvite) ScanRow(row RowScanner) error {
+	return row.Scan(
+		&i.Id,
+		&i.GroupId,
+		&i.CreatorId,
+		&i.Token,
+		&i.ExpireTime,
+		&i.MaxUses,
+		&i.Uses,
+		&i.NeedApproval,
+		&i.Revoked,
+		&i.CreateTime,
+	)
+}
+
+type JoinRequest struct {
+	Id         int       `json:"id"`
+	GroupId    int       `json:"group_id"`
+	UserId     int       `json:"user_id"`
+	InviteId   int       `json:"invite_id"`
+	CreateTime time.Time `json:"create_time"`
+}
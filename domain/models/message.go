@@ -1,11 +1,75 @@
 package models
 
-import "time"
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
 
 type Message struct {
-	Id     int       `json:"id"`
-	ChatId int       `json:"chat_id"`
-	UserId int       `json:"user_id"`
-	Text   string    `json:"text"`
-	Time   time.Time `json:"time"`
+	Id        int       `json:"id"`
+	ChatId    int       `json:"chat_id"`
+	UserId    int       `json:"user_id"`
+	Text      string    `json:"text"`
+	Time      time.Time `json:"time"`
+	Kind      string    `json:"kind"`
+	StickerId int       `json:"sticker_id,omitempty"`
+
+	Payload *SystemPayload `json:"payload,omitempty"`
+}
+
+const (
+	MessageKindText    = "text"
+	MessageKindSticker = "sticker"
+	// MessageKindSystem is service message about group event, UserId is the user who did the action
+	MessageKindSystem = "system"
+)
+
+const (
+	SystemActionAdd        = "add"
+	SystemActionJoin       = "join"
+	SystemActionLeave      = "leave"
+	SystemActionKick       = "kick"
+	SystemActionBan        = "ban"
+	SystemActionRoleChange = "role_change"
+	SystemActionRename     = "rename"
+)
+
+// SystemPayload describes event of system message, clients render it as e.g. "Alice added Bob"
+type SystemPayload struct {
+	Action  string `json:"action"`
+	ActorId int    `json:"actor_id"`
+	UserId  int    `json:"user_id,omitempty"`
+	Role    string `json:"role,omitempty"`
+	Name    string `json:"name,omitempty"`
+}
+
+type RowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (m *Message) ScanRow(row RowScanner) error {
+	var stickerId sql.NullInt64
+	var payload []byte
+	if err := row.Scan(
+		&m.Id,
+		&m.ChatId,
+		&m.UserId,
+		&m.Text,
+		&m.Time,
+		&m.Kind,
+		&stickerId,
+		&payload,
+	); err != nil {
+		return err
+	}
+	m.StickerId = int(stickerId.Int64)
+	m.Payload = nil
+	if payload != nil {
+		m.Payload = new(SystemPayload)
+		if err := json.Unmarshal(payload, m.Payload); err != nil {
+			return err
+		}
+	}
+	return nil
 }
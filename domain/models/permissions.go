@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Permissions is a bit set of group permissions, encoded in json as a list of names
+type Permissions int64
+
+const (
+	PermSendMessages Permissions = 1 << iota
+	PermSendMedia
+	// bit is not used, values of stored permissions must not change
+	_
+	PermInvite
+	PermRemoveMembers
+	PermEditInfo
+	PermManageRoles
+	// PermManageInvites allows listing and revoking invite links and handling join requests,
+	// unlike PermInvite it is not granted to members by MembersCanInvite
+	PermManageInvites
+
+	PermAll = PermSendMessages | PermSendMedia | PermInvite | PermRemoveMembers | PermEditInfo | PermManageRoles |
+		PermManageInvites
+)
+
+var permissionNames = []struct {
+	perm Permissions
+	name string
+}{
+	{PermSendMessages, "send_messages"},
+	{PermSendMedia, "send_media"},
+	{PermInvite, "invite"},
+	{PermRemoveMembers, "remove_members"},
+	{PermEditInfo, "edit_info"},
+	{PermManageRoles, "manage_roles"},
+	{PermManageInvites, "manage_invites"},
+}
+
+func (p Permissions) Has(perm Permissions) bool {
+	return p&perm == perm
+}
+
+func (p Permissions) Names() []string {
+	names := make([]string, 0, len(permissionNames))
+	for _, n := range permissionNames {
+		if p.Has(n.perm) {
+			names = append(names, n.name)
+		}
+	}
+	return names
+}
+
+func (p Permissions) String() string {
+	return fmt.Sprint(p.Names())
+}
+
+func ParsePermissions(names []string) (Permissions, error) {
+	var p Permissions
+outer:
+	for _, name := range names {
+		for _, n := range permissionNames {
+			if n.name == name {
+				p |= n.perm
+				continue outer
+			}
+		}
+		return 0, fmt.Errorf("unknown permission: %s", name)
+	}
+	return p, nil
+}
+
+func (p Permissions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Names())
+}
+
+func (p *Permissions) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	parsed, err := ParsePermissions(names)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+const (
+	RestrictionBan  = "ban"
+	RestrictionMute = "mute"
+)
+
+const maxRestrictionReasonLen = 255
+
+func ValidateRestrictionReason(reason string) bool {
+	return len(reason) <= maxRestrictionReasonLen
+}
+
+// Restriction is a ban or mute of user in group, restriction without expire time is permanent
+type Restriction struct {
+	Id         int        `json:"id"`
+	GroupId    int        `json:"group_id"`
+	UserId     int        `json:"user_id"`
+	ActorId    int        `json:"actor_id"`
+	Type       string     `json:"type"`
+	Reason     string     `json:"reason"`
+	ExpireTime *time.Time `json:"expire_time,omitempty"`
+	CreateTime time.Time  `json:"create_time"`
+}
+
+func (r *Restriction) ScanRow(row RowScanner) error {
+	return row.Scan(
+		&r.Id,
+		&r.GroupId,
+		&r.UserId,
+		&r.ActorId,
+		&r.Type,
+		&r.Reason,
+		&r.ExpireTime,
+		&r.CreateTime,
+	)
+}
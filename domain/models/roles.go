@@ -1,10 +1,23 @@
 package models
 
 const (
+	RoleOwner  = "owner"
 	RoleAdmin  = "admin"
 	RoleMember = "member"
 )
 
+const maxRoleNameLen = 45
+
+// IsBuiltinRole reports whether role is one of the predefined group roles
+func IsBuiltinRole(role string) bool {
+	switch role {
+	case RoleOwner, RoleAdmin, RoleMember:
+		return true
+	}
+	return false
+}
+
+// ValidateRole checks that role is a built-in role except owner
 func ValidateRole(role string) bool {
 	switch role {
 	case RoleAdmin, RoleMember:
@@ -12,3 +25,34 @@ func ValidateRole(role string) bool {
 	}
 	return false
 }
+
+func ValidateRoleName(name string) bool {
+	return len(name) != 0 && len(name) <= maxRoleNameLen && !IsBuiltinRole(name)
+}
+
+// BuiltinRolePermissions returns permissions of predefined role, false if role is custom
+func BuiltinRolePermissions(role string) (Permissions, bool) {
+	switch role {
+	case RoleOwner, RoleAdmin:
+		return PermAll, true
+	case RoleMember:
+		return PermSendMessages | PermSendMedia, true
+	}
+	return 0, false
+}
+
+// GroupRole is a custom role defined in a group
+type GroupRole struct {
+	Id          int         `json:"id"`
+	GroupId     int         `json:"group_id"`
+	Name        string      `json:"name"`
+	Permissions Permissions `json:"permissions"`
+}
+
+// PermissionOverride is allowed and denied permissions of a member on top of the member's role
+type PermissionOverride struct {
+	GroupId int         `json:"group_id"`
+	UserId  int         `json:"user_id"`
+	Allow   Permissions `json:"allow"`
+	Deny    Permissions `json:"deny"`
+}
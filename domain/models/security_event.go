@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	SecurityEventRefreshTokenReuse = "refresh_token_reuse"
+	SecurityEventPasswordReset     = "password_reset"
+)
+
+// SecurityEvent is suspicious activity on user account, shown to the user
+type SecurityEvent struct {
+	Id         int       `json:"id"`
+	UserId     int       `json:"user_id"`
+	Type       string    `json:"type"`
+	SessionId  int       `json:"session_id"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+func (e *SecurityEvent) ScanRow(row RowScanner) error {
+	return row.Scan(
+		&e.Id,
+		&e.UserId,
+		&e.Type,
+		&e.SessionId,
+		&e.IP,
+		&e.UserAgent,
+		&e.CreateTime,
+	)
+}
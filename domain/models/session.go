@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	maxDeviceNameLen = 64
+	maxUserAgentLen  = 255
+)
+
+// Session is a signed in device, each refresh token is bound to session
+type Session struct {
+	Id           int       `json:"id"`
+	UserId       int       `json:"user_id"`
+	DeviceName   string    `json:"device_name"`
+	UserAgent    string    `json:"user_agent"`
+	IP           string    `json:"ip"`
+	CreateTime   time.Time `json:"create_time"`
+	LastUsedTime time.Time `json:"last_used_time"`
+	ExpireTime   time.Time `json:"expire_time"`
+	Current      bool      `json:"current"`
+}
+
+func (s *Session) ScanRow(row RowScanner) error {
+	return row.Scan(
+		&s.Id,
+		&s.UserId,
+		&s.DeviceName,
+		&s.UserAgent,
+		&s.IP,
+		&s.CreateTime,
+		&s.LastUsedTime,
+		&s.ExpireTime,
+	)
+}
+
+// ClientInfo describes device which signs in or refreshes tokens
+type ClientInfo struct {
+	DeviceName string
+	UserAgent  string
+	IP         string
+}
+
+// Truncate cuts fields to column sizes, they come from client and are informational only
+func (c *ClientInfo) Truncate() {
+	if len(c.DeviceName) > maxDeviceNameLen {
+		c.DeviceName = c.DeviceName[:maxDeviceNameLen]
+	}
+	if len(c.UserAgent) > maxUserAgentLen {
+		c.UserAgent = c.UserAgent[:maxUserAgentLen]
+	}
+}
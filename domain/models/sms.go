@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	SmsStatusPending = "pending"
+	SmsStatusSent    = "sent"
+	SmsStatusFailed  = "failed"
+)
+
+// SmsMessage is send status of SMS, text is not stored because it contains verification code
+type SmsMessage struct {
+	Id                int
+	Phone             string
+	Status            string
+	Provider          string
+	ProviderMessageId string
+	Attempts          int
+	Error             string
+	CreateTime        time.Time
+	UpdateTime        time.Time
+}
+
+const maxSmsErrorLen = 255
+
+// SetError saves error of the last attempt, it is truncated to fit into database
+func (m *SmsMessage) SetError(err string) {
+	if len(err) > maxSmsErrorLen {
+		err = err[:maxSmsErrorLen]
+	}
+	m.Error = err
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type StickerPack struct {
+	Id         int       `json:"id"`
+	OwnerId    int       `json:"owner_id"`
+	Name       string    `json:"name"`
+	CreateTime time.Time `json:"create_time"`
+	Stickers   []Sticker `json:"stickers"`
+}
+
+type Sticker struct {
+	Id     int    `json:"id"`
+	PackId int    `json:"pack_id"`
+	Emoji  string `json:"emoji"`
+	Image  string `json:"image"`
+}
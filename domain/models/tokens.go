@@ -8,3 +8,9 @@ type Tokens struct {
 	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
 	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 }
+
+// ResetToken allows to set new password once after reset code was verified
+type ResetToken struct {
+	Token     string    `json:"reset_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	LoginMethodSMS   = "sms"
+	LoginMethodTOTP  = "totp"
+	LoginMethodEmail = "email"
+)
+
+// TOTP is authenticator app secret of user, it is used for login only after enrollment is confirmed
+type TOTP struct {
+	UserId  int
+	Secret  string
+	Enabled bool
+	// LastStep is time step of the last accepted code, codes can't be reused
+	LastStep   int64
+	CreateTime time.Time
+}
+
+func (t *TOTP) ScanRow(row RowScanner) error {
+	return row.Scan(
+		&t.UserId,
+		&t.Secret,
+		&t.Enabled,
+		&t.LastStep,
+		&t.CreateTime,
+	)
+}
+
+// TOTPEnrollment is returned to client to add secret to authenticator app, URI is shown as QR code
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// RecoveryCodes are one-time codes which replace TOTP code when authenticator app is lost
+type RecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
+
+// HashRecoveryCode returns hash stored instead of recovery code, codes are random so salt is not needed
+func HashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
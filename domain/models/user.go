@@ -4,18 +4,24 @@ import (
 	"database/sql"
 	errorsutils "errors"
 	"github.com/nyaruka/phonenumbers"
+	"net/mail"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type User struct {
 	Id         int       `json:"id"`
 	Phone      string    `json:"phone,omitempty"`
-	Password   string    `json:"password,omitempty"`
+	Password   string    `json:"-"`
 	Name       string    `json:"name"`
 	RealName   string    `json:"real_name"`
 	ShowPhone  bool      `json:"show_phone"`
 	LastOnline time.Time `json:"last_online"`
 	Confirmed  bool      `json:"confirming"`
+	// Email is optional, it is shown only to the user itself
+	Email          string `json:"-"`
+	EmailConfirmed bool   `json:"-"`
 }
 
 func (u *User) ScanRow(row *sql.Row) error {
@@ -28,6 +34,8 @@ func (u *User) ScanRow(row *sql.Row) error {
 		&u.ShowPhone,
 		&u.LastOnline,
 		&u.Confirmed,
+		&u.Email,
+		&u.EmailConfirmed,
 	)
 }
 
@@ -41,3 +49,45 @@ func ParsePhone(phone string) (string, error) {
 	}
 	return phonenumbers.Format(num, phonenumbers.E164), nil
 }
+
+const maxEmailLen = 254
+
+// ParseEmail validates bare email address without display name
+func ParseEmail(email string) (string, error) {
+	if len(email) > maxEmailLen {
+		return "", errorsutils.New("email is too long")
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", err
+	}
+	if addr.Address != email {
+		return "", errorsutils.New("invalid email")
+	}
+	return addr.Address, nil
+}
+
+const (
+	minPasswordLen = 8
+	maxPasswordLen = 128
+)
+
+// ValidatePassword checks strength of new password
+func ValidatePassword(password string) error {
+	n := utf8.RuneCountInString(password)
+	if n < minPasswordLen {
+		return errorsutils.New("password must be at least 8 characters")
+	}
+	if n > maxPasswordLen {
+		return errorsutils.New("password must be at most 128 characters")
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		hasLetter = hasLetter || unicode.IsLetter(r)
+		hasDigit = hasDigit || unicode.IsDigit(r)
+	}
+	if !hasLetter || !hasDigit {
+		return errorsutils.New("password must contain letters and digits")
+	}
+	return nil
+}
@@ -11,4 +11,6 @@ type Cache interface {
 	Get(ctx context.Context, key string) (int, *errors.Error)
 	Del(ctx context.Context, key string) *errors.Error
 	TTL(ctx context.Context, key string) time.Duration
+	// CompareAndSet atomically sets key to v if its value is old, missing key has value 0
+	CompareAndSet(ctx context.Context, key string, old int, v int, ttl time.Duration) (bool, *errors.Error)
 }
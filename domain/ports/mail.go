@@ -0,0 +1,10 @@
+package ports
+
+import (
+	"context"
+	"messanger/pkg/errors"
+)
+
+type MailSender interface {
+	Send(ctx context.Context, to, subject, body string) *errors.Error
+}
@@ -0,0 +1,83 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "messanger/domain/models"
+)
+
+// AuditRepo is an autogenerated mock type for the AuditRepo type
+type AuditRepo struct {
+	mock.Mock
+}
+
+// GetByGroup provides a mock function with given fields: ctx, filter
+func (_m *AuditRepo) GetByGroup(ctx context.Context, filter *models.AuditFilter) ([]models.AuditEntry, *errors.Error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByGroup")
+	}
+
+	var r0 []models.AuditEntry
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AuditFilter) ([]models.AuditEntry, *errors.Error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AuditFilter) []models.AuditEntry); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.AuditFilter) *errors.Error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// New provides a mock function with given fields: ctx, entry
+func (_m *AuditRepo) New(ctx context.Context, entry *models.AuditEntry) *errors.Error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for New")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AuditEntry) *errors.Error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewAuditRepo creates a new instance of AuditRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepo {
+	mock := &AuditRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
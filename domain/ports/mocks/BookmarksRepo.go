@@ -0,0 +1,103 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "messanger/domain/models"
+)
+
+// BookmarksRepo is an autogenerated mock type for the BookmarksRepo type
+type BookmarksRepo struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, userId, messageId
+func (_m *BookmarksRepo) Delete(ctx context.Context, userId int, messageId int) *errors.Error {
+	ret := _m.Called(ctx, userId, messageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *errors.Error); ok {
+		r0 = rf(ctx, userId, messageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetByUser provides a mock function with given fields: ctx, userId
+func (_m *BookmarksRepo) GetByUser(ctx context.Context, userId int) ([]models.Bookmark, *errors.Error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUser")
+	}
+
+	var r0 []models.Bookmark
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Bookmark, *errors.Error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Bookmark); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Bookmark)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// New provides a mock function with given fields: ctx, bookmark
+func (_m *BookmarksRepo) New(ctx context.Context, bookmark *models.Bookmark) *errors.Error {
+	ret := _m.Called(ctx, bookmark)
+
+	if len(ret) == 0 {
+		panic("no return value specified for New")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Bookmark) *errors.Error); ok {
+		r0 = rf(ctx, bookmark)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewBookmarksRepo creates a new instance of BookmarksRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookmarksRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookmarksRepo {
+	mock := &BookmarksRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
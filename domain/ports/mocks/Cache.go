@@ -16,6 +16,36 @@ type Cache struct {
 	mock.Mock
 }
 
+// CompareAndSet provides a mock function with given fields: ctx, key, old, v, ttl
+func (_m *Cache) CompareAndSet(ctx context.Context, key string, old int, v int, ttl time.Duration) (bool, *errors.Error) {
+	ret := _m.Called(ctx, key, old, v, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareAndSet")
+	}
+
+	var r0 bool
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, time.Duration) (bool, *errors.Error)); ok {
+		return rf(ctx, key, old, v, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, time.Duration) bool); ok {
+		r0 = rf(ctx, key, old, v, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int, time.Duration) *errors.Error); ok {
+		r1 = rf(ctx, key, old, v, ttl)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // Del provides a mock function with given fields: ctx, key
 func (_m *Cache) Del(ctx context.Context, key string) *errors.Error {
 	ret := _m.Called(ctx, key)
@@ -264,6 +264,26 @@ func (_m *ChatsRepo) New(ctx context.Context, chat *models.Chat) *errors.Error {
 	return r0
 }
 
+// NewSaved provides a mock function with given fields: ctx, chat, userId
+func (_m *ChatsRepo) NewSaved(ctx context.Context, chat *models.Chat, userId int) *errors.Error {
+	ret := _m.Called(ctx, chat, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewSaved")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Chat, int) *errors.Error); ok {
+		r0 = rf(ctx, chat, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
 // RemoveUserFromChat provides a mock function with given fields: ctx, id, userId
 func (_m *ChatsRepo) RemoveUserFromChat(ctx context.Context, id int, userId int) *errors.Error {
 	ret := _m.Called(ctx, id, userId)
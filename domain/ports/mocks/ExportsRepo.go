@@ -0,0 +1,209 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "messanger/domain/models"
+
+	time "time"
+)
+
+// ExportsRepo is an autogenerated mock type for the ExportsRepo type
+type ExportsRepo struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *ExportsRepo) Delete(ctx context.Context, id string) *errors.Error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string) *errors.Error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// FailStale provides a mock function with given fields: ctx, before, reason
+func (_m *ExportsRepo) FailStale(ctx context.Context, before time.Time, reason string) *errors.Error {
+	ret := _m.Called(ctx, before, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailStale")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) *errors.Error); ok {
+		r0 = rf(ctx, before, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetById provides a mock function with given fields: ctx, id
+func (_m *ExportsRepo) GetById(ctx context.Context, id string) (*models.ExportJob, *errors.Error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetById")
+	}
+
+	var r0 *models.ExportJob
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.ExportJob, *errors.Error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.ExportJob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ExportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *errors.Error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetByToken provides a mock function with given fields: ctx, token
+func (_m *ExportsRepo) GetByToken(ctx context.Context, token string) (*models.ExportJob, *errors.Error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByToken")
+	}
+
+	var r0 *models.ExportJob
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.ExportJob, *errors.Error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.ExportJob); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ExportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *errors.Error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetExpired provides a mock function with given fields: ctx, now, failedBefore
+func (_m *ExportsRepo) GetExpired(ctx context.Context, now time.Time, failedBefore time.Time) ([]models.ExportJob, *errors.Error) {
+	ret := _m.Called(ctx, now, failedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExpired")
+	}
+
+	var r0 []models.ExportJob
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]models.ExportJob, *errors.Error)); ok {
+		return rf(ctx, now, failedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []models.ExportJob); ok {
+		r0 = rf(ctx, now, failedBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ExportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) *errors.Error); ok {
+		r1 = rf(ctx, now, failedBefore)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// New provides a mock function with given fields: ctx, job
+func (_m *ExportsRepo) New(ctx context.Context, job *models.ExportJob) *errors.Error {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for New")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ExportJob) *errors.Error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, job
+func (_m *ExportsRepo) Update(ctx context.Context, job *models.ExportJob) *errors.Error {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ExportJob) *errors.Error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewExportsRepo creates a new instance of ExportsRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExportsRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExportsRepo {
+	mock := &ExportsRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
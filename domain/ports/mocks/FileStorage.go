@@ -0,0 +1,113 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FileStorage is an autogenerated mock type for the FileStorage type
+type FileStorage struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, name
+func (_m *FileStorage) Delete(ctx context.Context, name string) *errors.Error {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string) *errors.Error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// Open provides a mock function with given fields: ctx, name
+func (_m *FileStorage) Open(ctx context.Context, name string) (io.ReadSeekCloser, *errors.Error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Open")
+	}
+
+	var r0 io.ReadSeekCloser
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (io.ReadSeekCloser, *errors.Error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) io.ReadSeekCloser); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadSeekCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *errors.Error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, ext, r
+func (_m *FileStorage) Save(ctx context.Context, ext string, r io.Reader) (string, *errors.Error) {
+	ret := _m.Called(ctx, ext, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 string
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) (string, *errors.Error)); ok {
+		return rf(ctx, ext, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) string); ok {
+		r0 = rf(ctx, ext, r)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader) *errors.Error); ok {
+		r1 = rf(ctx, ext, r)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewFileStorage creates a new instance of FileStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFileStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FileStorage {
+	mock := &FileStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -16,6 +16,36 @@ type GroupsRepo struct {
 	mock.Mock
 }
 
+// CheckUserInGroup provides a mock function with given fields: ctx, userId, groupId
+func (_m *GroupsRepo) CheckUserInGroup(ctx context.Context, userId int, groupId int) (bool, *errors.Error) {
+	ret := _m.Called(ctx, userId, groupId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckUserInGroup")
+	}
+
+	var r0 bool
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (bool, *errors.Error)); ok {
+		return rf(ctx, userId, groupId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) bool); ok {
+		r0 = rf(ctx, userId, groupId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) *errors.Error); ok {
+		r1 = rf(ctx, userId, groupId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *GroupsRepo) Delete(ctx context.Context, id int) *errors.Error {
 	ret := _m.Called(ctx, id)
@@ -36,6 +66,46 @@ func (_m *GroupsRepo) Delete(ctx context.Context, id int) *errors.Error {
 	return r0
 }
 
+// DeleteCustomRole provides a mock function with given fields: ctx, groupId, name
+func (_m *GroupsRepo) DeleteCustomRole(ctx context.Context, groupId int, name string) *errors.Error {
+	ret := _m.Called(ctx, groupId, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCustomRole")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *errors.Error); ok {
+		r0 = rf(ctx, groupId, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// DeletePermissionOverride provides a mock function with given fields: ctx, userId, groupId
+func (_m *GroupsRepo) DeletePermissionOverride(ctx context.Context, userId int, groupId int) *errors.Error {
+	ret := _m.Called(ctx, userId, groupId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePermissionOverride")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *errors.Error); ok {
+		r0 = rf(ctx, userId, groupId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
 // GetById provides a mock function with given fields: ctx, id
 func (_m *GroupsRepo) GetById(ctx context.Context, id int) (*models.Group, *errors.Error) {
 	ret := _m.Called(ctx, id)
@@ -68,6 +138,102 @@ func (_m *GroupsRepo) GetById(ctx context.Context, id int) (*models.Group, *erro
 	return r0, r1
 }
 
+// GetByUsername provides a mock function with given fields: ctx, username
+func (_m *GroupsRepo) GetByUsername(ctx context.Context, username string) (*models.Group, *errors.Error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUsername")
+	}
+
+	var r0 *models.Group
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Group, *errors.Error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Group); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *errors.Error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetCustomRole provides a mock function with given fields: ctx, groupId, name
+func (_m *GroupsRepo) GetCustomRole(ctx context.Context, groupId int, name string) (*models.GroupRole, *errors.Error) {
+	ret := _m.Called(ctx, groupId, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCustomRole")
+	}
+
+	var r0 *models.GroupRole
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (*models.GroupRole, *errors.Error)); ok {
+		return rf(ctx, groupId, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *models.GroupRole); ok {
+		r0 = rf(ctx, groupId, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GroupRole)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) *errors.Error); ok {
+		r1 = rf(ctx, groupId, name)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetCustomRoles provides a mock function with given fields: ctx, groupId
+func (_m *GroupsRepo) GetCustomRoles(ctx context.Context, groupId int) ([]models.GroupRole, *errors.Error) {
+	ret := _m.Called(ctx, groupId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCustomRoles")
+	}
+
+	var r0 []models.GroupRole
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.GroupRole, *errors.Error)); ok {
+		return rf(ctx, groupId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.GroupRole); ok {
+		r0 = rf(ctx, groupId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.GroupRole)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, groupId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetGroupByChatId provides a mock function with given fields: ctx, chatId
 func (_m *GroupsRepo) GetGroupByChatId(ctx context.Context, chatId int) (*models.Group, *errors.Error) {
 	ret := _m.Called(ctx, chatId)
@@ -132,6 +298,38 @@ func (_m *GroupsRepo) GetGroupsByUser(ctx context.Context, userId int) ([]models
 	return r0, r1
 }
 
+// GetPermissionOverride provides a mock function with given fields: ctx, userId, groupId
+func (_m *GroupsRepo) GetPermissionOverride(ctx context.Context, userId int, groupId int) (*models.PermissionOverride, *errors.Error) {
+	ret := _m.Called(ctx, userId, groupId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPermissionOverride")
+	}
+
+	var r0 *models.PermissionOverride
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.PermissionOverride, *errors.Error)); ok {
+		return rf(ctx, userId, groupId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.PermissionOverride); ok {
+		r0 = rf(ctx, userId, groupId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PermissionOverride)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) *errors.Error); ok {
+		r1 = rf(ctx, userId, groupId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetRole provides a mock function with given fields: ctx, userId, groupId
 func (_m *GroupsRepo) GetRole(ctx context.Context, userId int, groupId int) (string, *errors.Error) {
 	ret := _m.Called(ctx, userId, groupId)
@@ -214,6 +412,98 @@ func (_m *GroupsRepo) New(ctx context.Context, group *models.Group) *errors.Erro
 	return r0
 }
 
+// NewCustomRole provides a mock function with given fields: ctx, role
+func (_m *GroupsRepo) NewCustomRole(ctx context.Context, role *models.GroupRole) *errors.Error {
+	ret := _m.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewCustomRole")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.GroupRole) *errors.Error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// SearchPublic provides a mock function with given fields: ctx, query, limit
+func (_m *GroupsRepo) SearchPublic(ctx context.Context, query string, limit int) ([]models.PublicGroup, *errors.Error) {
+	ret := _m.Called(ctx, query, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchPublic")
+	}
+
+	var r0 []models.PublicGroup
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]models.PublicGroup, *errors.Error)); ok {
+		return rf(ctx, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []models.PublicGroup); ok {
+		r0 = rf(ctx, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PublicGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) *errors.Error); ok {
+		r1 = rf(ctx, query, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// SetAvatar provides a mock function with given fields: ctx, id, avatar
+func (_m *GroupsRepo) SetAvatar(ctx context.Context, id int, avatar string) *errors.Error {
+	ret := _m.Called(ctx, id, avatar)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAvatar")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *errors.Error); ok {
+		r0 = rf(ctx, id, avatar)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// SetPermissionOverride provides a mock function with given fields: ctx, override
+func (_m *GroupsRepo) SetPermissionOverride(ctx context.Context, override *models.PermissionOverride) *errors.Error {
+	ret := _m.Called(ctx, override)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPermissionOverride")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PermissionOverride) *errors.Error); ok {
+		r0 = rf(ctx, override)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
 // SetRole provides a mock function with given fields: ctx, userId, groupId, role
 func (_m *GroupsRepo) SetRole(ctx context.Context, userId int, groupId int, role string) *errors.Error {
 	ret := _m.Called(ctx, userId, groupId, role)
@@ -234,6 +524,46 @@ func (_m *GroupsRepo) SetRole(ctx context.Context, userId int, groupId int, role
 	return r0
 }
 
+// SetSlowMode provides a mock function with given fields: ctx, id, seconds
+func (_m *GroupsRepo) SetSlowMode(ctx context.Context, id int, seconds int) *errors.Error {
+	ret := _m.Called(ctx, id, seconds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSlowMode")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *errors.Error); ok {
+		r0 = rf(ctx, id, seconds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// SetUsername provides a mock function with given fields: ctx, id, username
+func (_m *GroupsRepo) SetUsername(ctx context.Context, id int, username string) *errors.Error {
+	ret := _m.Called(ctx, id, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUsername")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *errors.Error); ok {
+		r0 = rf(ctx, id, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, group
 func (_m *GroupsRepo) Update(ctx context.Context, group *models.Group) *errors.Error {
 	ret := _m.Called(ctx, group)
@@ -254,6 +584,26 @@ func (_m *GroupsRepo) Update(ctx context.Context, group *models.Group) *errors.E
 	return r0
 }
 
+// UpdateCustomRole provides a mock function with given fields: ctx, role
+func (_m *GroupsRepo) UpdateCustomRole(ctx context.Context, role *models.GroupRole) *errors.Error {
+	ret := _m.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCustomRole")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.GroupRole) *errors.Error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
 // NewGroupsRepo creates a new instance of GroupsRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewGroupsRepo(t interface {
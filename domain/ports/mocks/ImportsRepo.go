@@ -0,0 +1,49 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ImportsRepo is an autogenerated mock type for the ImportsRepo type
+type ImportsRepo struct {
+	mock.Mock
+}
+
+// New provides a mock function with given fields: ctx, sourceId, chatId
+func (_m *ImportsRepo) New(ctx context.Context, sourceId string, chatId int) *errors.Error {
+	ret := _m.Called(ctx, sourceId, chatId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for New")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *errors.Error); ok {
+		r0 = rf(ctx, sourceId, chatId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewImportsRepo creates a new instance of ImportsRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewImportsRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ImportsRepo {
+	mock := &ImportsRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,301 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "messanger/domain/models"
+)
+
+// InvitesRepo is an autogenerated mock type for the InvitesRepo type
+type InvitesRepo struct {
+	mock.Mock
+}
+
+// DeleteJoinRequest provides a mock function with given fields: ctx, id
+func (_m *InvitesRepo) DeleteJoinRequest(ctx context.Context, id int) *errors.Error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteJoinRequest")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) *errors.Error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetByGroup provides a mock function with given fields: ctx, groupId
+func (_m *InvitesRepo) GetByGroup(ctx context.Context, groupId int) ([]models.GroupInvite, *errors.Error) {
+	ret := _m.Called(ctx, groupId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByGroup")
+	}
+
+	var r0 []models.GroupInvite
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.GroupInvite, *errors.Error)); ok {
+		return rf(ctx, groupId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.GroupInvite); ok {
+		r0 = rf(ctx, groupId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.GroupInvite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, groupId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetById provides a mock function with given fields: ctx, id
+func (_m *InvitesRepo) GetById(ctx context.Context, id int) (*models.GroupInvite, *errors.Error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetById")
+	}
+
+	var r0 *models.GroupInvite
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.GroupInvite, *errors.Error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.GroupInvite); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GroupInvite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetByToken provides a mock function with given fields: ctx, token
+func (_m *InvitesRepo) GetByToken(ctx context.Context, token string) (*models.GroupInvite, *errors.Error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByToken")
+	}
+
+	var r0 *models.GroupInvite
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.GroupInvite, *errors.Error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.GroupInvite); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GroupInvite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *errors.Error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetJoinRequestById provides a mock function with given fields: ctx, id
+func (_m *InvitesRepo) GetJoinRequestById(ctx context.Context, id int) (*models.JoinRequest, *errors.Error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJoinRequestById")
+	}
+
+	var r0 *models.JoinRequest
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.JoinRequest, *errors.Error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.JoinRequest); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.JoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetJoinRequestsByGroup provides a mock function with given fields: ctx, groupId
+func (_m *InvitesRepo) GetJoinRequestsByGroup(ctx context.Context, groupId int) ([]models.JoinRequest, *errors.Error) {
+	ret := _m.Called(ctx, groupId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJoinRequestsByGroup")
+	}
+
+	var r0 []models.JoinRequest
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.JoinRequest, *errors.Error)); ok {
+		return rf(ctx, groupId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.JoinRequest); ok {
+		r0 = rf(ctx, groupId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.JoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, groupId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// New provides a mock function with given fields: ctx, invite
+func (_m *InvitesRepo) New(ctx context.Context, invite *models.GroupInvite) *errors.Error {
+	ret := _m.Called(ctx, invite)
+
+	if len(ret) == 0 {
+		panic("no return value specified for New")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.GroupInvite) *errors.Error); ok {
+		r0 = rf(ctx, invite)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewJoinRequest provides a mock function with given fields: ctx, request
+func (_m *InvitesRepo) NewJoinRequest(ctx context.Context, request *models.JoinRequest) *errors.Error {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewJoinRequest")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.JoinRequest) *errors.Error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// Revoke provides a mock function with given fields: ctx, id
+func (_m *InvitesRepo) Revoke(ctx context.Context, id int) *errors.Error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) *errors.Error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// Use provides a mock function with given fields: ctx, id
+func (_m *InvitesRepo) Use(ctx context.Context, id int) (bool, *errors.Error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Use")
+	}
+
+	var r0 bool
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (bool, *errors.Error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewInvitesRepo creates a new instance of InvitesRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInvitesRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *InvitesRepo {
+	mock := &InvitesRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,49 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MailSender is an autogenerated mock type for the MailSender type
+type MailSender struct {
+	mock.Mock
+}
+
+// Send provides a mock function with given fields: ctx, to, subject, body
+func (_m *MailSender) Send(ctx context.Context, to string, subject string, body string) *errors.Error {
+	ret := _m.Called(ctx, to, subject, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *errors.Error); ok {
+		r0 = rf(ctx, to, subject, body)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewMailSender creates a new instance of MailSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMailSender(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MailSender {
+	mock := &MailSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -16,6 +16,36 @@ type MessagesRepo struct {
 	mock.Mock
 }
 
+// CountByChat provides a mock function with given fields: ctx, chatId
+func (_m *MessagesRepo) CountByChat(ctx context.Context, chatId int) (int, *errors.Error) {
+	ret := _m.Called(ctx, chatId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByChat")
+	}
+
+	var r0 int
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, *errors.Error)); ok {
+		return rf(ctx, chatId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, chatId)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, chatId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *MessagesRepo) Delete(ctx context.Context, id int) *errors.Error {
 	ret := _m.Called(ctx, id)
@@ -68,6 +98,38 @@ func (_m *MessagesRepo) GetByChat(ctx context.Context, chatId int, lastId int, c
 	return r0, r1
 }
 
+// GetByChatAfter provides a mock function with given fields: ctx, chatId, afterId, count
+func (_m *MessagesRepo) GetByChatAfter(ctx context.Context, chatId int, afterId int, count int) ([]models.Message, *errors.Error) {
+	ret := _m.Called(ctx, chatId, afterId, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByChatAfter")
+	}
+
+	var r0 []models.Message
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) ([]models.Message, *errors.Error)); ok {
+		return rf(ctx, chatId, afterId, count)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) []models.Message); ok {
+		r0 = rf(ctx, chatId, afterId, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) *errors.Error); ok {
+		r1 = rf(ctx, chatId, afterId, count)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetById provides a mock function with given fields: ctx, id
 func (_m *MessagesRepo) GetById(ctx context.Context, id int) (*models.Message, *errors.Error) {
 	ret := _m.Called(ctx, id)
@@ -0,0 +1,155 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "messanger/domain/models"
+)
+
+// RestrictionsRepo is an autogenerated mock type for the RestrictionsRepo type
+type RestrictionsRepo struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, groupId, userId, restrictionType
+func (_m *RestrictionsRepo) Delete(ctx context.Context, groupId int, userId int, restrictionType string) *errors.Error {
+	ret := _m.Called(ctx, groupId, userId, restrictionType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) *errors.Error); ok {
+		r0 = rf(ctx, groupId, userId, restrictionType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: ctx
+func (_m *RestrictionsRepo) DeleteExpired(ctx context.Context) *errors.Error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context) *errors.Error); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, groupId, userId, restrictionType
+func (_m *RestrictionsRepo) Get(ctx context.Context, groupId int, userId int, restrictionType string) (*models.Restriction, *errors.Error) {
+	ret := _m.Called(ctx, groupId, userId, restrictionType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *models.Restriction
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) (*models.Restriction, *errors.Error)); ok {
+		return rf(ctx, groupId, userId, restrictionType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) *models.Restriction); ok {
+		r0 = rf(ctx, groupId, userId, restrictionType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Restriction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) *errors.Error); ok {
+		r1 = rf(ctx, groupId, userId, restrictionType)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetByGroup provides a mock function with given fields: ctx, groupId, restrictionType
+func (_m *RestrictionsRepo) GetByGroup(ctx context.Context, groupId int, restrictionType string) ([]models.Restriction, *errors.Error) {
+	ret := _m.Called(ctx, groupId, restrictionType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByGroup")
+	}
+
+	var r0 []models.Restriction
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) ([]models.Restriction, *errors.Error)); ok {
+		return rf(ctx, groupId, restrictionType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) []models.Restriction); ok {
+		r0 = rf(ctx, groupId, restrictionType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Restriction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) *errors.Error); ok {
+		r1 = rf(ctx, groupId, restrictionType)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// Set provides a mock function with given fields: ctx, restriction
+func (_m *RestrictionsRepo) Set(ctx context.Context, restriction *models.Restriction) *errors.Error {
+	ret := _m.Called(ctx, restriction)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Restriction) *errors.Error); ok {
+		r0 = rf(ctx, restriction)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewRestrictionsRepo creates a new instance of RestrictionsRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRestrictionsRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RestrictionsRepo {
+	mock := &RestrictionsRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,207 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "messanger/domain/models"
+)
+
+// SessionsRepo is an autogenerated mock type for the SessionsRepo type
+type SessionsRepo struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *SessionsRepo) Delete(ctx context.Context, id int) *errors.Error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) *errors.Error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetById provides a mock function with given fields: ctx, id
+func (_m *SessionsRepo) GetById(ctx context.Context, id int) (*models.Session, *errors.Error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetById")
+	}
+
+	var r0 *models.Session
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Session, *errors.Error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Session); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetByUser provides a mock function with given fields: ctx, userId
+func (_m *SessionsRepo) GetByUser(ctx context.Context, userId int) ([]models.Session, *errors.Error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUser")
+	}
+
+	var r0 []models.Session
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Session, *errors.Error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Session); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetSecurityEvents provides a mock function with given fields: ctx, userId, count
+func (_m *SessionsRepo) GetSecurityEvents(ctx context.Context, userId int, count int) ([]models.SecurityEvent, *errors.Error) {
+	ret := _m.Called(ctx, userId, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSecurityEvents")
+	}
+
+	var r0 []models.SecurityEvent
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.SecurityEvent, *errors.Error)); ok {
+		return rf(ctx, userId, count)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.SecurityEvent); ok {
+		r0 = rf(ctx, userId, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.SecurityEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) *errors.Error); ok {
+		r1 = rf(ctx, userId, count)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// New provides a mock function with given fields: ctx, session
+func (_m *SessionsRepo) New(ctx context.Context, session *models.Session) *errors.Error {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for New")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Session) *errors.Error); ok {
+		r0 = rf(ctx, session)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewSecurityEvent provides a mock function with given fields: ctx, event
+func (_m *SessionsRepo) NewSecurityEvent(ctx context.Context, event *models.SecurityEvent) *errors.Error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewSecurityEvent")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SecurityEvent) *errors.Error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// Touch provides a mock function with given fields: ctx, session
+func (_m *SessionsRepo) Touch(ctx context.Context, session *models.Session) *errors.Error {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Touch")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Session) *errors.Error); ok {
+		r0 = rf(ctx, session)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewSessionsRepo creates a new instance of SessionsRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionsRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionsRepo {
+	mock := &SessionsRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,71 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "messanger/domain/models"
+)
+
+// SmsRepo is an autogenerated mock type for the SmsRepo type
+type SmsRepo struct {
+	mock.Mock
+}
+
+// New provides a mock function with given fields: ctx, msg
+func (_m *SmsRepo) New(ctx context.Context, msg *models.SmsMessage) *errors.Error {
+	ret := _m.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for New")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SmsMessage) *errors.Error); ok {
+		r0 = rf(ctx, msg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, msg
+func (_m *SmsRepo) Update(ctx context.Context, msg *models.SmsMessage) *errors.Error {
+	ret := _m.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SmsMessage) *errors.Error); ok {
+		r0 = rf(ctx, msg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewSmsRepo creates a new instance of SmsRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSmsRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SmsRepo {
+	mock := &SmsRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
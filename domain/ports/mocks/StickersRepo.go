@@ -0,0 +1,277 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "messanger/domain/models"
+)
+
+// StickersRepo is an autogenerated mock type for the StickersRepo type
+type StickersRepo struct {
+	mock.Mock
+}
+
+// AddSticker provides a mock function with given fields: ctx, sticker
+func (_m *StickersRepo) AddSticker(ctx context.Context, sticker *models.Sticker) *errors.Error {
+	ret := _m.Called(ctx, sticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSticker")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Sticker) *errors.Error); ok {
+		r0 = rf(ctx, sticker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// CheckPackInstalled provides a mock function with given fields: ctx, userId, packId
+func (_m *StickersRepo) CheckPackInstalled(ctx context.Context, userId int, packId int) (bool, *errors.Error) {
+	ret := _m.Called(ctx, userId, packId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckPackInstalled")
+	}
+
+	var r0 bool
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (bool, *errors.Error)); ok {
+		return rf(ctx, userId, packId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) bool); ok {
+		r0 = rf(ctx, userId, packId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) *errors.Error); ok {
+		r1 = rf(ctx, userId, packId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeletePack provides a mock function with given fields: ctx, id
+func (_m *StickersRepo) DeletePack(ctx context.Context, id int) *errors.Error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePack")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) *errors.Error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// DeleteSticker provides a mock function with given fields: ctx, id
+func (_m *StickersRepo) DeleteSticker(ctx context.Context, id int) *errors.Error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSticker")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) *errors.Error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetInstalledPacks provides a mock function with given fields: ctx, userId
+func (_m *StickersRepo) GetInstalledPacks(ctx context.Context, userId int) ([]models.StickerPack, *errors.Error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInstalledPacks")
+	}
+
+	var r0 []models.StickerPack
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.StickerPack, *errors.Error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.StickerPack); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.StickerPack)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetPackById provides a mock function with given fields: ctx, id
+func (_m *StickersRepo) GetPackById(ctx context.Context, id int) (*models.StickerPack, *errors.Error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPackById")
+	}
+
+	var r0 *models.StickerPack
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.StickerPack, *errors.Error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.StickerPack); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.StickerPack)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetStickerById provides a mock function with given fields: ctx, id
+func (_m *StickersRepo) GetStickerById(ctx context.Context, id int) (*models.Sticker, *errors.Error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStickerById")
+	}
+
+	var r0 *models.Sticker
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Sticker, *errors.Error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Sticker); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Sticker)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// InstallPack provides a mock function with given fields: ctx, userId, packId
+func (_m *StickersRepo) InstallPack(ctx context.Context, userId int, packId int) *errors.Error {
+	ret := _m.Called(ctx, userId, packId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InstallPack")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *errors.Error); ok {
+		r0 = rf(ctx, userId, packId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewPack provides a mock function with given fields: ctx, pack
+func (_m *StickersRepo) NewPack(ctx context.Context, pack *models.StickerPack) *errors.Error {
+	ret := _m.Called(ctx, pack)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewPack")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.StickerPack) *errors.Error); ok {
+		r0 = rf(ctx, pack)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// UninstallPack provides a mock function with given fields: ctx, userId, packId
+func (_m *StickersRepo) UninstallPack(ctx context.Context, userId int, packId int) *errors.Error {
+	ret := _m.Called(ctx, userId, packId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UninstallPack")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *errors.Error); ok {
+		r0 = rf(ctx, userId, packId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewStickersRepo creates a new instance of StickersRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStickersRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StickersRepo {
+	mock := &StickersRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,183 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	errors "messanger/pkg/errors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "messanger/domain/models"
+)
+
+// TOTPRepo is an autogenerated mock type for the TOTPRepo type
+type TOTPRepo struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, userId
+func (_m *TOTPRepo) Delete(ctx context.Context, userId int) *errors.Error {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) *errors.Error); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// Enable provides a mock function with given fields: ctx, userId
+func (_m *TOTPRepo) Enable(ctx context.Context, userId int) *errors.Error {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enable")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) *errors.Error); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, userId
+func (_m *TOTPRepo) Get(ctx context.Context, userId int) (*models.TOTP, *errors.Error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *models.TOTP
+	var r1 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.TOTP, *errors.Error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.TOTP); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TOTP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*errors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// Set provides a mock function with given fields: ctx, totp
+func (_m *TOTPRepo) Set(ctx context.Context, totp *models.TOTP) *errors.Error {
+	ret := _m.Called(ctx, totp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TOTP) *errors.Error); ok {
+		r0 = rf(ctx, totp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// SetRecoveryCodes provides a mock function with given fields: ctx, userId, hashes
+func (_m *TOTPRepo) SetRecoveryCodes(ctx context.Context, userId int, hashes []string) *errors.Error {
+	ret := _m.Called(ctx, userId, hashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRecoveryCodes")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string) *errors.Error); ok {
+		r0 = rf(ctx, userId, hashes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, userId, hash
+func (_m *TOTPRepo) UseRecoveryCode(ctx context.Context, userId int, hash string) *errors.Error {
+	ret := _m.Called(ctx, userId, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *errors.Error); ok {
+		r0 = rf(ctx, userId, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// UseStep provides a mock function with given fields: ctx, userId, step
+func (_m *TOTPRepo) UseStep(ctx context.Context, userId int, step int64) *errors.Error {
+	ret := _m.Called(ctx, userId, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseStep")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64) *errors.Error); ok {
+		r0 = rf(ctx, userId, step)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewTOTPRepo creates a new instance of TOTPRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTOTPRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TOTPRepo {
+	mock := &TOTPRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -18,6 +18,26 @@ type UsersRepo struct {
 	mock.Mock
 }
 
+// ConfirmEmail provides a mock function with given fields: ctx, userId, email
+func (_m *UsersRepo) ConfirmEmail(ctx context.Context, userId int, email string) *errors.Error {
+	ret := _m.Called(ctx, userId, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmEmail")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *errors.Error); ok {
+		r0 = rf(ctx, userId, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *UsersRepo) Delete(ctx context.Context, id int) *errors.Error {
 	ret := _m.Called(ctx, id)
@@ -134,9 +154,9 @@ func (_m *UsersRepo) GetById(ctx context.Context, id int) (*models.User, *errors
 	return r0, r1
 }
 
-// GetByIdWithPass provides a mock function with given fields: ctx, id, password
-func (_m *UsersRepo) GetByIdWithPass(ctx context.Context, id int, password string) (*models.User, *errors.Error) {
-	ret := _m.Called(ctx, id, password)
+// GetByIdWithPass provides a mock function with given fields: ctx, id
+func (_m *UsersRepo) GetByIdWithPass(ctx context.Context, id int) (*models.User, *errors.Error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByIdWithPass")
@@ -144,19 +164,19 @@ func (_m *UsersRepo) GetByIdWithPass(ctx context.Context, id int, password strin
 
 	var r0 *models.User
 	var r1 *errors.Error
-	if rf, ok := ret.Get(0).(func(context.Context, int, string) (*models.User, *errors.Error)); ok {
-		return rf(ctx, id, password)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.User, *errors.Error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, string) *models.User); ok {
-		r0 = rf(ctx, id, password)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, string) *errors.Error); ok {
-		r1 = rf(ctx, id, password)
+	if rf, ok := ret.Get(1).(func(context.Context, int) *errors.Error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*errors.Error)
@@ -166,9 +186,9 @@ func (_m *UsersRepo) GetByIdWithPass(ctx context.Context, id int, password strin
 	return r0, r1
 }
 
-// GetByPhoneWithPass provides a mock function with given fields: ctx, phone, password
-func (_m *UsersRepo) GetByPhoneWithPass(ctx context.Context, phone string, password string) (*models.User, *errors.Error) {
-	ret := _m.Called(ctx, phone, password)
+// GetByPhoneWithPass provides a mock function with given fields: ctx, phone
+func (_m *UsersRepo) GetByPhoneWithPass(ctx context.Context, phone string) (*models.User, *errors.Error) {
+	ret := _m.Called(ctx, phone)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByPhoneWithPass")
@@ -176,19 +196,19 @@ func (_m *UsersRepo) GetByPhoneWithPass(ctx context.Context, phone string, passw
 
 	var r0 *models.User
 	var r1 *errors.Error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.User, *errors.Error)); ok {
-		return rf(ctx, phone, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, *errors.Error)); ok {
+		return rf(ctx, phone)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.User); ok {
-		r0 = rf(ctx, phone, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, phone)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) *errors.Error); ok {
-		r1 = rf(ctx, phone, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string) *errors.Error); ok {
+		r1 = rf(ctx, phone)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*errors.Error)
@@ -288,6 +308,26 @@ func (_m *UsersRepo) SetShowPhone(ctx context.Context, userId int, v bool) *erro
 	return r0
 }
 
+// UpdateEmail provides a mock function with given fields: ctx, userId, email
+func (_m *UsersRepo) UpdateEmail(ctx context.Context, userId int, email string) *errors.Error {
+	ret := _m.Called(ctx, userId, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmail")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *errors.Error); ok {
+		r0 = rf(ctx, userId, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
 // UpdateLastOnlineTime provides a mock function with given fields: ctx, userId, _a2
 func (_m *UsersRepo) UpdateLastOnlineTime(ctx context.Context, userId int, _a2 time.Time) *errors.Error {
 	ret := _m.Called(ctx, userId, _a2)
@@ -308,9 +348,9 @@ func (_m *UsersRepo) UpdateLastOnlineTime(ctx context.Context, userId int, _a2 t
 	return r0
 }
 
-// UpdatePassword provides a mock function with given fields: ctx, userId, password
-func (_m *UsersRepo) UpdatePassword(ctx context.Context, userId int, password string) *errors.Error {
-	ret := _m.Called(ctx, userId, password)
+// UpdatePassword provides a mock function with given fields: ctx, userId, hash
+func (_m *UsersRepo) UpdatePassword(ctx context.Context, userId int, hash string) *errors.Error {
+	ret := _m.Called(ctx, userId, hash)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdatePassword")
@@ -318,7 +358,7 @@ func (_m *UsersRepo) UpdatePassword(ctx context.Context, userId int, password st
 
 	var r0 *errors.Error
 	if rf, ok := ret.Get(0).(func(context.Context, int, string) *errors.Error); ok {
-		r0 = rf(ctx, userId, password)
+		r0 = rf(ctx, userId, hash)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*errors.Error)
@@ -13,18 +13,45 @@ type UsersRepo interface {
 	UpdateUsername(ctx context.Context, userId int, name string) *errors.Error
 	UpdateRealName(ctx context.Context, userId int, realName string) *errors.Error
 	SetShowPhone(ctx context.Context, userId int, v bool) *errors.Error
-	UpdatePassword(ctx context.Context, userId int, password string) *errors.Error
+	UpdatePassword(ctx context.Context, userId int, hash string) *errors.Error
 	UpdatePhone(ctx context.Context, userId int, phone string) *errors.Error
+	UpdateEmail(ctx context.Context, userId int, email string) *errors.Error
+	ConfirmEmail(ctx context.Context, userId int, email string) *errors.Error
 	UpdateLastOnlineTime(ctx context.Context, userId int, time time.Time) *errors.Error
 	GetLastOnline(ctx context.Context, userId int) (time.Time, *errors.Error)
 	GetById(ctx context.Context, id int) (*models.User, *errors.Error)
 	FindByPhone(ctx context.Context, phone string) (*models.User, *errors.Error)
 	FindByName(ctx context.Context, name string) (*models.User, *errors.Error)
-	GetByPhoneWithPass(ctx context.Context, phone, password string) (*models.User, *errors.Error)
-	GetByIdWithPass(ctx context.Context, id int, password string) (*models.User, *errors.Error)
+	GetByPhoneWithPass(ctx context.Context, phone string) (*models.User, *errors.Error)
+	GetByIdWithPass(ctx context.Context, id int) (*models.User, *errors.Error)
 	Delete(ctx context.Context, id int) *errors.Error
 }
 
+type SessionsRepo interface {
+	New(ctx context.Context, session *models.Session) *errors.Error
+	GetById(ctx context.Context, id int) (*models.Session, *errors.Error)
+	GetByUser(ctx context.Context, userId int) ([]models.Session, *errors.Error)
+	Touch(ctx context.Context, session *models.Session) *errors.Error
+	Delete(ctx context.Context, id int) *errors.Error
+	NewSecurityEvent(ctx context.Context, event *models.SecurityEvent) *errors.Error
+	GetSecurityEvents(ctx context.Context, userId int, count int) ([]models.SecurityEvent, *errors.Error)
+}
+
+type TOTPRepo interface {
+	Set(ctx context.Context, totp *models.TOTP) *errors.Error
+	Get(ctx context.Context, userId int) (*models.TOTP, *errors.Error)
+	Enable(ctx context.Context, userId int) *errors.Error
+	UseStep(ctx context.Context, userId int, step int64) *errors.Error
+	Delete(ctx context.Context, userId int) *errors.Error
+	SetRecoveryCodes(ctx context.Context, userId int, hashes []string) *errors.Error
+	UseRecoveryCode(ctx context.Context, userId int, hash string) *errors.Error
+}
+
+type SmsRepo interface {
+	New(ctx context.Context, msg *models.SmsMessage) *errors.Error
+	Update(ctx context.Context, msg *models.SmsMessage) *errors.Error
+}
+
 type ContactsRepo interface {
 	Create(ctx context.Context, contact *models.Contact) *errors.Error
 	SetContactName(ctx context.Context, userid, contactId int, name string) *errors.Error
@@ -36,6 +63,11 @@ type ContactsRepo interface {
 type GroupsRepo interface {
 	New(ctx context.Context, group *models.Group) *errors.Error
 	Update(ctx context.Context, group *models.Group) *errors.Error
+	SetSlowMode(ctx context.Context, id int, seconds int) *errors.Error
+	SetAvatar(ctx context.Context, id int, avatar string) *errors.Error
+	SetUsername(ctx context.Context, id int, username string) *errors.Error
+	GetByUsername(ctx context.Context, username string) (*models.Group, *errors.Error)
+	SearchPublic(ctx context.Context, query string, limit int) ([]models.PublicGroup, *errors.Error)
 	GetGroupsByUser(ctx context.Context, userId int) ([]models.Group, *errors.Error)
 	GetGroupByChatId(ctx context.Context, chatId int) (*models.Group, *errors.Error)
 	GetUsersByGroup(ctx context.Context, id int) ([]int, *errors.Error)
@@ -43,11 +75,46 @@ type GroupsRepo interface {
 	GetById(ctx context.Context, id int) (*models.Group, *errors.Error)
 	SetRole(ctx context.Context, userId int, groupId int, role string) *errors.Error
 	GetRole(ctx context.Context, userId int, groupId int) (string, *errors.Error)
+	NewCustomRole(ctx context.Context, role *models.GroupRole) *errors.Error
+	GetCustomRole(ctx context.Context, groupId int, name string) (*models.GroupRole, *errors.Error)
+	GetCustomRoles(ctx context.Context, groupId int) ([]models.GroupRole, *errors.Error)
+	UpdateCustomRole(ctx context.Context, role *models.GroupRole) *errors.Error
+	DeleteCustomRole(ctx context.Context, groupId int, name string) *errors.Error
+	SetPermissionOverride(ctx context.Context, override *models.PermissionOverride) *errors.Error
+	GetPermissionOverride(ctx context.Context, userId int, groupId int) (*models.PermissionOverride, *errors.Error)
+	DeletePermissionOverride(ctx context.Context, userId int, groupId int) *errors.Error
 	Delete(ctx context.Context, id int) (e *errors.Error)
 }
 
+type InvitesRepo interface {
+	New(ctx context.Context, invite *models.GroupInvite) *errors.Error
+	GetById(ctx context.Context, id int) (*models.GroupInvite, *errors.Error)
+	GetByToken(ctx context.Context, token string) (*models.GroupInvite, *errors.Error)
+	GetByGroup(ctx context.Context, groupId int) ([]models.GroupInvite, *errors.Error)
+	Use(ctx context.Context, id int) (bool, *errors.Error)
+	Revoke(ctx context.Context, id int) *errors.Error
+	NewJoinRequest(ctx context.Context, request *models.JoinRequest) *errors.Error
+	GetJoinRequestById(ctx context.Context, id int) (*models.JoinRequest, *errors.Error)
+	GetJoinRequestsByGroup(ctx context.Context, groupId int) ([]models.JoinRequest, *errors.Error)
+	DeleteJoinRequest(ctx context.Context, id int) *errors.Error
+}
+
+type RestrictionsRepo interface {
+	Set(ctx context.Context, restriction *models.Restriction) *errors.Error
+	Get(ctx context.Context, groupId int, userId int, restrictionType string) (*models.Restriction, *errors.Error)
+	GetByGroup(ctx context.Context, groupId int, restrictionType string) ([]models.Restriction, *errors.Error)
+	Delete(ctx context.Context, groupId int, userId int, restrictionType string) *errors.Error
+	DeleteExpired(ctx context.Context) *errors.Error
+}
+
+type AuditRepo interface {
+	New(ctx context.Context, entry *models.AuditEntry) *errors.Error
+	GetByGroup(ctx context.Context, filter *models.AuditFilter) ([]models.AuditEntry, *errors.Error)
+}
+
 type ChatsRepo interface {
 	New(ctx context.Context, chat *models.Chat) *errors.Error
+	NewSaved(ctx context.Context, chat *models.Chat, userId int) *errors.Error
 	UpdateTime(ctx context.Context, chatId int, time time.Time) *errors.Error
 	AddUserToChat(ctx context.Context, id int, userId int) *errors.Error
 	RemoveUserFromChat(ctx context.Context, id int, userId int) *errors.Error
@@ -63,9 +130,44 @@ type ChatsRepo interface {
 type MessagesRepo interface {
 	New(ctx context.Context, message *models.Message) *errors.Error
 	GetByChat(ctx context.Context, chatId int, lastId int, count int) ([]models.Message, *errors.Error)
+	GetByChatAfter(ctx context.Context, chatId int, afterId int, count int) ([]models.Message, *errors.Error)
+	CountByChat(ctx context.Context, chatId int) (int, *errors.Error)
 	GetMinMassageIdInChat(ctx context.Context, chatId int) (int, *errors.Error)
 	IsUserMessage(ctx context.Context, id int, userId int) (bool, *errors.Error)
 	GetById(ctx context.Context, id int) (*models.Message, *errors.Error)
 	Update(ctx context.Context, id int, text string) *errors.Error
 	Delete(ctx context.Context, id int) *errors.Error
 }
+
+type ExportsRepo interface {
+	New(ctx context.Context, job *models.ExportJob) *errors.Error
+	GetById(ctx context.Context, id string) (*models.ExportJob, *errors.Error)
+	GetByToken(ctx context.Context, token string) (*models.ExportJob, *errors.Error)
+	Update(ctx context.Context, job *models.ExportJob) *errors.Error
+	FailStale(ctx context.Context, before time.Time, reason string) *errors.Error
+	GetExpired(ctx context.Context, now time.Time, failedBefore time.Time) ([]models.ExportJob, *errors.Error)
+	Delete(ctx context.Context, id string) *errors.Error
+}
+
+type ImportsRepo interface {
+	New(ctx context.Context, sourceId string, chatId int) *errors.Error
+}
+
+type BookmarksRepo interface {
+	New(ctx context.Context, bookmark *models.Bookmark) *errors.Error
+	GetByUser(ctx context.Context, userId int) ([]models.Bookmark, *errors.Error)
+	Delete(ctx context.Context, userId int, messageId int) *errors.Error
+}
+
+type StickersRepo interface {
+	NewPack(ctx context.Context, pack *models.StickerPack) *errors.Error
+	GetPackById(ctx context.Context, id int) (*models.StickerPack, *errors.Error)
+	DeletePack(ctx context.Context, id int) *errors.Error
+	AddSticker(ctx context.Context, sticker *models.Sticker) *errors.Error
+	GetStickerById(ctx context.Context, id int) (*models.Sticker, *errors.Error)
+	DeleteSticker(ctx context.Context, id int) *errors.Error
+	InstallPack(ctx context.Context, userId int, packId int) *errors.Error
+	UninstallPack(ctx context.Context, userId int, packId int) *errors.Error
+	CheckPackInstalled(ctx context.Context, userId int, packId int) (bool, *errors.Error)
+	GetInstalledPacks(ctx context.Context, userId int) ([]models.StickerPack, *errors.Error)
+}
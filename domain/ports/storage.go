@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"messanger/pkg/errors"
+)
+
+type FileStorage interface {
+	Save(ctx context.Context, ext string, r io.Reader) (string, *errors.Error)
+	Open(ctx context.Context, name string) (io.ReadSeekCloser, *errors.Error)
+	Delete(ctx context.Context, name string) *errors.Error
+}
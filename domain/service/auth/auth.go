@@ -2,55 +2,98 @@ package auth
 
 import (
 	"context"
+	errorsutils "errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"messanger/config"
 	"messanger/domain/models"
 	"messanger/domain/ports"
+	"messanger/domain/service/ratelimit"
 	"messanger/pkg/errors"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 const ErrInvalidToken = "invalid token"
 
 type AuthService struct {
-	cache     ports.Cache
-	repo      ports.UsersRepo
-	phoneConf PhoneConfirmator
+	cache        ports.Cache
+	repo         ports.UsersRepo
+	sessionsRepo ports.SessionsRepo
+	totpRepo     ports.TOTPRepo
+	phoneConf    PhoneConfirmator
+	disconnector UserDisconnector
 
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
-	accessTokenKey  []byte
+	keys            *keySet
 
 	blockDuration    time.Duration
 	maxLoginAttempts int
+
+	resetPhoneLimiter *ratelimit.Limiter
+	resetIPLimiter    *ratelimit.Limiter
 }
 
 type PhoneConfirmator interface {
-	ToConfirming(ctx context.Context, userId int, phone string) *errors.Error
-	ConfirmUser(ctx context.Context, code string) (int, *errors.Error)
+	SendCode(ctx context.Context, purpose string, userId int, phone string) *errors.Error
+	SendCodeByEmail(ctx context.Context, purpose string, userId int, phone, email string) *errors.Error
+	CheckCode(ctx context.Context, purpose string, phone string, code string) (int, *errors.Error)
+}
+
+// accessClaims are standard claims of access token, sub is user id, sid is session id,
+// ver is token version of user at the time of issue
+type accessClaims struct {
+	jwt.RegisteredClaims
+	SessionId int `json:"sid"`
+	Version   int `json:"ver,omitempty"`
+}
+
+// UserDisconnector closes live connections of user or session whose tokens were revoked
+type UserDisconnector interface {
+	DisconnectUser(userId int)
+	DisconnectSession(userId int, sessionId int)
 }
 
-func NewAuthService(cache ports.Cache, repo ports.UsersRepo, phoneConf PhoneConfirmator, cfg *config.AuthServiceConfig) *AuthService {
+func NewAuthService(cache ports.Cache, repo ports.UsersRepo, sessionsRepo ports.SessionsRepo, totpRepo ports.TOTPRepo, phoneConf PhoneConfirmator, cfg *config.AuthServiceConfig) (*AuthService, error) {
 	accessTokenTTL := time.Duration(cfg.AccessTokenTTLMin) * time.Minute
 	refreshTokenTTL := time.Duration(cfg.RefreshTokenTTLDays) * time.Hour * 24
 	blockDuration := time.Duration(cfg.DurationBlockUserMin) * time.Minute
 
+	// replaced key must verify tokens until the last of them expires
+	overlap := max(time.Duration(cfg.KeyOverlapMin)*time.Minute, accessTokenTTL)
+	keys, err := newKeySet(cfg.SigningAlg, cfg.SigningKeysDir, cfg.AccessTokenSignKey,
+		time.Duration(cfg.KeyRotationHours)*time.Hour, overlap)
+	if err != nil {
+		return nil, err
+	}
+
 	return &AuthService{
 		cache:            cache,
 		repo:             repo,
+		sessionsRepo:     sessionsRepo,
+		totpRepo:         totpRepo,
 		phoneConf:        phoneConf,
 		refreshTokenTTL:  refreshTokenTTL,
 		accessTokenTTL:   accessTokenTTL,
-		accessTokenKey:   []byte(cfg.AccessTokenSignKey),
+		keys:             keys,
 		blockDuration:    blockDuration,
 		maxLoginAttempts: cfg.LoginAttempts,
-	}
+
+		resetPhoneLimiter: ratelimit.NewLimiterEvery(cache, resetPhoneInterval, resetPhoneBurst),
+		resetIPLimiter:    ratelimit.NewLimiterEvery(cache, resetIPInterval, resetIPBurst),
+	}, nil
+}
+
+func (s *AuthService) SetDisconnector(disconnector UserDisconnector) {
+	s.disconnector = disconnector
 }
 
-func (s *AuthService) Login1FA(ctx context.Context, phone, password string) *errors.Error {
+// Login1FA checks password and starts second factor, method is SMS code (default), code sent to confirmed email
+// or authenticator app code
+func (s *AuthService) Login1FA(ctx context.Context, phone, password, method string) *errors.Error {
 	phone, e := models.ParsePhone(phone)
 	if e != nil {
 		return errors.New(e, "invalid phone number", http.StatusBadRequest)
@@ -68,7 +111,7 @@ func (s *AuthService) Login1FA(ctx context.Context, phone, password string) *err
 			http.StatusTooManyRequests)
 	}
 
-	user, err := s.repo.GetByPhoneWithPass(ctx, phone, password)
+	user, err := s.checkPassword(ctx, phone, password)
 	if err != nil {
 		if err.Code == http.StatusUnauthorized {
 			attempts++
@@ -80,22 +123,54 @@ func (s *AuthService) Login1FA(ctx context.Context, phone, password string) *err
 	}
 	s.cache.Del(ctx, phone)
 
-	if err := s.phoneConf.ToConfirming(ctx, user.Id, user.Phone); err != nil {
-		return err.Trace()
+	switch method {
+	case "", models.LoginMethodSMS:
+		if err := s.cache.Del(ctx, totpLoginKey(user.Phone)); err != nil {
+			return err.Trace()
+		}
+		if err := s.phoneConf.SendCode(ctx, models.CodePurposeLogin, user.Id, user.Phone); err != nil {
+			return err.Trace()
+		}
+	case models.LoginMethodEmail:
+		if len(user.Email) == 0 || !user.EmailConfirmed {
+			return errors.New1Msg("email is not confirmed", http.StatusBadRequest)
+		}
+		if err := s.cache.Del(ctx, totpLoginKey(user.Phone)); err != nil {
+			return err.Trace()
+		}
+		if err := s.phoneConf.SendCodeByEmail(ctx, models.CodePurposeLogin, user.Id, user.Phone, user.Email); err != nil {
+			return err.Trace()
+		}
+	case models.LoginMethodTOTP:
+		if err := s.toTOTPConfirming(ctx, user); err != nil {
+			return err.Trace()
+		}
+	default:
+		return errors.New1Msg("unknown login method: "+method, http.StatusBadRequest)
 	}
 
 	return nil
 }
 
-func (s *AuthService) Login2FA(ctx context.Context, phone string, code string) (*models.Tokens, *errors.Error) {
+func (s *AuthService) Login2FA(ctx context.Context, phone string, code string, client *models.ClientInfo) (*models.Tokens, *errors.Error) {
 	phone, e := models.ParsePhone(phone)
 	if e != nil {
 		return nil, errors.New(e, "invalid phone number", http.StatusBadRequest)
 	}
-	userId, err := s.phoneConf.ConfirmUser(ctx, code)
+	userId, err := s.cache.Get(ctx, totpLoginKey(phone))
 	if err != nil {
 		return nil, err.Trace()
 	}
+	if userId != 0 {
+		if err := s.confirmTOTPLogin(ctx, phone, userId, code); err != nil {
+			return nil, err.Trace()
+		}
+	} else {
+		userId, err = s.phoneConf.CheckCode(ctx, models.CodePurposeLogin, phone, code)
+		if err != nil {
+			return nil, err.Trace()
+		}
+	}
 
 	user, err := s.repo.GetById(ctx, userId)
 	if err != nil {
@@ -106,107 +181,165 @@ func (s *AuthService) Login2FA(ctx context.Context, phone string, code string) (
 		return nil, errors.New1Msg("invalid phone number", http.StatusUnauthorized)
 	}
 
-	access, accessExpires, err := s.NewAccessToken(userId)
+	session, err := s.newSession(ctx, userId, client)
 	if err != nil {
 		return nil, err.Trace()
 	}
-
-	refreshExpired := time.Now().Add(s.refreshTokenTTL)
-	refresh := s.newRefreshToken()
-	if err := s.cache.Set(ctx, refresh, userId, s.refreshTokenTTL); err != nil {
+	tokens, err := s.newTokens(ctx, session)
+	if err != nil {
 		return nil, err.Trace()
 	}
-
-	return &models.Tokens{
-		AccessToken:           access,
-		RefreshToken:          refresh,
-		AccessTokenExpiresAt:  accessExpires,
-		RefreshTokenExpiresAt: refreshExpired,
-	}, nil
+	return tokens, nil
 }
 
-func (s *AuthService) UpdateTokens(ctx context.Context, refresh string) (*models.Tokens, *errors.Error) {
+func (s *AuthService) UpdateTokens(ctx context.Context, refresh string, client *models.ClientInfo) (*models.Tokens, *errors.Error) {
 	if len(refresh) == 0 {
 		return nil, errors.New1Msg("missing refresh token", http.StatusBadRequest)
 	}
-	userId, err := s.cache.Get(ctx, refresh)
+	sessionId, err := s.cache.Get(ctx, refreshTokenKey(refresh))
 	if err != nil {
 		return nil, err.Trace()
 	}
-	if userId == 0 {
+	if sessionId == 0 {
+		if err := s.checkRefreshReuse(ctx, refresh, client); err != nil {
+			return nil, err.Trace()
+		}
 		return nil, errors.New1Msg("invalid refresh token", http.StatusUnauthorized)
 	}
 
-	if err := s.cache.Del(ctx, refresh); err != nil {
+	// token is marked used before claim, so request which lost the claim detects reuse
+	if err := s.cache.Set(ctx, usedRefreshTokenKey(refresh), sessionId, s.refreshTokenTTL); err != nil {
+		return nil, err.Trace()
+	}
+	claimed, err := s.cache.CompareAndSet(ctx, refreshTokenKey(refresh), sessionId, 0, s.refreshTokenTTL)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if !claimed {
+		if err := s.checkRefreshReuse(ctx, refresh, client); err != nil {
+			return nil, err.Trace()
+		}
+		return nil, errors.New1Msg("invalid refresh token", http.StatusUnauthorized)
+	}
+	if err := s.cache.Del(ctx, refreshTokenKey(refresh)); err != nil {
 		return nil, err.Trace()
 	}
 
-	access, accessExpired, err := s.NewAccessToken(userId)
+	session, err := s.sessionsRepo.GetById(ctx, sessionId)
+	if err != nil {
+		if err.Code == http.StatusNotFound {
+			return nil, errors.New(err, "session revoked", http.StatusUnauthorized)
+		}
+		return nil, err.Trace()
+	}
+	client.Truncate()
+	session.UserAgent = client.UserAgent
+	session.IP = client.IP
+	session.LastUsedTime = time.Now()
+	session.ExpireTime = session.LastUsedTime.Add(s.refreshTokenTTL)
+	if err := s.sessionsRepo.Touch(ctx, session); err != nil {
+		return nil, err.Trace()
+	}
+
+	tokens, err := s.newTokens(ctx, session)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return tokens, nil
+}
+
+// newTokens issues access token and refresh token bound to session
+func (s *AuthService) newTokens(ctx context.Context, session *models.Session) (*models.Tokens, *errors.Error) {
+	version, err := s.tokenVersion(ctx, session.UserId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	access, accessExpires, err := s.NewAccessToken(session.UserId, session.Id, version)
 	if err != nil {
 		return nil, err.Trace()
 	}
 
 	refreshExpired := time.Now().Add(s.refreshTokenTTL)
-	newRefresh := s.newRefreshToken()
-	if err := s.cache.Set(ctx, newRefresh, userId, s.refreshTokenTTL); err != nil {
+	refresh := s.newRefreshToken()
+	if err := s.cache.Set(ctx, refreshTokenKey(refresh), session.Id, s.refreshTokenTTL); err != nil {
 		return nil, err.Trace()
 	}
 
 	return &models.Tokens{
 		AccessToken:           access,
-		RefreshToken:          newRefresh,
-		AccessTokenExpiresAt:  accessExpired,
+		RefreshToken:          refresh,
+		AccessTokenExpiresAt:  accessExpires,
 		RefreshTokenExpiresAt: refreshExpired,
-	}, err
+	}, nil
 }
 
-func (s *AuthService) DecodeAccessToken(access string) (int, *errors.Error) {
-	claims, err := jwt.Parse(access, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+// DecodeAccessToken returns user and session of access token, tokens of revoked sessions
+// and tokens issued before user tokens were revoked are rejected
+func (s *AuthService) DecodeAccessToken(ctx context.Context, access string) (userId int, sessionId int, err *errors.Error) {
+	claims := new(accessClaims)
+	_, e := jwt.ParseWithClaims(access, claims, s.keys.keyFunc,
+		jwt.WithValidMethods([]string{s.keys.method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if e != nil {
+		if errorsutils.Is(e, jwt.ErrTokenExpired) {
+			return 0, 0, errors.New(e, "token expired", http.StatusUnauthorized)
 		}
+		return 0, 0, errors.New(e, ErrInvalidToken, http.StatusUnauthorized)
+	}
 
-		return s.accessTokenKey, nil
-	})
-
-	if err != nil {
-		return 0, errors.New(err, ErrInvalidToken, http.StatusUnauthorized)
+	userId, e = strconv.Atoi(claims.Subject)
+	if e != nil {
+		return 0, 0, errors.New(e, ErrInvalidToken, http.StatusUnauthorized)
 	}
-	mapClaims, ok := claims.Claims.(jwt.MapClaims)
-	if !ok {
-		return 0, errors.New("claims is not a map", ErrInvalidToken, http.StatusUnauthorized)
+	if claims.SessionId == 0 {
+		return 0, 0, errors.New("sid is not in claims", ErrInvalidToken, http.StatusUnauthorized)
 	}
 
-	id, ok := mapClaims["id"]
-	if !ok {
-		return 0, errors.New("id is not in map claims", ErrInvalidToken, http.StatusUnauthorized)
+	revoked, err := s.cache.Get(ctx, revokedSessionKey(claims.SessionId))
+	if err != nil {
+		return 0, 0, err.Trace()
 	}
-	tUnix, ok := mapClaims["expires"]
-	if !ok {
-		return 0, errors.New("expires time is not in map claims", ErrInvalidToken, http.StatusUnauthorized)
+	if revoked != 0 {
+		return 0, 0, errors.New1Msg("session revoked", http.StatusUnauthorized)
 	}
-
-	if time.Now().After(time.Unix(int64(tUnix.(float64)), 0)) {
-		return 0, errors.New1Msg("token expired", http.StatusUnauthorized)
+	version, err := s.cache.Get(ctx, tokenVersionKey(userId))
+	if err != nil {
+		return 0, 0, err.Trace()
+	}
+	if claims.Version != version {
+		return 0, 0, errors.New1Msg("token revoked", http.StatusUnauthorized)
 	}
 
-	return int(id.(float64)), nil
+	return userId, claims.SessionId, nil
 }
 
-func (s *AuthService) NewAccessToken(id int) (string, time.Time, *errors.Error) {
-	expires := time.Now().Add(s.accessTokenTTL)
-
-	claims := jwt.NewWithClaims(jwt.SigningMethodHS512, jwt.MapClaims{
-		"id":      id,
-		"expires": expires.Unix(),
+func (s *AuthService) NewAccessToken(id int, sessionId int, version int) (string, time.Time, *errors.Error) {
+	now := time.Now()
+	expires := now.Add(s.accessTokenTTL)
+
+	token, err := s.keys.sign(&accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(id),
+			ExpiresAt: jwt.NewNumericDate(expires),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        uuid.NewString(),
+		},
+		SessionId: sessionId,
+		Version:   version,
 	})
-	token, err := claims.SignedString(s.accessTokenKey)
 	if err != nil {
 		return "", expires, errors.New(err, "create token error", http.StatusBadRequest)
 	}
 	return token, expires, nil
 }
 
+// JWKS returns public keys which verify access tokens
+func (s *AuthService) JWKS() *JWKS {
+	return s.keys.jwks()
+}
+
 func (s *AuthService) newRefreshToken() string {
 	uid, _ := uuid.NewUUID()
 	return uid.String()
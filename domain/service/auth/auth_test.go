@@ -2,15 +2,22 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"messanger/config"
 	cache "messanger/data/cache/local"
+	mail "messanger/data/mail/mail_chan"
 	sms "messanger/data/sms/sms_chan"
 	"messanger/domain/models"
 	"messanger/domain/ports"
 	"messanger/domain/ports/mocks"
 	"messanger/domain/service/phone"
+	"messanger/pkg/errors"
+	"messanger/pkg/password"
+	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -22,55 +29,273 @@ var authCfg = &config.AuthServiceConfig{
 	LoginAttempts:        1,
 }
 
+// phoneCfg doesn't throttle resend, tests login the same user many times
+var phoneCfg = &config.PhoneConfig{
+	CodeTTLSec:        60,
+	ResendIntervalSec: -1,
+	MaxAttempts:       3,
+	LockoutMin:        5,
+}
+
 var c ports.Cache
 var smsChan *sms.SmsChan
+var mailChan *mail.MailChan
 var phoneConf PhoneConfirmator
 
+const testPassword = "password1"
+
 func init() {
 	c = cache.NewCache()
 	smsChan = sms.NewSmsChan()
-	phoneConf = phone.NewPhoneService(smsChan, c)
+	mailChan = mail.NewMailChan()
+	phoneConf = phone.NewPhoneService(smsChan, mailChan, c, phoneCfg)
+
+	testUser.Password, _ = password.Hash(testPassword)
 }
 
-func TestAuth(t *testing.T) {
-	user := &models.User{
-		Id:        1,
-		Phone:     "70000000000",
-		Password:  "123456",
-		Name:      "name",
-		RealName:  "Aleksey",
-		ShowPhone: true,
-		Confirmed: true,
-	}
+var testUser = &models.User{
+	Id:        1,
+	Phone:     "+79161234567",
+	Name:      "name",
+	RealName:  "Aleksey",
+	ShowPhone: true,
+	Confirmed: true,
+}
+
+var testClient = &models.ClientInfo{DeviceName: "test", IP: "127.0.0.1"}
+
+// lastSessionId keeps session ids unique between tests, because revoked sessions are stored in shared cache
+var lastSessionId atomic.Int32
 
+func newTestAuthService(t *testing.T) (*AuthService, *mocks.SessionsRepo, *mocks.TOTPRepo) {
+	auth, sessionsRepo, totpRepo, _ := newTestAuthServiceWithUsers(t)
+	return auth, sessionsRepo, totpRepo
+}
+
+func newTestAuthServiceWithUsers(t *testing.T) (*AuthService, *mocks.SessionsRepo, *mocks.TOTPRepo, *mocks.UsersRepo) {
 	userRepo := mocks.NewUsersRepo(t)
+	userRepo.On("GetByPhoneWithPass", mock.Anything, testUser.Phone).Return(testUser, nil).Maybe()
+	userRepo.On("GetById", mock.Anything, testUser.Id).Return(testUser, nil).Maybe()
+
+	var mu sync.Mutex
+	sessions := make(map[int]*models.Session)
+	sessionsRepo := mocks.NewSessionsRepo(t)
+	sessionsRepo.On("New", mock.Anything, mock.Anything).Return(func(_ context.Context, session *models.Session) *errors.Error {
+		mu.Lock()
+		defer mu.Unlock()
+		session.Id = int(lastSessionId.Add(1))
+		sessions[session.Id] = session
+		return nil
+	}).Maybe()
+	sessionsRepo.On("GetById", mock.Anything, mock.Anything).Return(func(_ context.Context, id int) (*models.Session, *errors.Error) {
+		mu.Lock()
+		defer mu.Unlock()
+		session, ok := sessions[id]
+		if !ok {
+			return nil, errors.New1Msg("session not found", http.StatusNotFound)
+		}
+		return session, nil
+	}).Maybe()
+	sessionsRepo.On("GetByUser", mock.Anything, testUser.Id).Return(func(_ context.Context, userId int) ([]models.Session, *errors.Error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var res []models.Session
+		for _, session := range sessions {
+			if session.UserId == userId {
+				res = append(res, *session)
+			}
+		}
+		return res, nil
+	}).Maybe()
+	sessionsRepo.On("Touch", mock.Anything, mock.Anything).Return(nil).Maybe()
+	sessionsRepo.On("Delete", mock.Anything, mock.Anything).Return(func(_ context.Context, id int) *errors.Error {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(sessions, id)
+		return nil
+	}).Maybe()
+
+	totpRepo := mocks.NewTOTPRepo(t)
+
+	auth, err := NewAuthService(c, userRepo, sessionsRepo, totpRepo, phoneConf, authCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth, sessionsRepo, totpRepo, userRepo
+}
 
-	userRepo.On("GetByPhoneWithPass", mock.Anything, user.Phone, user.Password).Return(user, nil)
-	userRepo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+func login(t *testing.T, auth *AuthService) *models.Tokens {
+	if err := auth.Login1FA(context.Background(), testUser.Phone, testPassword, models.LoginMethodSMS); err != nil {
+		t.Fatal(err)
+	}
 
-	auth := NewAuthService(c, userRepo, phoneConf, authCfg)
+	code := smsChan.Code()
 
-	if err := auth.Login1FA(context.Background(), user.Phone, user.Password); err != nil {
-		t.Error(err)
+	tokens, err := auth.Login2FA(context.Background(), testUser.Phone, code, testClient)
+	if err != nil {
+		t.Fatal(err)
 	}
+	return tokens
+}
 
-	code := <-smsChan.Chan
+func TestAuth(t *testing.T) {
+	auth, _, _ := newTestAuthService(t)
+	disconnected := make(testDisconnector, 1)
+	auth.SetDisconnector(disconnected)
+	tokens := login(t, auth)
 
-	tokens, err := auth.Login2FA(context.Background(), user.Phone, code)
+	userId, sessionId, err := auth.DecodeAccessToken(context.Background(), tokens.AccessToken)
 	if err != nil {
 		t.Fatal(err)
 	}
+	require.Equal(t, testUser.Id, userId, "invalid user id in token")
 
-	userId, err := auth.DecodeAccessToken(tokens.AccessToken)
+	newTokens, err := auth.UpdateTokens(context.Background(), tokens.RefreshToken, testClient)
 	if err != nil {
 		t.Fatal(err)
 	}
-	require.Equal(t, user.Id, userId, "invalid user id in token")
 
-	if _, err := auth.UpdateTokens(context.Background(), tokens.RefreshToken); err != nil {
+	ctx := CtxWithSession(CtxWithUser(context.Background(), userId), sessionId)
+	if err := auth.RevokeSession(ctx, sessionId); err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, sessionId, <-disconnected, "connections of revoked session should be closed")
+	if _, _, err := auth.DecodeAccessToken(context.Background(), newTokens.AccessToken); err == nil {
+		t.Fatal("access token of revoked session should be rejected")
+	}
+	if _, err := auth.UpdateTokens(context.Background(), newTokens.RefreshToken, testClient); err == nil {
+		t.Fatal("refresh token of revoked session should be rejected")
+	}
+}
+
+func TestRefreshTokenReuse(t *testing.T) {
+	auth, sessionsRepo, _ := newTestAuthService(t)
+	sessionsRepo.On("NewSecurityEvent", mock.Anything, mock.MatchedBy(func(event *models.SecurityEvent) bool {
+		return event.UserId == testUser.Id && event.Type == models.SecurityEventRefreshTokenReuse
+	})).Return(nil).Once()
+
+	tokens := login(t, auth)
+
+	newTokens, err := auth.UpdateTokens(context.Background(), tokens.RefreshToken, testClient)
+	if err != nil {
 		t.Fatal(err)
 	}
-	if _, err := auth.UpdateTokens(context.Background(), tokens.RefreshToken); err == nil {
-		t.Fatal("refresh token should not be updated")
+	if _, err := auth.UpdateTokens(context.Background(), tokens.RefreshToken, testClient); err == nil {
+		t.Fatal("rotated refresh token should not be updated")
+	}
+
+	if _, _, err := auth.DecodeAccessToken(context.Background(), newTokens.AccessToken); err == nil {
+		t.Fatal("access token of session with reused refresh token should be rejected")
+	}
+	if _, err := auth.UpdateTokens(context.Background(), newTokens.RefreshToken, testClient); err == nil {
+		t.Fatal("refresh token family should be revoked")
+	}
+}
+
+func TestConcurrentRefresh(t *testing.T) {
+	auth, sessionsRepo, _ := newTestAuthService(t)
+	sessionsRepo.On("NewSecurityEvent", mock.Anything, mock.Anything).Return(nil).Maybe()
+	tokens := login(t, auth)
+
+	const requests = 10
+	var wg sync.WaitGroup
+	var updated atomic.Int32
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := auth.UpdateTokens(context.Background(), tokens.RefreshToken, testClient); err == nil {
+				updated.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.LessOrEqual(t, updated.Load(), int32(1), "refresh token should be used once")
+}
+
+type testDisconnector chan int
+
+func (d testDisconnector) DisconnectUser(userId int) {
+	d <- userId
+}
+
+func (d testDisconnector) DisconnectSession(_ int, sessionId int) {
+	d <- sessionId
+}
+
+func TestRevokeUserTokens(t *testing.T) {
+	auth, _, _ := newTestAuthService(t)
+	disconnected := make(testDisconnector, 1)
+	auth.SetDisconnector(disconnected)
+
+	tokens := login(t, auth)
+	if err := auth.RevokeUserTokens(context.Background(), testUser.Id); err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, testUser.Id, <-disconnected)
+
+	if _, _, err := auth.DecodeAccessToken(context.Background(), tokens.AccessToken); err == nil {
+		t.Fatal("access token issued before revocation should be rejected")
+	}
+	if _, err := auth.UpdateTokens(context.Background(), tokens.RefreshToken, testClient); err == nil {
+		t.Fatal("refresh token issued before revocation should be rejected")
+	}
+
+	tokens = login(t, auth)
+	if _, _, err := auth.DecodeAccessToken(context.Background(), tokens.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLegacyPasswordRehash(t *testing.T) {
+	auth, _, _, userRepo := newTestAuthServiceWithUsers(t)
+
+	sum := sha256.Sum256([]byte(testPassword))
+	legacyUser := &models.User{
+		Id:        2,
+		Phone:     "+79161234568",
+		Password:  "nwy9837ctp5" + string(sum[:]),
+		Confirmed: true,
+	}
+	userRepo.On("GetByPhoneWithPass", mock.Anything, legacyUser.Phone).Return(legacyUser, nil)
+	userRepo.On("UpdatePassword", mock.Anything, legacyUser.Id, mock.MatchedBy(func(hash string) bool {
+		ok, needsRehash := password.Verify(testPassword, hash)
+		return ok && !needsRehash
+	})).Return(nil).Once()
+
+	if err := auth.Login1FA(context.Background(), legacyUser.Phone, testPassword, models.LoginMethodSMS); err != nil {
+		t.Fatal(err)
+	}
+	<-smsChan.Chan
+}
+
+func TestLoginByEmail(t *testing.T) {
+	auth, _, _, userRepo := newTestAuthServiceWithUsers(t)
+	emailUser := &models.User{
+		Id:        3,
+		Phone:     "+79161234570",
+		Password:  testUser.Password,
+		Confirmed: true,
+		Email:     "user@example.com",
+	}
+	userRepo.On("GetByPhoneWithPass", mock.Anything, emailUser.Phone).Return(emailUser, nil)
+	userRepo.On("GetById", mock.Anything, emailUser.Id).Return(emailUser, nil).Maybe()
+
+	err := auth.Login1FA(context.Background(), emailUser.Phone, testPassword, models.LoginMethodEmail)
+	require.NotNil(t, err, "unconfirmed email should not be used for login")
+	require.Equal(t, http.StatusBadRequest, err.Code)
+
+	emailUser.EmailConfirmed = true
+	if err := auth.Login1FA(context.Background(), emailUser.Phone, testPassword, models.LoginMethodEmail); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := auth.Login2FA(context.Background(), emailUser.Phone, mailChan.Code(), testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userId, _, err := auth.DecodeAccessToken(context.Background(), tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
 	}
+	require.Equal(t, emailUser.Id, userId)
 }
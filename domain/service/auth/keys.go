@@ -0,0 +1,354 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AlgHS512 = "HS512"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const rsaKeyBits = 2048
+
+// keysReloadInterval limits reloads of keys dir by tokens with unknown key id
+const keysReloadInterval = 10 * time.Second
+
+// JWK is public key of access tokens in JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type signingKey struct {
+	kid     string
+	created time.Time
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+// keySet signs access tokens with the newest started key and verifies them with any known key.
+// Asymmetric keys are stored in dir as <kid>.pem, kid starts with unix time the key starts signing.
+// Key is rotated every rotation, the next key is published one rotation before it starts signing,
+// so verifiers caching JWKS know it before its tokens appear. Previous key stays valid for overlap
+// after rotation so already issued tokens are not rejected. Directory is reloaded before each rotation
+// and on unknown key id, so instances sharing it converge to the same keys
+type keySet struct {
+	method   jwt.SigningMethod
+	dir      string
+	rotation time.Duration
+	overlap  time.Duration
+
+	mu sync.RWMutex
+	// keys are ordered by start time, the last one may not have started yet
+	keys     []*signingKey
+	signer   *signingKey
+	reloaded time.Time
+}
+
+func newKeySet(alg, dir, secret string, rotation, overlap time.Duration) (*keySet, error) {
+	switch alg {
+	case "", AlgHS512:
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("access token sign key is required for %s", AlgHS512)
+		}
+		key := &signingKey{private: []byte(secret), public: []byte(secret)}
+		return &keySet{
+			method: jwt.SigningMethodHS512,
+			keys:   []*signingKey{key},
+			signer: key,
+		}, nil
+	case AlgRS256, AlgEdDSA:
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+	if len(dir) == 0 {
+		return nil, fmt.Errorf("signing keys dir is required for %s", alg)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	ks := &keySet{
+		method:   jwt.GetSigningMethod(alg),
+		dir:      dir,
+		rotation: rotation,
+		overlap:  overlap,
+	}
+	if err := ks.rotate(time.Now()); err != nil {
+		return nil, err
+	}
+	if rotation > 0 {
+		go func() {
+			for {
+				time.Sleep(time.Minute)
+				ks.rotate(time.Now())
+			}
+		}()
+	}
+	return ks, nil
+}
+
+// rotate creates the first key if there are no keys, creates the next key when the last one has started,
+// switches signing to the newest started key and removes keys replaced more than overlap ago
+func (ks *keySet) rotate(now time.Time) error {
+	keys, err := ks.load()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		key, err := ks.generate(now)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	if last := keys[len(keys)-1]; ks.rotation > 0 && !last.created.After(now) {
+		start := last.created.Add(ks.rotation)
+		if !start.After(now) {
+			// keys were not rotated for longer than rotation, the next key still has to be published before use
+			start = now.Add(ks.rotation)
+		}
+		key, err := ks.generate(start)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	// key is retired when it has been replaced for longer than overlap
+	active := keys[:0]
+	var signer *signingKey
+	for i, key := range keys {
+		if i+1 < len(keys) && now.Sub(keys[i+1].created) >= ks.overlap {
+			os.Remove(ks.path(key.kid))
+			continue
+		}
+		active = append(active, key)
+		if !key.created.After(now) {
+			signer = key
+		}
+	}
+
+	ks.mu.Lock()
+	ks.keys = active
+	ks.signer = signer
+	ks.mu.Unlock()
+	return nil
+}
+
+// reload loads keys created by other instances, signing key is changed only by rotate
+func (ks *keySet) reload(now time.Time) error {
+	ks.mu.Lock()
+	if now.Sub(ks.reloaded) < keysReloadInterval {
+		ks.mu.Unlock()
+		return nil
+	}
+	ks.reloaded = now
+	ks.mu.Unlock()
+
+	keys, err := ks.load()
+	if err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.mu.Unlock()
+	return nil
+}
+
+func (ks *keySet) path(kid string) string {
+	return filepath.Join(ks.dir, kid+".pem")
+}
+
+func (ks *keySet) load() ([]*signingKey, error) {
+	files, err := filepath.Glob(filepath.Join(ks.dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*signingKey, 0, len(files))
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		unix, _, _ := strings.Cut(kid, "-")
+		created, err := strconv.ParseInt(unix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key id %s: %w", kid, err)
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("key %s is not PEM encoded", kid)
+		}
+		private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse key %s: %w", kid, err)
+		}
+		key := &signingKey{kid: kid, created: time.Unix(created, 0), private: private}
+		switch private := private.(type) {
+		case *rsa.PrivateKey:
+			key.public = &private.PublicKey
+		case ed25519.PrivateKey:
+			key.public = private.Public()
+		}
+		if ks.publicJWK(key) == nil {
+			return nil, fmt.Errorf("key %s does not match %s algorithm", kid, ks.method.Alg())
+		}
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b *signingKey) int {
+		return a.created.Compare(b.created)
+	})
+	return keys, nil
+}
+
+// generate creates key which starts signing at start
+func (ks *keySet) generate(start time.Time) (*signingKey, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	key := &signingKey{
+		kid:     strconv.FormatInt(start.Unix(), 10) + "-" + hex.EncodeToString(suffix),
+		created: time.Unix(start.Unix(), 0),
+	}
+
+	switch ks.method.Alg() {
+	case AlgRS256:
+		private, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		key.private, key.public = private, &private.PublicKey
+	case AlgEdDSA:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key.private, key.public = private, public
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key.private)
+	if err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(ks.path(key.kid), data, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (ks *keySet) sign(claims jwt.Claims) (string, error) {
+	ks.mu.RLock()
+	key := ks.signer
+	ks.mu.RUnlock()
+
+	token := jwt.NewWithClaims(ks.method, claims)
+	if len(key.kid) != 0 {
+		token.Header["kid"] = key.kid
+	}
+	return token.SignedString(key.private)
+}
+
+// keyFunc returns verification key by kid of token header, keys dir is reloaded on unknown kid,
+// because the key may be created by another instance
+func (ks *keySet) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key := ks.find(kid); key != nil {
+		return key.public, nil
+	}
+	if err := ks.reload(time.Now()); err != nil {
+		return nil, err
+	}
+	if key := ks.find(kid); key != nil {
+		return key.public, nil
+	}
+	return nil, fmt.Errorf("unknown key id: %q", kid)
+}
+
+func (ks *keySet) find(kid string) *signingKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if ks.method == jwt.SigningMethodHS512 {
+		return ks.keys[0]
+	}
+	for _, key := range ks.keys {
+		if key.kid == kid {
+			return key
+		}
+	}
+	return nil
+}
+
+// jwks returns public keys that verify currently valid tokens and the next key, it is empty for HS512
+func (ks *keySet) jwks() *JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	set := &JWKS{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, key := range ks.keys {
+		if jwk := ks.publicJWK(key); jwk != nil {
+			set.Keys = append(set.Keys, *jwk)
+		}
+	}
+	return set
+}
+
+// publicJWK returns nil if key can't be used with algorithm of key set
+func (ks *keySet) publicJWK(key *signingKey) *JWK {
+	jwk := &JWK{Kid: key.kid, Use: "sig", Alg: ks.method.Alg()}
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		if jwk.Alg != AlgRS256 {
+			return nil
+		}
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		if jwk.Alg != AlgEdDSA {
+			return nil
+		}
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	default:
+		return nil
+	}
+	return jwk
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestKeyRotation(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			const rotation, overlap = time.Hour, 10 * time.Minute
+			dir := t.TempDir()
+			ks, err := newKeySet(alg, dir, "", 0, overlap)
+			require.NoError(t, err)
+			ks.rotation = rotation
+
+			sign := func() string {
+				token, err := ks.sign(&accessClaims{
+					RegisteredClaims: jwt.RegisteredClaims{Subject: "1", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+					SessionId:        1,
+				})
+				require.NoError(t, err)
+				return token
+			}
+			verify := func(token string) error {
+				_, err := jwt.ParseWithClaims(token, new(accessClaims), ks.keyFunc, jwt.WithValidMethods([]string{alg}))
+				return err
+			}
+
+			// instance started before rotation, it doesn't know keys created later
+			other, err := newKeySet(alg, dir, "", 0, overlap)
+			require.NoError(t, err)
+
+			now := time.Now()
+			oldToken := sign()
+			oldKid := ks.signer.kid
+			require.NoError(t, ks.rotate(now))
+			require.Len(t, ks.jwks().Keys, 2, "next key should be published before it signs")
+			require.Equal(t, oldKid, ks.signer.kid, "current key should sign until the next one starts")
+
+			require.NoError(t, ks.rotate(now.Add(rotation)))
+			require.Len(t, ks.jwks().Keys, 3, "replaced key should be published during overlap")
+			require.Equal(t, ks.jwks().Keys[1].Kid, ks.signer.kid)
+			newToken := sign()
+			require.NoError(t, verify(oldToken))
+			require.NoError(t, verify(newToken))
+
+			// another instance sharing keys dir reloads keys on unknown key id
+			_, err = jwt.ParseWithClaims(newToken, new(accessClaims), other.keyFunc, jwt.WithValidMethods([]string{alg}))
+			require.NoError(t, err)
+			require.Equal(t, ks.jwks(), other.jwks())
+
+			require.NoError(t, ks.rotate(now.Add(rotation+overlap)))
+			require.Len(t, ks.jwks().Keys, 2)
+			require.Error(t, verify(oldToken), "token of retired key should be rejected")
+			require.NoError(t, verify(newToken))
+		})
+	}
+}
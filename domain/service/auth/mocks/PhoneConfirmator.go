@@ -14,27 +14,27 @@ type PhoneConfirmator struct {
 	mock.Mock
 }
 
-// ConfirmUser provides a mock function with given fields: ctx, code
-func (_m *PhoneConfirmator) ConfirmUser(ctx context.Context, code string) (int, *errors.Error) {
-	ret := _m.Called(ctx, code)
+// CheckCode provides a mock function with given fields: ctx, purpose, phone, code
+func (_m *PhoneConfirmator) CheckCode(ctx context.Context, purpose string, phone string, code string) (int, *errors.Error) {
+	ret := _m.Called(ctx, purpose, phone, code)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ConfirmUser")
+		panic("no return value specified for CheckCode")
 	}
 
 	var r0 int
 	var r1 *errors.Error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (int, *errors.Error)); ok {
-		return rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (int, *errors.Error)); ok {
+		return rf(ctx, purpose, phone, code)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
-		r0 = rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) int); ok {
+		r0 = rf(ctx, purpose, phone, code)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) *errors.Error); ok {
-		r1 = rf(ctx, code)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) *errors.Error); ok {
+		r1 = rf(ctx, purpose, phone, code)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*errors.Error)
@@ -44,17 +44,37 @@ func (_m *PhoneConfirmator) ConfirmUser(ctx context.Context, code string) (int,
 	return r0, r1
 }
 
-// ToConfirming provides a mock function with given fields: ctx, userId, phone
-func (_m *PhoneConfirmator) ToConfirming(ctx context.Context, userId int, phone string) *errors.Error {
-	ret := _m.Called(ctx, userId, phone)
+// SendCode provides a mock function with given fields: ctx, purpose, userId, phone
+func (_m *PhoneConfirmator) SendCode(ctx context.Context, purpose string, userId int, phone string) *errors.Error {
+	ret := _m.Called(ctx, purpose, userId, phone)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ToConfirming")
+		panic("no return value specified for SendCode")
 	}
 
 	var r0 *errors.Error
-	if rf, ok := ret.Get(0).(func(context.Context, int, string) *errors.Error); ok {
-		r0 = rf(ctx, userId, phone)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, string) *errors.Error); ok {
+		r0 = rf(ctx, purpose, userId, phone)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*errors.Error)
+		}
+	}
+
+	return r0
+}
+
+// SendCodeByEmail provides a mock function with given fields: ctx, purpose, userId, phone, email
+func (_m *PhoneConfirmator) SendCodeByEmail(ctx context.Context, purpose string, userId int, phone string, email string) *errors.Error {
+	ret := _m.Called(ctx, purpose, userId, phone, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendCodeByEmail")
+	}
+
+	var r0 *errors.Error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, string, string) *errors.Error); ok {
+		r0 = rf(ctx, purpose, userId, phone, email)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*errors.Error)
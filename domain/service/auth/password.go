@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"messanger/pkg/password"
+	"net/http"
+	"sync"
+)
+
+// dummyPasswordHash is verified when user is not found, so response time doesn't reveal registered phones
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := password.Hash("dummy password")
+	return hash
+})
+
+// checkPassword returns confirmed user with phone and password,
+// hash of user made with legacy algorithm or parameters is replaced after successful check
+func (s *AuthService) checkPassword(ctx context.Context, phone, pass string) (*models.User, *errors.Error) {
+	user, err := s.repo.GetByPhoneWithPass(ctx, phone)
+	if err != nil {
+		if err.Code == http.StatusUnauthorized {
+			password.Verify(pass, dummyPasswordHash())
+		}
+		return nil, err.Trace()
+	}
+
+	ok, needsRehash := password.Verify(pass, user.Password)
+	if !ok {
+		return nil, errors.New1Msg("invalid phone or password", http.StatusUnauthorized)
+	}
+	if needsRehash {
+		hash, e := password.Hash(pass)
+		if e != nil {
+			return nil, errors.New(e, "hash password error", http.StatusInternalServerError)
+		}
+		if err := s.repo.UpdatePassword(ctx, user.Id, hash); err != nil {
+			return nil, err.Trace()
+		}
+		user.Password = hash
+	}
+	return user, nil
+}
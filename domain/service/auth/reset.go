@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"messanger/pkg/password"
+	"net/http"
+	"time"
+)
+
+const (
+	resetTokenTTL = 15 * time.Minute
+
+	resetPhoneInterval = 10 * time.Minute
+	resetPhoneBurst    = 3
+	resetIPInterval    = time.Minute
+	resetIPBurst       = 10
+)
+
+const ErrTooManyResetRequests = "too many password reset requests"
+
+// resetTokenKey keeps user whose reset code was verified until new password is set
+func resetTokenKey(token string) string {
+	return "password_reset:" + token
+}
+
+// checkResetLimits limits reset requests by phone, so SMS are not sent to one phone too often,
+// and by IP of client, so codes are not brute forced
+func (s *AuthService) checkResetLimits(ctx context.Context, phone, ip string) *errors.Error {
+	wait, err := s.resetIPLimiter.Allow(ctx, "rl:reset:ip:"+ip)
+	if err != nil {
+		return err.Trace()
+	}
+	if wait > 0 {
+		return errors.New(fmt.Sprintf("ip %s exceeded password reset rate limit", ip), ErrTooManyResetRequests,
+			http.StatusTooManyRequests).WithRetryAfter(wait)
+	}
+	if len(phone) == 0 {
+		return nil
+	}
+	wait, err = s.resetPhoneLimiter.Allow(ctx, "rl:reset:phone:"+phone)
+	if err != nil {
+		return err.Trace()
+	}
+	if wait > 0 {
+		return errors.New(fmt.Sprintf("phone %s exceeded password reset rate limit", phone), ErrTooManyResetRequests,
+			http.StatusTooManyRequests).WithRetryAfter(wait)
+	}
+	return nil
+}
+
+// RequestPasswordReset sends reset code by sms (default) or to confirmed email of user.
+// Unknown phone or missing email is not reported, so registered phones can't be enumerated
+func (s *AuthService) RequestPasswordReset(ctx context.Context, phone, channel string, client *models.ClientInfo) *errors.Error {
+	phone, e := models.ParsePhone(phone)
+	if e != nil {
+		return errors.New(e, "invalid phone number", http.StatusBadRequest)
+	}
+	switch channel {
+	case "", models.CodeChannelSMS, models.CodeChannelEmail:
+	default:
+		return errors.New1Msg("unknown code channel: "+channel, http.StatusBadRequest)
+	}
+	if err := s.checkResetLimits(ctx, phone, client.IP); err != nil {
+		return err.Trace()
+	}
+
+	user, err := s.repo.FindByPhone(ctx, phone)
+	if err != nil {
+		if err.Code == http.StatusNotFound {
+			return nil
+		}
+		return err.Trace()
+	}
+	if !user.Confirmed {
+		return nil
+	}
+	if channel == models.CodeChannelEmail {
+		if len(user.Email) == 0 || !user.EmailConfirmed {
+			return nil
+		}
+		if err := s.phoneConf.SendCodeByEmail(ctx, models.CodePurposeReset, user.Id, user.Phone, user.Email); err != nil {
+			return err.Trace()
+		}
+		return nil
+	}
+	if err := s.phoneConf.SendCode(ctx, models.CodePurposeReset, user.Id, user.Phone); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// VerifyPasswordReset checks reset code of phone and returns token which allows to set new password once
+func (s *AuthService) VerifyPasswordReset(ctx context.Context, phone, code string, client *models.ClientInfo) (*models.ResetToken, *errors.Error) {
+	phone, e := models.ParsePhone(phone)
+	if e != nil {
+		return nil, errors.New(e, "invalid phone number", http.StatusBadRequest)
+	}
+	if err := s.checkResetLimits(ctx, "", client.IP); err != nil {
+		return nil, err.Trace()
+	}
+
+	userId, err := s.phoneConf.CheckCode(ctx, models.CodePurposeReset, phone, code)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	user, err := s.repo.GetById(ctx, userId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if user.Phone != phone {
+		return nil, errors.New1Msg("invalid code", http.StatusUnauthorized)
+	}
+
+	token := uuid.NewString()
+	if err := s.cache.Set(ctx, resetTokenKey(token), user.Id, resetTokenTTL); err != nil {
+		return nil, err.Trace()
+	}
+	return &models.ResetToken{
+		Token:     token,
+		ExpiresAt: time.Now().Add(resetTokenTTL),
+	}, nil
+}
+
+// ResetPassword sets new password of user by reset token and signs user out of all devices
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string, client *models.ClientInfo) *errors.Error {
+	if len(token) == 0 {
+		return errors.New1Msg("missing reset token", http.StatusBadRequest)
+	}
+	if e := models.ValidatePassword(newPassword); e != nil {
+		return errors.New(e, e.Error(), http.StatusBadRequest)
+	}
+	userId, err := s.cache.Get(ctx, resetTokenKey(token))
+	if err != nil {
+		return err.Trace()
+	}
+	if userId == 0 {
+		return errors.New1Msg("invalid reset token", http.StatusUnauthorized)
+	}
+	// token is consumed atomically, so concurrent requests can't both use it
+	consumed, err := s.cache.CompareAndSet(ctx, resetTokenKey(token), userId, 0, resetTokenTTL)
+	if err != nil {
+		return err.Trace()
+	}
+	if !consumed {
+		return errors.New1Msg("invalid reset token", http.StatusUnauthorized)
+	}
+	if err := s.cache.Del(ctx, resetTokenKey(token)); err != nil {
+		return err.Trace()
+	}
+
+	hash, e := password.Hash(newPassword)
+	if e != nil {
+		return errors.New(e, "hash password error", http.StatusInternalServerError)
+	}
+	if err := s.repo.UpdatePassword(ctx, userId, hash); err != nil {
+		return err.Trace()
+	}
+	if err := s.RevokeUserTokens(ctx, userId); err != nil {
+		return err.Trace()
+	}
+
+	client.Truncate()
+	if err := s.sessionsRepo.NewSecurityEvent(ctx, &models.SecurityEvent{
+		UserId:    userId,
+		Type:      models.SecurityEventPasswordReset,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
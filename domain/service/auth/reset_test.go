@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"messanger/pkg/password"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPasswordReset(t *testing.T) {
+	auth, sessionsRepo, _, userRepo := newTestAuthServiceWithUsers(t)
+	userRepo.On("FindByPhone", mock.Anything, testUser.Phone).Return(testUser, nil)
+	userRepo.On("UpdatePassword", mock.Anything, testUser.Id, mock.MatchedBy(func(hash string) bool {
+		ok, _ := password.Verify("new password 2", hash)
+		return ok
+	})).Return(nil).Once()
+	sessionsRepo.On("NewSecurityEvent", mock.Anything, mock.MatchedBy(func(event *models.SecurityEvent) bool {
+		return event.UserId == testUser.Id && event.Type == models.SecurityEventPasswordReset
+	})).Return(nil).Once()
+
+	tokens := login(t, auth)
+	ctx := context.Background()
+
+	if err := auth.RequestPasswordReset(ctx, testUser.Phone, "", testClient); err != nil {
+		t.Fatal(err)
+	}
+	code := smsChan.Code()
+
+	reset, err := auth.VerifyPasswordReset(ctx, testUser.Phone, code, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.ResetPassword(ctx, reset.Token, "weak", testClient); err == nil {
+		t.Fatal("weak password should be rejected")
+	}
+	if err := auth.ResetPassword(ctx, reset.Token, "new password 2", testClient); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.ResetPassword(ctx, reset.Token, "new password 2", testClient); err == nil {
+		t.Fatal("reset token should be used once")
+	}
+
+	if _, _, err := auth.DecodeAccessToken(ctx, tokens.AccessToken); err == nil {
+		t.Fatal("access token issued before reset should be rejected")
+	}
+}
+
+func TestPasswordResetLimit(t *testing.T) {
+	auth, _, _, userRepo := newTestAuthServiceWithUsers(t)
+	const phone = "+79161234569"
+	userRepo.On("FindByPhone", mock.Anything, phone).Return(nil, errors.New1Msg("user not found", http.StatusNotFound))
+
+	for range resetPhoneBurst {
+		if err := auth.RequestPasswordReset(context.Background(), phone, "", testClient); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := auth.RequestPasswordReset(context.Background(), phone, "", testClient)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusTooManyRequests, err.Code)
+	require.Greater(t, err.RetryAfter, time.Duration(0))
+}
+
+func TestPasswordResetByEmail(t *testing.T) {
+	auth, _, _, userRepo := newTestAuthServiceWithUsers(t)
+	emailUser := &models.User{
+		Id:             4,
+		Phone:          "+79161234571",
+		Confirmed:      true,
+		Email:          "reset@example.com",
+		EmailConfirmed: true,
+	}
+	userRepo.On("FindByPhone", mock.Anything, emailUser.Phone).Return(emailUser, nil)
+	userRepo.On("GetById", mock.Anything, emailUser.Id).Return(emailUser, nil)
+	ctx := context.Background()
+
+	if err := auth.RequestPasswordReset(ctx, emailUser.Phone, models.CodeChannelEmail, testClient); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.VerifyPasswordReset(ctx, emailUser.Phone, mailChan.Code(), testClient); err != nil {
+		t.Fatal(err)
+	}
+
+	err := auth.RequestPasswordReset(ctx, emailUser.Phone, "pigeon", testClient)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code)
+}
+
+func TestConcurrentPasswordReset(t *testing.T) {
+	auth, sessionsRepo, _, userRepo := newTestAuthServiceWithUsers(t)
+	userRepo.On("UpdatePassword", mock.Anything, testUser.Id, mock.Anything).Return(nil).Once()
+	sessionsRepo.On("NewSecurityEvent", mock.Anything, mock.Anything).Return(nil).Once()
+
+	const token = "concurrent-reset-token"
+	require.Nil(t, c.Set(context.Background(), resetTokenKey(token), testUser.Id, time.Minute))
+
+	const requests = 10
+	var wg sync.WaitGroup
+	var reset atomic.Int32
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := auth.ResetPassword(context.Background(), token, "new password 3", testClient); err == nil {
+				reset.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), reset.Load(), "reset token should be used once")
+}
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func refreshTokenKey(token string) string {
+	return "refresh:" + token
+}
+
+// usedRefreshTokenKey keeps session of rotated refresh token, so its reuse can be detected
+func usedRefreshTokenKey(token string) string {
+	return "refresh_used:" + token
+}
+
+// revokedSessionKey marks revoked session until its last access token expires
+func revokedSessionKey(sessionId int) string {
+	return "revoked_session:" + strconv.Itoa(sessionId)
+}
+
+// tokenVersionKey keeps current token version of user, access tokens with other version are rejected.
+// Key lives refresh token TTL after the last token issue, so it outlives all tokens with its version
+func tokenVersionKey(userId int) string {
+	return "token_version:" + strconv.Itoa(userId)
+}
+
+func (s *AuthService) tokenVersion(ctx context.Context, userId int) (int, *errors.Error) {
+	version, err := s.cache.Get(ctx, tokenVersionKey(userId))
+	if err != nil {
+		return 0, err.Trace()
+	}
+	if version != 0 {
+		if err := s.cache.Set(ctx, tokenVersionKey(userId), version, s.refreshTokenTTL); err != nil {
+			return 0, err.Trace()
+		}
+	}
+	return version, nil
+}
+
+// checkRefreshReuse revokes session if refresh token was already rotated.
+// Refresh tokens of one session form a family, reuse of rotated token means that one of tokens
+// was stolen, and as it is unknown which client is legitimate the whole session is revoked
+func (s *AuthService) checkRefreshReuse(ctx context.Context, refresh string, client *models.ClientInfo) *errors.Error {
+	sessionId, err := s.cache.Get(ctx, usedRefreshTokenKey(refresh))
+	if err != nil {
+		return err.Trace()
+	}
+	if sessionId == 0 {
+		return nil
+	}
+	session, err := s.sessionsRepo.GetById(ctx, sessionId)
+	if err != nil {
+		if err.Code == http.StatusNotFound {
+			return errors.New1Msg("session revoked", http.StatusUnauthorized)
+		}
+		return err.Trace()
+	}
+
+	if err := s.revokeSession(ctx, session); err != nil {
+		return err.Trace()
+	}
+	client.Truncate()
+	if err := s.sessionsRepo.NewSecurityEvent(ctx, &models.SecurityEvent{
+		UserId:    session.UserId,
+		Type:      models.SecurityEventRefreshTokenReuse,
+		SessionId: session.Id,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}); err != nil {
+		return err.Trace()
+	}
+	return errors.New(fmt.Sprintf("refresh token of session (%d) reused", session.Id),
+		"refresh token reused, session revoked", http.StatusUnauthorized)
+}
+
+func (s *AuthService) newSession(ctx context.Context, userId int, client *models.ClientInfo) (*models.Session, *errors.Error) {
+	client.Truncate()
+	now := time.Now()
+	session := &models.Session{
+		UserId:       userId,
+		DeviceName:   client.DeviceName,
+		UserAgent:    client.UserAgent,
+		IP:           client.IP,
+		CreateTime:   now,
+		LastUsedTime: now,
+		ExpireTime:   now.Add(s.refreshTokenTTL),
+	}
+	if err := s.sessionsRepo.New(ctx, session); err != nil {
+		return nil, err.Trace()
+	}
+	return session, nil
+}
+
+// GetSessions returns active sessions of current user, current session is marked
+func (s *AuthService) GetSessions(ctx context.Context) ([]models.Session, *errors.Error) {
+	sessions, err := s.sessionsRepo.GetByUser(ctx, ExtractUser(ctx))
+	if err != nil {
+		return nil, err.Trace()
+	}
+	currentId := ExtractSession(ctx)
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id == currentId
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs out device of current user, access tokens of session are rejected immediately
+func (s *AuthService) RevokeSession(ctx context.Context, sessionId int) *errors.Error {
+	if sessionId == 0 {
+		return errors.New1Msg("missing session id", http.StatusBadRequest)
+	}
+	session, err := s.sessionsRepo.GetById(ctx, sessionId)
+	if err != nil {
+		return err.Trace()
+	}
+	if session.UserId != ExtractUser(ctx) {
+		return errors.New1Msg("session not found", http.StatusNotFound)
+	}
+	if err := s.revokeSession(ctx, session); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// RevokeOtherSessions signs out all devices of current user except current one
+func (s *AuthService) RevokeOtherSessions(ctx context.Context) *errors.Error {
+	sessions, err := s.sessionsRepo.GetByUser(ctx, ExtractUser(ctx))
+	if err != nil {
+		return err.Trace()
+	}
+	currentId := ExtractSession(ctx)
+	for _, session := range sessions {
+		if session.Id == currentId {
+			continue
+		}
+		if err := s.revokeSession(ctx, &session); err != nil {
+			return err.Trace()
+		}
+	}
+	return nil
+}
+
+// LogoutAll signs out all devices of current user including current one
+func (s *AuthService) LogoutAll(ctx context.Context) *errors.Error {
+	if err := s.RevokeUserTokens(ctx, ExtractUser(ctx)); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// RevokeUserTokens rejects all issued access tokens of user, deletes its sessions
+// and closes its live connections, user has to login again
+func (s *AuthService) RevokeUserTokens(ctx context.Context, userId int) *errors.Error {
+	version, err := s.cache.Get(ctx, tokenVersionKey(userId))
+	if err != nil {
+		return err.Trace()
+	}
+	if err := s.cache.Set(ctx, tokenVersionKey(userId), version+1, s.refreshTokenTTL); err != nil {
+		return err.Trace()
+	}
+
+	sessions, err := s.sessionsRepo.GetByUser(ctx, userId)
+	if err != nil {
+		return err.Trace()
+	}
+	for _, session := range sessions {
+		if err := s.sessionsRepo.Delete(ctx, session.Id); err != nil {
+			return err.Trace()
+		}
+	}
+
+	if s.disconnector != nil {
+		go s.disconnector.DisconnectUser(userId)
+	}
+	return nil
+}
+
+// revokeSession deletes session, rejects its access tokens and closes its live connections
+func (s *AuthService) revokeSession(ctx context.Context, session *models.Session) *errors.Error {
+	if err := s.sessionsRepo.Delete(ctx, session.Id); err != nil {
+		return err.Trace()
+	}
+	if err := s.cache.Set(ctx, revokedSessionKey(session.Id), 1, s.accessTokenTTL); err != nil {
+		return err.Trace()
+	}
+	if s.disconnector != nil {
+		go s.disconnector.DisconnectSession(session.UserId, session.Id)
+	}
+	return nil
+}
+
+const securityEventsCount = 50
+
+// GetSecurityEvents returns last security events of current user
+func (s *AuthService) GetSecurityEvents(ctx context.Context) ([]models.SecurityEvent, *errors.Error) {
+	events, err := s.sessionsRepo.GetSecurityEvents(ctx, ExtractUser(ctx), securityEventsCount)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return events, nil
+}
@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"messanger/domain/models"
+	"messanger/pkg/db"
+	"messanger/pkg/errors"
+	"messanger/pkg/totp"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	totpIssuer = "Messanger"
+	// totpLoginTTL is time to enter code after password was checked
+	totpLoginTTL       = 5 * time.Minute
+	totpMaxAttempts    = 5
+	recoveryCodesCount = 10
+	recoveryCodeBytes  = 5
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// totpLoginKey keeps user who passed first factor and chose authenticator app as second one
+func totpLoginKey(phone string) string {
+	return "totp_login:" + phone
+}
+
+func totpAttemptsKey(userId int) string {
+	return "totp_attempts:" + strconv.Itoa(userId)
+}
+
+// toTOTPConfirming waits for authenticator app code of user instead of SMS code
+func (s *AuthService) toTOTPConfirming(ctx context.Context, user *models.User) *errors.Error {
+	t, err := s.totpRepo.Get(ctx, user.Id)
+	if err != nil {
+		if err.Code == http.StatusNotFound {
+			return errors.New(err, "authenticator app is not enabled", http.StatusBadRequest)
+		}
+		return err.Trace()
+	}
+	if !t.Enabled {
+		return errors.New1Msg("authenticator app is not enabled", http.StatusBadRequest)
+	}
+	if err := s.cache.Set(ctx, totpLoginKey(user.Phone), user.Id, totpLoginTTL); err != nil {
+		return err.Trace()
+	}
+	if err := s.cache.Del(ctx, totpAttemptsKey(user.Id)); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// confirmTOTPLogin checks second factor of user waiting for authenticator app code,
+// login is canceled after too many invalid codes
+func (s *AuthService) confirmTOTPLogin(ctx context.Context, phone string, userId int, code string) *errors.Error {
+	attempts, err := s.cache.Get(ctx, totpAttemptsKey(userId))
+	if err != nil {
+		return err.Trace()
+	}
+	if err := s.checkSecondFactor(ctx, userId, code); err != nil {
+		if err.Code != http.StatusUnauthorized {
+			return err.Trace()
+		}
+		attempts++
+		if attempts >= totpMaxAttempts {
+			s.cache.Del(ctx, totpLoginKey(phone))
+			s.cache.Del(ctx, totpAttemptsKey(userId))
+			return errors.New(err, "too many invalid codes, login again", http.StatusUnauthorized)
+		}
+		if err2 := s.cache.Set(ctx, totpAttemptsKey(userId), attempts, totpLoginTTL); err2 != nil {
+			err.Msg += fmt.Sprintf("\n set attempts error: %v", err2.Trace())
+		}
+		return err.Trace()
+	}
+	s.cache.Del(ctx, totpLoginKey(phone))
+	s.cache.Del(ctx, totpAttemptsKey(userId))
+	return nil
+}
+
+// checkSecondFactor accepts authenticator app code or unused recovery code of user
+func (s *AuthService) checkSecondFactor(ctx context.Context, userId int, code string) *errors.Error {
+	t, err := s.totpRepo.Get(ctx, userId)
+	if err != nil {
+		if err.Code == http.StatusNotFound {
+			return errors.New(err, "authenticator app is not enabled", http.StatusUnauthorized)
+		}
+		return err.Trace()
+	}
+	if !t.Enabled {
+		return errors.New1Msg("authenticator app is not enabled", http.StatusUnauthorized)
+	}
+
+	if len(code) == totp.Digits {
+		step, ok := totp.Verify(t.Secret, code, time.Now(), t.LastStep)
+		if !ok {
+			return errors.New1Msg("invalid code", http.StatusUnauthorized)
+		}
+		if err := s.totpRepo.UseStep(ctx, userId, step); err != nil {
+			return err.Trace()
+		}
+		return nil
+	}
+
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if err := s.totpRepo.UseRecoveryCode(ctx, userId, models.HashRecoveryCode(code)); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// EnrollTOTP creates authenticator app secret of current user, it has to be confirmed with ConfirmTOTP
+func (s *AuthService) EnrollTOTP(ctx context.Context) (*models.TOTPEnrollment, *errors.Error) {
+	userId := ExtractUser(ctx)
+	user, err := s.repo.GetById(ctx, userId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	t, err := s.totpRepo.Get(ctx, userId)
+	if err != nil && err.Code != http.StatusNotFound {
+		return nil, err.Trace()
+	}
+	if t != nil && t.Enabled {
+		return nil, errors.New1Msg("authenticator app is already enabled", http.StatusConflict)
+	}
+
+	secret, e := totp.NewSecret()
+	if e != nil {
+		return nil, errors.New(e, "create secret error", http.StatusInternalServerError)
+	}
+	if err := s.totpRepo.Set(ctx, &models.TOTP{UserId: userId, Secret: secret}); err != nil {
+		return nil, err.Trace()
+	}
+	return &models.TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(totpIssuer, user.Phone, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables authenticator app after user entered its first code, returns recovery codes
+func (s *AuthService) ConfirmTOTP(ctx context.Context, code string) (codes *models.RecoveryCodes, err *errors.Error) {
+	userId := ExtractUser(ctx)
+	t, err := s.totpRepo.Get(ctx, userId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if t.Enabled {
+		return nil, errors.New1Msg("authenticator app is already enabled", http.StatusConflict)
+	}
+	step, ok := totp.Verify(t.Secret, code, time.Now(), t.LastStep)
+	if !ok {
+		return nil, errors.New1Msg("invalid code", http.StatusUnauthorized)
+	}
+
+	ctx, err = db.WithTx(ctx, s.totpRepo)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.totpRepo.UseStep(ctx, userId, step); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.totpRepo.Enable(ctx, userId); err != nil {
+		return nil, err.Trace()
+	}
+	codes, err = s.newRecoveryCodes(ctx, userId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return codes, nil
+}
+
+// DisableTOTP removes authenticator app of current user, code or recovery code is required
+func (s *AuthService) DisableTOTP(ctx context.Context, code string) *errors.Error {
+	userId := ExtractUser(ctx)
+	if err := s.checkSecondFactor(ctx, userId, code); err != nil {
+		return err.Trace()
+	}
+	if err := s.totpRepo.Delete(ctx, userId); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces recovery codes of current user, code or recovery code is required
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, code string) (*models.RecoveryCodes, *errors.Error) {
+	userId := ExtractUser(ctx)
+	if err := s.checkSecondFactor(ctx, userId, code); err != nil {
+		return nil, err.Trace()
+	}
+	codes, err := s.newRecoveryCodes(ctx, userId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return codes, nil
+}
+
+// newRecoveryCodes replaces recovery codes of user, codes are shown once and only hashes are stored
+func (s *AuthService) newRecoveryCodes(ctx context.Context, userId int) (*models.RecoveryCodes, *errors.Error) {
+	codes := &models.RecoveryCodes{Codes: make([]string, recoveryCodesCount)}
+	hashes := make([]string, recoveryCodesCount)
+	for i := range codes.Codes {
+		b := make([]byte, recoveryCodeBytes)
+		if _, e := rand.Read(b); e != nil {
+			return nil, errors.New(e, "create recovery codes error", http.StatusInternalServerError)
+		}
+		// 5 bytes are 8 base32 chars
+		code := recoveryCodeEncoding.EncodeToString(b)
+		codes.Codes[i] = strings.ToLower(code[:4] + "-" + code[4:])
+		hashes[i] = models.HashRecoveryCode(code)
+	}
+	if err := s.totpRepo.SetRecoveryCodes(ctx, userId, hashes); err != nil {
+		return nil, err.Trace()
+	}
+	return codes, nil
+}
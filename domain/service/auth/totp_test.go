@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/pkg/errors"
+	"messanger/pkg/totp"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+// mockTOTPRepo keeps secret and recovery codes of test user in memory
+func mockTOTPRepo(totpRepo *mocks.TOTPRepo) {
+	var stored *models.TOTP
+	var recoveryCodes []string
+
+	totpRepo.On("Set", mock.Anything, mock.Anything).Return(func(_ context.Context, t *models.TOTP) *errors.Error {
+		stored = &models.TOTP{UserId: t.UserId, Secret: t.Secret}
+		return nil
+	})
+	totpRepo.On("Get", mock.Anything, testUser.Id).Return(func(context.Context, int) (*models.TOTP, *errors.Error) {
+		if stored == nil {
+			return nil, errors.New1Msg("authenticator app is not enrolled", http.StatusNotFound)
+		}
+		t := *stored
+		return &t, nil
+	})
+	totpRepo.On("Enable", mock.Anything, testUser.Id).Return(func(context.Context, int) *errors.Error {
+		stored.Enabled = true
+		return nil
+	})
+	totpRepo.On("UseStep", mock.Anything, testUser.Id, mock.Anything).Return(func(_ context.Context, _ int, step int64) *errors.Error {
+		if step <= stored.LastStep {
+			return errors.New1Msg("code already used", http.StatusUnauthorized)
+		}
+		stored.LastStep = step
+		return nil
+	})
+	totpRepo.On("SetRecoveryCodes", mock.Anything, testUser.Id, mock.Anything).Return(func(_ context.Context, _ int, hashes []string) *errors.Error {
+		recoveryCodes = hashes
+		return nil
+	})
+	totpRepo.On("UseRecoveryCode", mock.Anything, testUser.Id, mock.Anything).Return(func(_ context.Context, _ int, hash string) *errors.Error {
+		i := slices.Index(recoveryCodes, hash)
+		if i == -1 {
+			return errors.New1Msg("invalid recovery code", http.StatusUnauthorized)
+		}
+		recoveryCodes = slices.Delete(recoveryCodes, i, i+1)
+		return nil
+	})
+}
+
+func TestTOTPLogin(t *testing.T) {
+	auth, _, totpRepo := newTestAuthService(t)
+	mockTOTPRepo(totpRepo)
+	ctx := CtxWithUser(context.Background(), testUser.Id)
+
+	if err := auth.Login1FA(context.Background(), testUser.Phone, testPassword, models.LoginMethodTOTP); err == nil {
+		t.Fatal("login with authenticator app should fail before enrollment")
+	}
+
+	enrollment, err := auth.EnrollTOTP(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	code, _ := totp.Code(enrollment.Secret, totp.Step(now))
+	codes, err := auth.ConfirmTOTP(ctx, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Len(t, codes.Codes, recoveryCodesCount)
+
+	loginTOTP := func(code string) *errors.Error {
+		if err := auth.Login1FA(context.Background(), testUser.Phone, testPassword, models.LoginMethodTOTP); err != nil {
+			t.Fatal(err)
+		}
+		_, err := auth.Login2FA(context.Background(), testUser.Phone, code, testClient)
+		return err
+	}
+
+	if err := loginTOTP(code); err == nil {
+		t.Fatal("used code should be rejected")
+	}
+	next, _ := totp.Code(enrollment.Secret, totp.Step(now.Add(totp.Period)))
+	if err := loginTOTP(next); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loginTOTP(codes.Codes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := loginTOTP(codes.Codes[0]); err == nil {
+		t.Fatal("used recovery code should be rejected")
+	}
+}
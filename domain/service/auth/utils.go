@@ -11,3 +11,15 @@ func CtxWithUser(ctx context.Context, userId int) context.Context {
 func ExtractUser(ctx context.Context) int {
 	return ctx.Value(UserIdKey{}).(int)
 }
+
+type SessionIdKey struct{}
+
+func CtxWithSession(ctx context.Context, sessionId int) context.Context {
+	return context.WithValue(ctx, SessionIdKey{}, sessionId)
+}
+
+// ExtractSession returns session of access token, 0 if context has no session
+func ExtractSession(ctx context.Context) int {
+	sessionId, _ := ctx.Value(SessionIdKey{}).(int)
+	return sessionId
+}
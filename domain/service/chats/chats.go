@@ -5,7 +5,9 @@ import (
 	"messanger/domain/models"
 	"messanger/domain/ports"
 	"messanger/domain/service/auth"
+	"messanger/pkg/db"
 	"messanger/pkg/errors"
+	"net/http"
 	"slices"
 	"time"
 )
@@ -25,6 +27,37 @@ func NewChatService(
 	}
 }
 
+// createSavedChat creates saved messages chat of user registered before it was added to registration,
+// returns updated chat list
+func (s *ChatService) createSavedChat(ctx context.Context, userId int) ([]models.Chat, *errors.Error) {
+	// chat could be created by concurrent request
+	if err := s.newSavedChat(ctx, userId); err != nil && err.Code != http.StatusConflict {
+		return nil, err.Trace()
+	}
+	chats, err := s.chatsRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return chats, nil
+}
+
+func (s *ChatService) newSavedChat(ctx context.Context, userId int) (err *errors.Error) {
+	ctx, err = db.WithTx(ctx, s.chatsRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	chat := new(models.Chat)
+	if err := s.chatsRepo.NewSaved(ctx, chat, userId); err != nil {
+		return err.Trace()
+	}
+	if err := s.chatsRepo.AddUserToChat(ctx, chat.Id, userId); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
 type ChatResponseUser struct {
 	UserId int `json:"user_id"`
 }
@@ -35,9 +68,22 @@ type UserRoleResponse struct {
 }
 
 type ChatResponseGroup struct {
-	GroupId int                `json:"group_id"`
-	Name    string             `json:"name"`
-	Users   []UserRoleResponse `json:"users"`
+	GroupId     int                `json:"group_id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Avatar      string             `json:"avatar"`
+	Users       []UserRoleResponse `json:"users"`
+}
+
+// ChatResponseChannel contains subscribers count instead of member list because channels can be large
+type ChatResponseChannel struct {
+	ChannelId   int    `json:"channel_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Avatar      string `json:"avatar"`
+	Username    string `json:"username,omitempty"`
+	Role        string `json:"role"`
+	Subscribers int    `json:"subscribers"`
 }
 
 type ChatResponse struct {
@@ -55,6 +101,11 @@ func (s *ChatService) GetAllUserChats(ctx context.Context) ([]*ChatResponse, *er
 	if err != nil {
 		return nil, err.Trace()
 	}
+	if !slices.ContainsFunc(chats, func(c models.Chat) bool { return c.Type == models.ChatTypeSaved }) {
+		if chats, err = s.createSavedChat(ctx, userId); err != nil {
+			return nil, err.Trace()
+		}
+	}
 
 	resp := make([]*ChatResponse, 0, len(chats))
 	for _, chat := range chats {
@@ -102,9 +153,38 @@ func (s *ChatService) GetAllUserChats(ctx context.Context) ([]*ChatResponse, *er
 			}
 
 			chatResp.ChatInfo = ChatResponseGroup{
-				GroupId: group.Id,
-				Name:    group.Name,
-				Users:   usersRole,
+				GroupId:     group.Id,
+				Name:        group.Name,
+				Description: group.Description,
+				Avatar:      group.Avatar,
+				Users:       usersRole,
+			}
+
+		case models.ChatTypeChannel:
+			channel, err := s.groupsRepo.GetGroupByChatId(ctx, chat.Id)
+			if err != nil {
+				return nil, err.Trace()
+			}
+			count, err := s.chatsRepo.CountUsersInChat(ctx, chat.Id)
+			if err != nil {
+				return nil, err.Trace()
+			}
+			role, err := s.groupsRepo.GetRole(ctx, userId, channel.Id)
+			if err != nil {
+				if err.Code != http.StatusNotFound {
+					return nil, err.Trace()
+				}
+				role = models.RoleMember
+			}
+
+			chatResp.ChatInfo = ChatResponseChannel{
+				ChannelId:   channel.Id,
+				Name:        channel.Name,
+				Description: channel.Description,
+				Avatar:      channel.Avatar,
+				Username:    channel.Username,
+				Role:        role,
+				Subscribers: count,
 			}
 		}
 
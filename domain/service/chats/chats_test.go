@@ -0,0 +1,63 @@
+package chats
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+	"testing"
+)
+
+const (
+	testUserId  = 1
+	savedChatId = 10
+)
+
+func TestCreateSavedChat(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), testUserId)
+
+	chatsRepo := mocks.NewChatsRepo(t)
+	chatsRepo.On("GetByUserId", mock.Anything, testUserId).Return([]models.Chat{}, nil).Once()
+	chatsRepo.On("NewSaved", mock.Anything, mock.Anything, testUserId).Return(func(_ context.Context, chat *models.Chat, _ int) *errors.Error {
+		chat.Id = savedChatId
+		chat.Type = models.ChatTypeSaved
+		return nil
+	}).Once()
+	chatsRepo.On("AddUserToChat", mock.Anything, savedChatId, testUserId).Return(nil).Once()
+	chatsRepo.On("GetByUserId", mock.Anything, testUserId).Return([]models.Chat{{Id: savedChatId, Type: models.ChatTypeSaved}}, nil)
+
+	s := NewChatService(chatsRepo, mocks.NewGroupsRepo(t))
+
+	chats, err := s.GetAllUserChats(ctx)
+	require.Nil(t, err)
+	require.Len(t, chats, 1)
+	require.Equal(t, savedChatId, chats[0].ChatId)
+	require.Equal(t, models.ChatTypeSaved, chats[0].Type)
+
+	// saved chat is not created again
+	chats, err = s.GetAllUserChats(ctx)
+	require.Nil(t, err)
+	require.Len(t, chats, 1)
+}
+
+func TestCreateSavedChatConflict(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), testUserId)
+
+	// chat is created by request to another instance
+	chatsRepo := mocks.NewChatsRepo(t)
+	chatsRepo.On("GetByUserId", mock.Anything, testUserId).Return([]models.Chat{}, nil).Once()
+	chatsRepo.On("NewSaved", mock.Anything, mock.Anything, testUserId).
+		Return(errors.New1Msg("saved messages chat already exists", http.StatusConflict)).Once()
+	chatsRepo.On("GetByUserId", mock.Anything, testUserId).Return([]models.Chat{{Id: savedChatId, Type: models.ChatTypeSaved}}, nil).Once()
+
+	s := NewChatService(chatsRepo, mocks.NewGroupsRepo(t))
+
+	chats, err := s.GetAllUserChats(ctx)
+	require.Nil(t, err)
+	require.Len(t, chats, 1)
+	require.Equal(t, savedChatId, chats[0].ChatId)
+}
@@ -0,0 +1,296 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"messanger/config"
+	"messanger/domain/models"
+	"messanger/domain/ports"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	batchSize = 500
+
+	defaultLinkTTL = 24 * time.Hour
+	// staleTimeout is how long running job may have no progress before it is considered interrupted
+	staleTimeout = 10 * time.Minute
+)
+
+type ExportService struct {
+	exportsRepo  ports.ExportsRepo
+	messagesRepo ports.MessagesRepo
+	chatsRepo    ports.ChatsRepo
+	usersRepo    ports.UsersRepo
+	stickersRepo ports.StickersRepo
+
+	dir     string
+	linkTTL time.Duration
+}
+
+func NewExportService(
+	exportsRepo ports.ExportsRepo,
+	messagesRepo ports.MessagesRepo,
+	chatsRepo ports.ChatsRepo,
+	usersRepo ports.UsersRepo,
+	stickersRepo ports.StickersRepo,
+	cfg *config.ExportConfig,
+) *ExportService {
+	linkTTL := time.Duration(cfg.LinkTTLMin) * time.Minute
+	if linkTTL <= 0 {
+		linkTTL = defaultLinkTTL
+	}
+	s := &ExportService{
+		exportsRepo:  exportsRepo,
+		messagesRepo: messagesRepo,
+		chatsRepo:    chatsRepo,
+		usersRepo:    usersRepo,
+		stickersRepo: stickersRepo,
+		dir:          cfg.Dir,
+		linkTTL:      linkTTL,
+	}
+
+	go func() {
+		for {
+			time.Sleep(time.Minute)
+			s.removeExpired(context.Background())
+		}
+	}()
+
+	return s
+}
+
+func (s *ExportService) CreateExport(ctx context.Context, chatId int, format string) (*models.ExportJob, *errors.Error) {
+	if chatId == 0 {
+		return nil, errors.New1Msg("missing chat id", http.StatusBadRequest)
+	}
+	if !models.ValidateExportFormat(format) {
+		return nil, errors.New1Msg("invalid export format: "+format, http.StatusBadRequest)
+	}
+	userId := auth.ExtractUser(ctx)
+	ok, err := s.chatsRepo.CheckUserInChat(ctx, userId, chatId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("user (%d) tried to export the chat (%d)", userId, chatId),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+
+	total, err := s.messagesRepo.CountByChat(ctx, chatId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, errors.New(err, "create export error", http.StatusInternalServerError)
+	}
+
+	job := &models.ExportJob{
+		Id:         uuid.NewString(),
+		UserId:     userId,
+		ChatId:     chatId,
+		Format:     format,
+		Status:     models.ExportStatusRunning,
+		Total:      total,
+		CreateTime: time.Now(),
+	}
+	if err := s.exportsRepo.New(ctx, job); err != nil {
+		return nil, err.Trace()
+	}
+
+	j := *job
+	go s.run(&j)
+
+	return job, nil
+}
+
+func (s *ExportService) GetExport(ctx context.Context, id string) (*models.ExportJob, *errors.Error) {
+	if len(id) == 0 {
+		return nil, errors.New1Msg("missing export id", http.StatusBadRequest)
+	}
+	userId := auth.ExtractUser(ctx)
+
+	job, err := s.exportsRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if job.UserId != userId {
+		return nil, errors.New(fmt.Sprintf("user (%d) tried to get export (%s)", userId, id),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+	if len(job.Token) != 0 {
+		job.DownloadUrl = "/exports/download?token=" + job.Token
+	}
+	return job, nil
+}
+
+// GetExportFile returns path to the finished export file by download token
+func (s *ExportService) GetExportFile(ctx context.Context, token string) (string, *errors.Error) {
+	if len(token) == 0 {
+		return "", errors.New1Msg("missing token", http.StatusBadRequest)
+	}
+	job, err := s.exportsRepo.GetByToken(ctx, token)
+	if err != nil {
+		return "", err.Trace()
+	}
+	if job.ExpiresAt == nil || time.Now().After(*job.ExpiresAt) {
+		return "", errors.New1Msg("export not found or link expired", http.StatusNotFound)
+	}
+	return s.path(job), nil
+}
+
+func (s *ExportService) path(job *models.ExportJob) string {
+	return filepath.Join(s.dir, job.Id+"."+job.Format)
+}
+
+func (s *ExportService) run(job *models.ExportJob) {
+	ctx := context.Background()
+	err := s.write(ctx, job)
+
+	if err != nil {
+		job.Status = models.ExportStatusFailed
+		job.Error = err.UserMessage
+		os.Remove(s.path(job))
+	} else {
+		expires := time.Now().Add(s.linkTTL)
+		job.Token = uuid.NewString()
+		job.Status = models.ExportStatusDone
+		job.ExpiresAt = &expires
+	}
+
+	s.exportsRepo.Update(ctx, job)
+}
+
+func (s *ExportService) write(ctx context.Context, job *models.ExportJob) (err *errors.Error) {
+	file, e := os.Create(s.path(job))
+	if e != nil {
+		return errors.New(e, "create export file error", http.StatusInternalServerError)
+	}
+	defer func() {
+		if e := file.Close(); e != nil && err == nil {
+			err = errors.New(e, "write export file error", http.StatusInternalServerError)
+		}
+	}()
+
+	w := newWriter(job.Format, file)
+	if e := w.begin(job.ChatId); e != nil {
+		return errors.New(e, "write export file error", http.StatusInternalServerError)
+	}
+
+	names := make(map[int]string)
+	getName := func(userId int) (string, *errors.Error) {
+		if userId == 0 {
+			return "", nil
+		}
+		name, ok := names[userId]
+		if !ok {
+			user, err := s.usersRepo.GetById(ctx, userId)
+			if err != nil {
+				if err.Code != http.StatusNotFound {
+					return "", err.Trace()
+				}
+			} else {
+				name = user.Name
+			}
+			names[userId] = name
+		}
+		return name, nil
+	}
+	stickers := make(map[int]*models.Sticker)
+
+	var lastId int
+	for {
+		messages, err := s.messagesRepo.GetByChatAfter(ctx, job.ChatId, lastId, batchSize)
+		if err != nil {
+			return err.Trace()
+		}
+		if len(messages) == 0 {
+			break
+		}
+
+		for _, m := range messages {
+			name, err := getName(m.UserId)
+			if err != nil {
+				return err.Trace()
+			}
+			em := &exportMessage{
+				Id:       m.Id,
+				UserId:   m.UserId,
+				UserName: name,
+				Kind:     m.Kind,
+				Text:     m.Text,
+				Time:     m.Time,
+				Payload:  m.Payload,
+			}
+
+			switch {
+			case m.Kind == models.MessageKindSticker && m.StickerId != 0:
+				em.StickerId = m.StickerId
+				sticker, ok := stickers[em.StickerId]
+				if !ok {
+					sticker, err = s.stickersRepo.GetStickerById(ctx, em.StickerId)
+					if err != nil {
+						if err.Code != http.StatusNotFound {
+							return err.Trace()
+						}
+						sticker = nil
+					}
+					stickers[em.StickerId] = sticker
+				}
+				if sticker != nil {
+					em.StickerEmoji = sticker.Emoji
+					em.StickerUrl = "/files/" + sticker.Image
+				}
+			case m.Kind == models.MessageKindSystem && m.Payload != nil:
+				actor, err := getName(m.Payload.ActorId)
+				if err != nil {
+					return err.Trace()
+				}
+				target, err := getName(m.Payload.UserId)
+				if err != nil {
+					return err.Trace()
+				}
+				em.System = systemText(m.Payload, actor, target)
+			}
+
+			if e := w.message(em); e != nil {
+				return errors.New(e, "write export file error", http.StatusInternalServerError)
+			}
+		}
+		lastId = messages[len(messages)-1].Id
+
+		job.Processed += len(messages)
+		if job.Processed > job.Total {
+			job.Total = job.Processed
+		}
+		if err := s.exportsRepo.Update(ctx, job); err != nil {
+			return err.Trace()
+		}
+	}
+
+	if e := w.end(); e != nil {
+		return errors.New(e, "write export file error", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (s *ExportService) removeExpired(ctx context.Context) {
+	now := time.Now()
+	s.exportsRepo.FailStale(ctx, now.Add(-staleTimeout), "export interrupted")
+
+	jobs, err := s.exportsRepo.GetExpired(ctx, now, now.Add(-s.linkTTL))
+	if err != nil {
+		return
+	}
+	for i := range jobs {
+		os.Remove(s.path(&jobs[i]))
+		s.exportsRepo.Delete(ctx, jobs[i].Id)
+	}
+}
@@ -0,0 +1,326 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/config"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testUserId = 1
+	testChatId = 10
+)
+
+type mockExportsRepo struct {
+	jobs map[string]models.ExportJob
+	mu   sync.Mutex
+}
+
+func (m *mockExportsRepo) New(_ context.Context, job *models.ExportJob) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job.UpdateTime = job.CreateTime
+	m.jobs[job.Id] = *job
+	return nil
+}
+
+func (m *mockExportsRepo) GetById(_ context.Context, id string) (*models.ExportJob, *errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, errors.New1Msg("export not found", http.StatusNotFound)
+	}
+	return &job, nil
+}
+
+func (m *mockExportsRepo) GetByToken(_ context.Context, token string) (*models.ExportJob, *errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		if len(token) != 0 && job.Token == token {
+			return &job, nil
+		}
+	}
+	return nil, errors.New1Msg("export not found or link expired", http.StatusNotFound)
+}
+
+func (m *mockExportsRepo) Update(_ context.Context, job *models.ExportJob) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job.UpdateTime = time.Now()
+	m.jobs[job.Id] = *job
+	return nil
+}
+
+func (m *mockExportsRepo) FailStale(_ context.Context, before time.Time, reason string) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, job := range m.jobs {
+		if job.Status == models.ExportStatusRunning && job.UpdateTime.Before(before) {
+			job.Status = models.ExportStatusFailed
+			job.Error = reason
+			job.UpdateTime = time.Now()
+			m.jobs[id] = job
+		}
+	}
+	return nil
+}
+
+func (m *mockExportsRepo) GetExpired(_ context.Context, now, failedBefore time.Time) ([]models.ExportJob, *errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]models.ExportJob, 0)
+	for _, job := range m.jobs {
+		if (job.ExpiresAt != nil && job.ExpiresAt.Before(now)) ||
+			(job.Status == models.ExportStatusFailed && job.UpdateTime.Before(failedBefore)) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (m *mockExportsRepo) Delete(_ context.Context, id string) *errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.jobs, id)
+	return nil
+}
+
+func newTestExportService(t *testing.T, linkTTLMin int) (*ExportService, *mockExportsRepo) {
+	now := time.Now()
+	messages := []models.Message{
+		{Id: 1, ChatId: testChatId, UserId: testUserId, Text: "hello <b>", Time: now, Kind: models.MessageKindText},
+		{Id: 2, ChatId: testChatId, UserId: 2, Time: now, Kind: models.MessageKindSticker, StickerId: 7},
+		{Id: 3, ChatId: testChatId, UserId: testUserId, Time: now, Kind: models.MessageKindSystem, Payload: &models.SystemPayload{
+			Action:  models.SystemActionAdd,
+			ActorId: testUserId,
+			UserId:  2,
+		}},
+	}
+
+	messagesRepo := mocks.NewMessagesRepo(t)
+	messagesRepo.On("CountByChat", mock.Anything, testChatId).Return(len(messages), nil).Maybe()
+	messagesRepo.On("GetByChatAfter", mock.Anything, testChatId, mock.Anything, batchSize).Return(
+		func(_ context.Context, _ int, afterId int, _ int) ([]models.Message, *errors.Error) {
+			for i := range messages {
+				if messages[i].Id > afterId {
+					return messages[i:], nil
+				}
+			}
+			return nil, nil
+		}).Maybe()
+
+	chatsRepo := mocks.NewChatsRepo(t)
+	chatsRepo.On("CheckUserInChat", mock.Anything, mock.Anything, testChatId).Return(
+		func(_ context.Context, userId int, _ int) (bool, *errors.Error) {
+			return userId == testUserId, nil
+		})
+
+	usersRepo := mocks.NewUsersRepo(t)
+	usersRepo.On("GetById", mock.Anything, testUserId).Return(&models.User{Id: testUserId, Name: "alice"}, nil).Maybe()
+	usersRepo.On("GetById", mock.Anything, 2).Return(&models.User{Id: 2, Name: "bob"}, nil).Maybe()
+
+	stickersRepo := mocks.NewStickersRepo(t)
+	stickersRepo.On("GetStickerById", mock.Anything, 7).Return(&models.Sticker{Id: 7, Emoji: "🙂", Image: "sticker.webp"}, nil).Maybe()
+
+	exportsRepo := &mockExportsRepo{jobs: make(map[string]models.ExportJob)}
+	s := NewExportService(exportsRepo, messagesRepo, chatsRepo, usersRepo, stickersRepo, &config.ExportConfig{
+		Dir:        t.TempDir(),
+		LinkTTLMin: linkTTLMin,
+	})
+	return s, exportsRepo
+}
+
+func waitExport(t *testing.T, ctx context.Context, s *ExportService, id string) *models.ExportJob {
+	job := waitFinished(t, ctx, s, id)
+	require.Equal(t, models.ExportStatusDone, job.Status, job.Error)
+	return job
+}
+
+func waitFinished(t *testing.T, ctx context.Context, s *ExportService, id string) *models.ExportJob {
+	var job *models.ExportJob
+	require.Eventually(t, func() bool {
+		var err *errors.Error
+		job, err = s.GetExport(ctx, id)
+		require.Nil(t, err)
+		return job.Status != models.ExportStatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func readExport(t *testing.T, ctx context.Context, s *ExportService, job *models.ExportJob) []byte {
+	token := strings.TrimPrefix(job.DownloadUrl, "/exports/download?token=")
+	path, err := s.GetExportFile(ctx, token)
+	require.Nil(t, err)
+	data, e := os.ReadFile(path)
+	require.NoError(t, e)
+	return data
+}
+
+func TestExportJSON(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), testUserId)
+	s, _ := newTestExportService(t, 60)
+
+	job, err := s.CreateExport(ctx, testChatId, models.ExportFormatJSON)
+	require.Nil(t, err)
+	job = waitExport(t, ctx, s, job.Id)
+	require.Equal(t, 3, job.Processed)
+	require.NotEmpty(t, job.DownloadUrl)
+
+	var result struct {
+		ChatId   int             `json:"chat_id"`
+		Messages []exportMessage `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal(readExport(t, ctx, s, job), &result))
+	require.Equal(t, testChatId, result.ChatId)
+	require.Len(t, result.Messages, 3)
+
+	require.Equal(t, models.MessageKindText, result.Messages[0].Kind)
+	require.Equal(t, "alice", result.Messages[0].UserName)
+
+	require.Equal(t, models.MessageKindSticker, result.Messages[1].Kind)
+	require.Equal(t, 7, result.Messages[1].StickerId)
+	require.Equal(t, "/files/sticker.webp", result.Messages[1].StickerUrl)
+
+	require.Equal(t, models.MessageKindSystem, result.Messages[2].Kind)
+	require.NotNil(t, result.Messages[2].Payload)
+	require.Equal(t, models.SystemActionAdd, result.Messages[2].Payload.Action)
+	require.Equal(t, 2, result.Messages[2].Payload.UserId)
+}
+
+func TestExportHTML(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), testUserId)
+	s, _ := newTestExportService(t, 60)
+
+	job, err := s.CreateExport(ctx, testChatId, models.ExportFormatHTML)
+	require.Nil(t, err)
+	job = waitExport(t, ctx, s, job.Id)
+
+	html := string(readExport(t, ctx, s, job))
+	require.Contains(t, html, "hello &lt;b&gt;", "text should be escaped")
+	require.Contains(t, html, `src="/files/sticker.webp"`)
+	require.Contains(t, html, "alice added bob")
+}
+
+func TestExportAccess(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), testUserId)
+	otherCtx := auth.CtxWithUser(context.Background(), 2)
+	s, exportsRepo := newTestExportService(t, 0)
+	require.Equal(t, defaultLinkTTL, s.linkTTL, "zero link ttl should be replaced with default")
+
+	_, err := s.CreateExport(otherCtx, testChatId, models.ExportFormatJSON)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+
+	job, err := s.CreateExport(ctx, testChatId, models.ExportFormatJSON)
+	require.Nil(t, err)
+	job = waitExport(t, ctx, s, job.Id)
+	require.True(t, job.ExpiresAt.After(time.Now().Add(time.Hour)))
+
+	_, err = s.GetExport(otherCtx, job.Id)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+
+	// link expires
+	saved, _ := exportsRepo.GetById(ctx, job.Id)
+	expired := time.Now().Add(-time.Minute)
+	saved.ExpiresAt = &expired
+	exportsRepo.Update(ctx, saved)
+
+	_, err = s.GetExportFile(ctx, saved.Token)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusNotFound, err.Code)
+}
+
+func TestExportFailed(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), testUserId)
+
+	messagesRepo := mocks.NewMessagesRepo(t)
+	messagesRepo.On("CountByChat", mock.Anything, testChatId).Return(10, nil)
+	messagesRepo.On("GetByChatAfter", mock.Anything, testChatId, 0, batchSize).
+		Return(nil, errors.New1Msg(models.ErrDatabaseError, http.StatusInternalServerError))
+	chatsRepo := mocks.NewChatsRepo(t)
+	chatsRepo.On("CheckUserInChat", mock.Anything, testUserId, testChatId).Return(true, nil)
+
+	exportsRepo := &mockExportsRepo{jobs: make(map[string]models.ExportJob)}
+	s := NewExportService(exportsRepo, messagesRepo, chatsRepo, mocks.NewUsersRepo(t), mocks.NewStickersRepo(t),
+		&config.ExportConfig{Dir: t.TempDir()})
+
+	job, err := s.CreateExport(ctx, testChatId, models.ExportFormatJSON)
+	require.Nil(t, err)
+	require.Equal(t, models.ExportStatusRunning, job.Status)
+	require.Equal(t, 10, job.Total)
+
+	job = waitFinished(t, ctx, s, job.Id)
+	require.Equal(t, models.ExportStatusFailed, job.Status)
+	require.Equal(t, models.ErrDatabaseError, job.Error)
+	require.Empty(t, job.DownloadUrl)
+	require.NoFileExists(t, s.path(job), "file of failed export should be removed")
+}
+
+func TestExportCleanup(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), testUserId)
+	s, exportsRepo := newTestExportService(t, 60)
+
+	job, err := s.CreateExport(ctx, testChatId, models.ExportFormatJSON)
+	require.Nil(t, err)
+	job = waitExport(t, ctx, s, job.Id)
+	require.FileExists(t, s.path(job))
+
+	// job of stopped instance has no progress
+	stale := &models.ExportJob{
+		Id:         "stale",
+		UserId:     testUserId,
+		ChatId:     testChatId,
+		Format:     models.ExportFormatJSON,
+		Status:     models.ExportStatusRunning,
+		CreateTime: time.Now().Add(-time.Hour),
+	}
+	require.Nil(t, exportsRepo.New(ctx, stale))
+
+	s.removeExpired(ctx)
+
+	stale, err = s.GetExport(ctx, stale.Id)
+	require.Nil(t, err)
+	require.Equal(t, models.ExportStatusFailed, stale.Status)
+	require.Equal(t, "export interrupted", stale.Error)
+
+	_, err = s.GetExport(ctx, job.Id)
+	require.Nil(t, err, "link is not expired yet")
+	require.FileExists(t, s.path(job))
+
+	// link expires
+	saved, _ := exportsRepo.GetById(ctx, job.Id)
+	expired := time.Now().Add(-time.Minute)
+	saved.ExpiresAt = &expired
+	exportsRepo.Update(ctx, saved)
+
+	s.removeExpired(ctx)
+
+	_, err = s.GetExport(ctx, job.Id)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusNotFound, err.Code)
+	require.NoFileExists(t, s.path(job))
+
+	// failed job is kept for link ttl so user can see the error
+	_, err = s.GetExport(ctx, stale.Id)
+	require.Nil(t, err)
+	s.linkTTL = 0
+	s.removeExpired(ctx)
+	_, err = s.GetExport(ctx, stale.Id)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusNotFound, err.Code)
+}
@@ -0,0 +1,148 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"messanger/domain/models"
+	"time"
+)
+
+type exportMessage struct {
+	Id           int                   `json:"id"`
+	UserId       int                   `json:"user_id"`
+	UserName     string                `json:"user_name"`
+	Kind         string                `json:"kind"`
+	Text         string                `json:"text"`
+	Time         time.Time             `json:"time"`
+	StickerId    int                   `json:"sticker_id,omitempty"`
+	StickerEmoji string                `json:"sticker_emoji,omitempty"`
+	StickerUrl   string                `json:"sticker_url,omitempty"`
+	Payload      *models.SystemPayload `json:"payload,omitempty"`
+	// System is readable text of system message, only for html
+	System string `json:"-"`
+}
+
+// systemText renders system message as e.g. "Alice added Bob"
+func systemText(p *models.SystemPayload, actor string, target string) string {
+	if len(actor) == 0 {
+		actor = fmt.Sprintf("user %d", p.ActorId)
+	}
+	if len(target) == 0 {
+		target = fmt.Sprintf("user %d", p.UserId)
+	}
+	switch p.Action {
+	case models.SystemActionAdd:
+		return actor + " added " + target
+	case models.SystemActionJoin:
+		return actor + " joined"
+	case models.SystemActionLeave:
+		return actor + " left"
+	case models.SystemActionKick:
+		return actor + " removed " + target
+	case models.SystemActionBan:
+		return actor + " banned " + target
+	case models.SystemActionRoleChange:
+		return actor + " changed role of " + target + " to " + p.Role
+	case models.SystemActionRename:
+		return actor + " renamed the group to " + p.Name
+	}
+	return actor + ": " + p.Action
+}
+
+type writer interface {
+	begin(chatId int) error
+	message(m *exportMessage) error
+	end() error
+}
+
+func newWriter(format string, w io.Writer) writer {
+	if format == models.ExportFormatHTML {
+		return &htmlWriter{w: w}
+	}
+	return &jsonWriter{w: w}
+}
+
+// jsonWriter writes {"chat_id": ..., "messages": [...]} without holding all messages in memory
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonWriter) begin(chatId int) error {
+	chat, err := json.Marshal(chatId)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(j.w, `{"chat_id":`+string(chat)+`,"messages":[`)
+	return err
+}
+
+func (j *jsonWriter) message(m *exportMessage) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if j.count > 0 {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.count++
+	_, err = j.w.Write(b)
+	return err
+}
+
+func (j *jsonWriter) end() error {
+	_, err := io.WriteString(j.w, "]}\n")
+	return err
+}
+
+var htmlTemplates = template.Must(template.New("export").Parse(`
+{{define "begin"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Chat {{.}}</title>
+<style>
+body { font-family: sans-serif; max-width: 800px; margin: 0 auto; }
+.message { padding: 6px 0; border-bottom: 1px solid #eee; }
+.name { font-weight: bold; }
+.time { color: #888; font-size: 0.85em; margin-left: 8px; }
+.text { white-space: pre-wrap; }
+.system { color: #888; font-style: italic; text-align: center; }
+</style>
+</head>
+<body>
+<h1>Chat {{.}}</h1>
+{{end}}
+{{define "message"}}{{if eq .Kind "system"}}<div class="message system" id="message-{{.Id}}">
+{{if .System}}{{.System}}{{else}}{{.Text}}{{end}}<span class="time">{{.Time.Format "2006-01-02 15:04:05"}}</span>
+</div>
+{{else}}<div class="message" id="message-{{.Id}}">
+<span class="name">{{if .UserName}}{{.UserName}}{{else}}user {{.UserId}}{{end}}</span><span class="time">{{.Time.Format "2006-01-02 15:04:05"}}</span>
+{{if eq .Kind "sticker"}}<div class="text">{{if .StickerUrl}}<img src="{{.StickerUrl}}" alt="{{.StickerEmoji}}" width="128">{{else}}[sticker {{.StickerId}}]{{end}}</div>
+{{else}}<div class="text">{{.Text}}</div>
+{{end}}</div>
+{{end}}{{end}}
+{{define "end"}}</body>
+</html>
+{{end}}
+`))
+
+type htmlWriter struct {
+	w io.Writer
+}
+
+func (h *htmlWriter) begin(chatId int) error {
+	return htmlTemplates.ExecuteTemplate(h.w, "begin", chatId)
+}
+
+func (h *htmlWriter) message(m *exportMessage) error {
+	return htmlTemplates.ExecuteTemplate(h.w, "message", m)
+}
+
+func (h *htmlWriter) end() error {
+	return htmlTemplates.ExecuteTemplate(h.w, "end", nil)
+}
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"testing"
+	"time"
+)
+
+func TestJSONWriter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, messages := range [][]*exportMessage{
+		{},
+		{{Id: 1, UserId: 1, UserName: "alice", Kind: models.MessageKindText, Text: `"quoted"`, Time: now}},
+		{
+			{Id: 1, UserId: 1, UserName: "alice", Kind: models.MessageKindText, Text: "hi", Time: now},
+			{Id: 2, UserId: 2, Kind: models.MessageKindSticker, StickerId: 7, StickerEmoji: "🙂", StickerUrl: "/files/s.webp", Time: now},
+			{Id: 3, UserId: 1, Kind: models.MessageKindSystem, Time: now, System: "alice joined",
+				Payload: &models.SystemPayload{Action: models.SystemActionJoin, ActorId: 1}},
+		},
+	} {
+		var buf bytes.Buffer
+		w := newWriter(models.ExportFormatJSON, &buf)
+		require.NoError(t, w.begin(testChatId))
+		for _, m := range messages {
+			require.NoError(t, w.message(m))
+		}
+		require.NoError(t, w.end())
+
+		var result struct {
+			ChatId   int             `json:"chat_id"`
+			Messages []exportMessage `json:"messages"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &result), buf.String())
+		require.Equal(t, testChatId, result.ChatId)
+		require.Len(t, result.Messages, len(messages))
+		for i, m := range messages {
+			expected := *m
+			expected.System = ""
+			require.Equal(t, expected, result.Messages[i])
+		}
+		require.NotContains(t, buf.String(), "alice joined", "system text is only for html")
+	}
+}
+
+func TestHTMLWriter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w := newWriter(models.ExportFormatHTML, &buf)
+	require.NoError(t, w.begin(testChatId))
+	for _, m := range []*exportMessage{
+		{Id: 1, UserId: 1, UserName: "<alice>", Kind: models.MessageKindText, Text: "<script>", Time: now},
+		{Id: 2, UserId: 2, Kind: models.MessageKindText, Text: "hi", Time: now},
+		{Id: 3, UserId: 2, Kind: models.MessageKindSticker, StickerId: 7, StickerEmoji: "🙂", StickerUrl: "/files/s.webp", Time: now},
+		{Id: 4, UserId: 2, Kind: models.MessageKindSticker, StickerId: 8, Time: now},
+		{Id: 5, UserId: 1, Kind: models.MessageKindSystem, System: "alice joined", Time: now},
+	} {
+		require.NoError(t, w.message(m))
+	}
+	require.NoError(t, w.end())
+
+	html := buf.String()
+	require.Contains(t, html, "<title>Chat 10</title>")
+	require.Contains(t, html, "&lt;alice&gt;")
+	require.Contains(t, html, "&lt;script&gt;")
+	require.NotContains(t, html, "<script>")
+	require.Contains(t, html, `<span class="name">user 2</span>`, "name falls back to user id")
+	require.Contains(t, html, "2024-05-01 12:00:00")
+	require.Contains(t, html, `<img src="/files/s.webp" alt="🙂" width="128">`)
+	require.Contains(t, html, "[sticker 8]", "deleted sticker is shown by id")
+	require.Contains(t, html, `<div class="message system" id="message-5">`)
+	require.Contains(t, html, "alice joined")
+	require.True(t, bytes.HasSuffix(bytes.TrimSpace(buf.Bytes()), []byte("</html>")))
+}
+
+func TestSystemText(t *testing.T) {
+	for _, test := range []struct {
+		payload  models.SystemPayload
+		actor    string
+		target   string
+		expected string
+	}{
+		{models.SystemPayload{Action: models.SystemActionAdd}, "alice", "bob", "alice added bob"},
+		{models.SystemPayload{Action: models.SystemActionJoin}, "alice", "", "alice joined"},
+		{models.SystemPayload{Action: models.SystemActionLeave}, "alice", "", "alice left"},
+		{models.SystemPayload{Action: models.SystemActionKick}, "alice", "bob", "alice removed bob"},
+		{models.SystemPayload{Action: models.SystemActionBan}, "alice", "bob", "alice banned bob"},
+		{models.SystemPayload{Action: models.SystemActionRoleChange, Role: "admin"}, "alice", "bob", "alice changed role of bob to admin"},
+		{models.SystemPayload{Action: models.SystemActionRename, Name: "team"}, "alice", "", "alice renamed the group to team"},
+		{models.SystemPayload{Action: models.SystemActionAdd, ActorId: 1, UserId: 2}, "", "", "user 1 added user 2"},
+	} {
+		require.Equal(t, test.expected, systemText(&test.payload, test.actor, test.target))
+	}
+}
@@ -0,0 +1,98 @@
+package groups
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"messanger/domain/models"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+)
+
+const (
+	defaultAuditCount = 50
+	maxAuditCount     = 100
+)
+
+// auditGroupInfo is group info recorded on group update and delete
+type auditGroupInfo struct {
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	OnlyAdminsPost   bool   `json:"only_admins_post"`
+	MembersCanInvite bool   `json:"members_can_invite"`
+}
+
+func newAuditGroupInfo(group *models.Group) *auditGroupInfo {
+	return &auditGroupInfo{
+		Name:             group.Name,
+		Description:      group.Description,
+		OnlyAdminsPost:   group.OnlyAdminsPost,
+		MembersCanInvite: group.MembersCanInvite,
+	}
+}
+
+// audit records administrative action in group, targetId is user the action applied to or 0,
+// before and after are stored as JSON, nil values are not stored
+func (s *GroupService) audit(ctx context.Context, groupId int, actorId int, action string, targetId int, before, after any) *errors.Error {
+	entry := &models.AuditEntry{
+		GroupId:  groupId,
+		ActorId:  actorId,
+		Action:   action,
+		TargetId: targetId,
+	}
+	var e error
+	if before != nil {
+		if entry.Before, e = json.Marshal(before); e != nil {
+			return errors.New(e, "encode audit entry error", http.StatusInternalServerError)
+		}
+	}
+	if after != nil {
+		if entry.After, e = json.Marshal(after); e != nil {
+			return errors.New(e, "encode audit entry error", http.StatusInternalServerError)
+		}
+	}
+	if err := s.auditRepo.New(ctx, entry); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// GetAuditLog returns page of group audit log from newest entries, available to owner and admins
+func (s *GroupService) GetAuditLog(ctx context.Context, dto *AuditLogDTO) ([]models.AuditEntry, *errors.Error) {
+	if dto.GroupId == 0 {
+		return nil, errors.New1Msg("groupId is missing", http.StatusBadRequest)
+	}
+	if len(dto.Action) != 0 && !models.ValidateAuditAction(dto.Action) {
+		return nil, errors.New1Msg("invalid action: "+dto.Action, http.StatusBadRequest)
+	}
+	if dto.Count < 0 || dto.Count > maxAuditCount {
+		return nil, errors.New1Msg(fmt.Sprintf("count must be from 1 to %d", maxAuditCount), http.StatusBadRequest)
+	}
+	if dto.Count == 0 {
+		dto.Count = defaultAuditCount
+	}
+
+	actionerId := auth.ExtractUser(ctx)
+	role, err := s.perms.GetRole(ctx, actionerId, dto.GroupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if role != models.RoleOwner && role != models.RoleAdmin {
+		return nil, errors.New(fmt.Sprintf("user (%d) tried get audit log of group (%d)", actionerId, dto.GroupId),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+
+	entries, err := s.auditRepo.GetByGroup(ctx, &models.AuditFilter{
+		GroupId:  dto.GroupId,
+		ActorId:  dto.ActorId,
+		TargetId: dto.TargetId,
+		Action:   dto.Action,
+		LastId:   dto.LastId,
+		Count:    dto.Count,
+	})
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return entries, nil
+}
@@ -0,0 +1,87 @@
+package groups
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"messanger/domain/models"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+)
+
+// defaultMaxImageSize is used if size is not set, zero would reject every avatar
+const defaultMaxImageSize = 512 * 1024
+
+func (s *GroupService) SetAvatar(ctx context.Context, groupId int, image io.Reader) (*models.Group, *errors.Error) {
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermEditInfo, "set avatar"); err != nil {
+		return nil, err.Trace()
+	}
+	group, err := s.groupsRepo.GetById(ctx, groupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+
+	data, e := io.ReadAll(io.LimitReader(image, s.maxImageSize+1))
+	if e != nil {
+		return nil, errors.New(e, "read image error", http.StatusBadRequest)
+	}
+	if int64(len(data)) > s.maxImageSize {
+		return nil, errors.New1Msg(fmt.Sprintf("image is too large, max size is %d KB", s.maxImageSize/1024), http.StatusBadRequest)
+	}
+	ext, ok := models.ImageExt(data)
+	if !ok {
+		return nil, errors.New1Msg("unsupported image format", http.StatusBadRequest)
+	}
+
+	name, err := s.storage.Save(ctx, ext, bytes.NewReader(data))
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.groupsRepo.SetAvatar(ctx, group.Id, name); err != nil {
+		s.storage.Delete(ctx, name)
+		return nil, err.Trace()
+	}
+
+	old := group.Avatar
+	group.Avatar = name
+	if err := s.audit(ctx, group.Id, actionerId, models.AuditActionAvatar, 0, map[string]string{"avatar": old}, map[string]string{"avatar": name}); err != nil {
+		return nil, err.Trace()
+	}
+	if len(old) != 0 {
+		if err := s.storage.Delete(ctx, old); err != nil {
+			return nil, err.Trace()
+		}
+	}
+	s.onGroupUpdated(group)
+	return group, nil
+}
+
+func (s *GroupService) DeleteAvatar(ctx context.Context, groupId int) *errors.Error {
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermEditInfo, "delete avatar"); err != nil {
+		return err.Trace()
+	}
+	group, err := s.groupsRepo.GetById(ctx, groupId)
+	if err != nil {
+		return err.Trace()
+	}
+	if len(group.Avatar) == 0 {
+		return nil
+	}
+
+	if err := s.groupsRepo.SetAvatar(ctx, group.Id, ""); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, group.Id, actionerId, models.AuditActionAvatar, 0, map[string]string{"avatar": group.Avatar}, map[string]string{"avatar": ""}); err != nil {
+		return err.Trace()
+	}
+	if err := s.storage.Delete(ctx, group.Avatar); err != nil {
+		return err.Trace()
+	}
+	group.Avatar = ""
+	s.onGroupUpdated(group)
+	return nil
+}
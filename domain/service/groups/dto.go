@@ -1,10 +1,70 @@
 package groups
 
+import "messanger/domain/models"
+
+// UpdateGroupDTO contains group fields to update, empty name and nil fields are not changed
 type UpdateGroupDTO struct {
-	Name string `json:"name"`
+	Name             string  `json:"name"`
+	Description      *string `json:"description"`
+	OnlyAdminsPost   *bool   `json:"only_admins_post"`
+	MembersCanInvite *bool   `json:"members_can_invite"`
 }
 
 type GetUsersDTO struct {
 	UserId int    `json:"user_id"`
 	Role   string `json:"role"`
 }
+
+type CreateInviteDTO struct {
+	GroupId      int  `json:"group_id"`
+	ExpireSec    int  `json:"expire_sec"`
+	MaxUses      int  `json:"max_uses"`
+	NeedApproval bool `json:"need_approval"`
+}
+
+const (
+	JoinStatusJoined  = "joined"
+	JoinStatusPending = "pending"
+)
+
+type JoinResponseDTO struct {
+	GroupId int    `json:"group_id"`
+	Status  string `json:"status"`
+}
+
+type RoleDTO struct {
+	GroupId     int                `json:"group_id"`
+	Name        string             `json:"name"`
+	Permissions models.Permissions `json:"permissions"`
+}
+
+type PermissionOverrideDTO struct {
+	GroupId int                `json:"group_id"`
+	UserId  int                `json:"user_id"`
+	Allow   models.Permissions `json:"allow"`
+	Deny    models.Permissions `json:"deny"`
+}
+
+type CreateChannelDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Username    string `json:"username"`
+}
+
+// RestrictionDTO is ban or mute request, zero duration means permanent restriction
+type RestrictionDTO struct {
+	GroupId     int    `json:"group_id"`
+	UserId      int    `json:"user_id"`
+	Reason      string `json:"reason"`
+	DurationSec int    `json:"duration_sec"`
+}
+
+// AuditLogDTO is audit log page request, zero filter fields are not applied
+type AuditLogDTO struct {
+	GroupId  int
+	ActorId  int
+	TargetId int
+	Action   string
+	LastId   int
+	Count    int
+}
@@ -3,33 +3,126 @@ package groups
 import (
 	"context"
 	"fmt"
+	"messanger/config"
 	"messanger/domain/models"
 	"messanger/domain/ports"
 	"messanger/domain/service/auth"
+	"messanger/domain/service/permissions"
 	"messanger/pkg/db"
 	"messanger/pkg/errors"
 	"net/http"
 	"slices"
+	"time"
 )
 
 type GroupService struct {
-	chatsRepo  ports.ChatsRepo
-	groupsRepo ports.GroupsRepo
+	chatsRepo        ports.ChatsRepo
+	groupsRepo       ports.GroupsRepo
+	invitesRepo      ports.InvitesRepo
+	messagesRepo     ports.MessagesRepo
+	restrictionsRepo ports.RestrictionsRepo
+	auditRepo        ports.AuditRepo
+	storage          ports.FileStorage
+	perms            *permissions.Checker
+	events           EventsSender
+
+	maxImageSize int64
+}
+
+// EventsSender pushes group events to online members
+type EventsSender interface {
+	OnGroupUpdated(group *models.Group)
+	OnMessageCreated(msg *models.Message)
+	OnChatMemberAdded(chatId int, userId int)
+	OnChatMemberRemoved(chatId int, userId int)
+	OnChatDeleted(chatId int)
 }
 
-func NewGroupService(chatsRepo ports.ChatsRepo, groupsRepo ports.GroupsRepo) *GroupService {
-	return &GroupService{
-		chatsRepo:  chatsRepo,
-		groupsRepo: groupsRepo,
+func NewGroupService(
+	chatsRepo ports.ChatsRepo,
+	groupsRepo ports.GroupsRepo,
+	invitesRepo ports.InvitesRepo,
+	messagesRepo ports.MessagesRepo,
+	restrictionsRepo ports.RestrictionsRepo,
+	auditRepo ports.AuditRepo,
+	storage ports.FileStorage,
+	cfg *config.StorageConfig,
+) *GroupService {
+	maxImageSize := int64(cfg.MaxImageSizeKB) * 1024
+	if maxImageSize <= 0 {
+		maxImageSize = defaultMaxImageSize
+	}
+	s := &GroupService{
+		chatsRepo:        chatsRepo,
+		groupsRepo:       groupsRepo,
+		invitesRepo:      invitesRepo,
+		messagesRepo:     messagesRepo,
+		restrictionsRepo: restrictionsRepo,
+		auditRepo:        auditRepo,
+		storage:          storage,
+		perms:            permissions.NewChecker(groupsRepo, chatsRepo),
+		maxImageSize:     maxImageSize,
 	}
+
+	go func() {
+		for {
+			time.Sleep(time.Minute)
+			s.removeExpiredRestrictions()
+		}
+	}()
+
+	return s
+}
+
+func (s *GroupService) SetEventsSender(events EventsSender) {
+	s.events = events
 }
 
-func (s *GroupService) NewGroup(ctx context.Context, group *models.Group) (err *errors.Error) {
+func (s *GroupService) onGroupUpdated(group *models.Group) {
+	if s.events != nil {
+		go s.events.OnGroupUpdated(group)
+	}
+}
+
+// onMemberAdded must be called after transaction commit, so connections are not subscribed to chat on rollback
+func (s *GroupService) onMemberAdded(chatId int, userId int) {
+	if s.events != nil {
+		go s.events.OnChatMemberAdded(chatId, userId)
+	}
+}
+
+func (s *GroupService) onMemberRemoved(chatId int, userId int) {
+	if s.events != nil {
+		go s.events.OnChatMemberRemoved(chatId, userId)
+	}
+}
+
+func (s *GroupService) onChatDeleted(chatId int) {
+	if s.events != nil {
+		go s.events.OnChatDeleted(chatId)
+	}
+}
+
+func (s *GroupService) NewGroup(ctx context.Context, group *models.Group) *errors.Error {
+	if err := s.newGroup(ctx, models.ChatTypeGroup, group); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// newGroup creates chat of chatType with group and makes current user its owner
+func (s *GroupService) newGroup(ctx context.Context, chatType string, group *models.Group) (err *errors.Error) {
 	if len(group.Name) == 0 {
 		return errors.New1Msg("group name is missing", http.StatusBadRequest)
 	}
-	adminId := auth.ExtractUser(ctx)
-	chat := &models.Chat{Type: models.ChatTypeGroup}
+	if !models.ValidateGroupDescription(group.Description) {
+		return errors.New1Msg("group description is too long", http.StatusBadRequest)
+	}
+	if len(group.Username) != 0 && !models.ValidateGroupUsername(group.Username) {
+		return errors.New1Msg("invalid username", http.StatusBadRequest)
+	}
+	ownerId := auth.ExtractUser(ctx)
+	chat := &models.Chat{Type: chatType}
 
 	ctx, err = db.WithTx(ctx, s.chatsRepo)
 	if err != nil {
@@ -45,116 +138,264 @@ func (s *GroupService) NewGroup(ctx context.Context, group *models.Group) (err *
 	if err := s.groupsRepo.New(ctx, group); err != nil {
 		return err.Trace()
 	}
-	if err := s.groupsRepo.SetRole(ctx, adminId, group.Id, models.RoleAdmin); err != nil {
+	if len(group.Username) != 0 {
+		if err := s.groupsRepo.SetUsername(ctx, group.Id, group.Username); err != nil {
+			return err.Trace()
+		}
+	}
+	if err := s.groupsRepo.SetRole(ctx, ownerId, group.Id, models.RoleOwner); err != nil {
 		return err.Trace()
 	}
 	return nil
 }
 
-func (s *GroupService) UpdateGroup(ctx context.Context, groupId int, dto *UpdateGroupDTO) *errors.Error {
-	if len(dto.Name) == 0 {
-		return errors.New1Msg("group name is missing", http.StatusBadRequest)
+func (s *GroupService) UpdateGroup(ctx context.Context, groupId int, dto *UpdateGroupDTO) (err *errors.Error) {
+	if dto.Description != nil && !models.ValidateGroupDescription(*dto.Description) {
+		return errors.New1Msg("group description is too long", http.StatusBadRequest)
 	}
 
 	actionerId := auth.ExtractUser(ctx)
-	role, err := s.groupsRepo.GetRole(ctx, actionerId, groupId)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermEditInfo, "update group"); err != nil {
+		return err.Trace()
+	}
+	group, err := s.groupsRepo.GetById(ctx, groupId)
 	if err != nil {
 		return err.Trace()
 	}
-	if role != models.RoleAdmin {
-		return errors.New(fmt.Sprintf("user (%d) tried rename group (%d)", actionerId, groupId),
-			models.ErrPermissionDenied, http.StatusForbidden)
+
+	if dto.OnlyAdminsPost != nil && !*dto.OnlyAdminsPost {
+		chat, err := s.chatsRepo.GetById(ctx, group.ChatId)
+		if err != nil {
+			return err.Trace()
+		}
+		if chat.Type == models.ChatTypeChannel {
+			return errors.New1Msg("only admins can post in channel", http.StatusBadRequest)
+		}
 	}
 
-	if err := s.groupsRepo.Update(ctx, &models.Group{
-		Id:   groupId,
-		Name: dto.Name,
-	}); err != nil {
+	before := newAuditGroupInfo(group)
+	renamed := len(dto.Name) != 0 && dto.Name != group.Name
+	if len(dto.Name) != 0 {
+		group.Name = dto.Name
+	}
+	if dto.Description != nil {
+		group.Description = *dto.Description
+	}
+	if dto.OnlyAdminsPost != nil {
+		group.OnlyAdminsPost = *dto.OnlyAdminsPost
+	}
+	if dto.MembersCanInvite != nil {
+		group.MembersCanInvite = *dto.MembersCanInvite
+	}
+
+	var message *models.Message
+	defer func() {
+		if err == nil {
+			s.onGroupUpdated(group)
+			s.onMessageCreated(message)
+		}
+	}()
+	ctx, err = db.WithTx(ctx, s.groupsRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.groupsRepo.Update(ctx, group); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, groupId, actionerId, models.AuditActionUpdateInfo, 0, before, newAuditGroupInfo(group)); err != nil {
 		return err.Trace()
 	}
+	if renamed {
+		if message, err = s.addSystemMessage(ctx, group, &models.SystemPayload{
+			Action:  models.SystemActionRename,
+			ActorId: actionerId,
+			Name:    group.Name,
+		}); err != nil {
+			return err.Trace()
+		}
+	}
 	return nil
 }
 
-func (s *GroupService) AddUserToGroup(ctx context.Context, groupId int, userId int) (err *errors.Error) {
-	if userId == 0 {
-		return errors.New1Msg("userId is missing", http.StatusBadRequest)
+const maxSlowModeSec = 60 * 60
+
+func (s *GroupService) SetSlowMode(ctx context.Context, groupId int, seconds int) (err *errors.Error) {
+	if seconds < 0 || seconds > maxSlowModeSec {
+		return errors.New1Msg(fmt.Sprintf("slow mode must be from 0 to %d seconds", maxSlowModeSec), http.StatusBadRequest)
 	}
+
 	actionerId := auth.ExtractUser(ctx)
-	role, err := s.groupsRepo.GetRole(ctx, actionerId, groupId)
-	if err != nil {
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermEditInfo, "set slow mode"); err != nil {
 		return err.Trace()
 	}
-	if role != models.RoleAdmin {
-		return errors.New(fmt.Sprintf("user (%d) tried add user to group (%d)", actionerId, groupId),
-			models.ErrPermissionDenied, http.StatusForbidden)
+	group, err := s.groupsRepo.GetById(ctx, groupId)
+	if err != nil {
+		return err.Trace()
 	}
-	exist, err := s.groupsRepo.CheckUserInGroup(ctx, groupId, userId)
+
+	ctx, err = db.WithTx(ctx, s.groupsRepo)
 	if err != nil {
 		return err.Trace()
 	}
-	if exist {
-		return errors.New1Msg("user already exists", http.StatusBadRequest)
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.groupsRepo.SetSlowMode(ctx, groupId, seconds); err != nil {
+		return err.Trace()
 	}
+	if err := s.audit(ctx, groupId, actionerId, models.AuditActionSlowMode, 0,
+		map[string]int{"slow_mode_sec": group.SlowModeSec}, map[string]int{"slow_mode_sec": seconds}); err != nil {
+		return err.Trace()
+	}
+	group.SlowModeSec = seconds
+	s.onGroupUpdated(group)
+	return nil
+}
 
+func (s *GroupService) AddUserToGroup(ctx context.Context, groupId int, userId int) (err *errors.Error) {
+	if userId == 0 {
+		return errors.New1Msg("userId is missing", http.StatusBadRequest)
+	}
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermInvite, "add user"); err != nil {
+		return err.Trace()
+	}
 	group, err := s.groupsRepo.GetById(ctx, groupId)
 	if err != nil {
 		return err.Trace()
 	}
 
+	var message *models.Message
+	defer func() {
+		if err == nil {
+			s.onMemberAdded(group.ChatId, userId)
+			s.onMessageCreated(message)
+		}
+	}()
 	ctx, err = db.WithTx(ctx, s.chatsRepo)
 	if err != nil {
 		return err.Trace()
 	}
 	defer db.CommitOnDefer(ctx, &err)
 
-	if err := s.chatsRepo.AddUserToChat(ctx, group.ChatId, userId); err != nil {
+	if message, err = s.addMember(ctx, group, userId, actionerId); err != nil {
 		return err.Trace()
 	}
-	if err := s.groupsRepo.SetRole(ctx, group.Id, groupId, models.RoleMember); err != nil {
-
+	if err := s.audit(ctx, groupId, actionerId, models.AuditActionAddMember, userId, nil, nil); err != nil {
+		return err.Trace()
 	}
 	return nil
 }
 
+// addMember adds user to group, actorId is the user who added, or the user self if joined.
+// It returns system message to be pushed after transaction commit.
+func (s *GroupService) addMember(ctx context.Context, group *models.Group, userId int, actorId int) (*models.Message, *errors.Error) {
+	exist, err := s.groupsRepo.CheckUserInGroup(ctx, userId, group.Id)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if exist {
+		return nil, errors.New1Msg("user already exists", http.StatusBadRequest)
+	}
+	if err := s.checkNotBanned(ctx, group.Id, userId); err != nil {
+		return nil, err.Trace()
+	}
+
+	if err := s.chatsRepo.AddUserToChat(ctx, group.ChatId, userId); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.groupsRepo.SetRole(ctx, userId, group.Id, models.RoleMember); err != nil {
+		return nil, err.Trace()
+	}
+
+	payload := &models.SystemPayload{
+		Action:  models.SystemActionAdd,
+		ActorId: actorId,
+		UserId:  userId,
+	}
+	if actorId == userId {
+		payload.Action = models.SystemActionJoin
+		if err := s.audit(ctx, group.Id, userId, models.AuditActionJoin, 0, nil, nil); err != nil {
+			return nil, err.Trace()
+		}
+	}
+	message, err := s.addSystemMessage(ctx, group, payload)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return message, nil
+}
+
 func (s *GroupService) RemoveUserFromGroup(ctx context.Context, groupId int, userId int) (err *errors.Error) {
 	if userId == 0 {
 		return errors.New1Msg("userId is missing", http.StatusBadRequest)
 	}
 	actionerId := auth.ExtractUser(ctx)
-	role, err := s.groupsRepo.GetRole(ctx, actionerId, groupId)
+	if actionerId != userId {
+		if err := s.perms.Check(ctx, actionerId, groupId, models.PermRemoveMembers, "remove user"); err != nil {
+			return err.Trace()
+		}
+	}
+	role, err := s.perms.GetRole(ctx, actionerId, groupId)
 	if err != nil {
 		return err.Trace()
 	}
-	userRole, err := s.groupsRepo.GetRole(ctx, userId, groupId)
+	userRole, err := s.perms.GetRole(ctx, userId, groupId)
 	if err != nil {
-		if err.Code != http.StatusNotFound {
-			return err.Trace()
-		}
-		userRole = models.RoleMember
+		return err.Trace()
 	}
 
 	group, err := s.groupsRepo.GetById(ctx, groupId)
 	if err != nil {
 		return err.Trace()
 	}
-	if role != models.RoleAdmin && actionerId != userId {
-		return errors.New(fmt.Sprintf("user (%d) tried remove user from group (%d)", actionerId, groupId),
+	if actionerId != userId && userRole == models.RoleOwner {
+		return errors.New(fmt.Sprintf("user (%d) tried remove owner (%d) from group (%d)", actionerId, userId, groupId),
 			models.ErrPermissionDenied, http.StatusForbidden)
 	}
-	if role == userRole && role == models.RoleAdmin {
-		return errors.New(fmt.Sprintf("admin (%d) tried remove admin (%d) from group (%d)", actionerId, userId, groupId),
+	if actionerId != userId && userRole == models.RoleAdmin && role != models.RoleOwner {
+		return errors.New(fmt.Sprintf("user (%d) tried remove admin (%d) from group (%d)", actionerId, userId, groupId),
 			models.ErrPermissionDenied, http.StatusForbidden)
 	}
 
+	var message *models.Message
+	defer func() {
+		if err == nil {
+			s.onMemberRemoved(group.ChatId, userId)
+			s.onMessageCreated(message)
+		}
+	}()
 	ctx, err = db.WithTx(ctx, s.chatsRepo)
 	if err != nil {
 		return err.Trace()
 	}
 	defer db.CommitOnDefer(ctx, &err)
 
+	successor, err := s.perms.EnsureSuccessor(ctx, groupId, userId)
+	if err != nil {
+		return err.Trace()
+	}
+	if successor != 0 {
+		if err := s.audit(ctx, groupId, userId, models.AuditActionSuccession, successor, nil, nil); err != nil {
+			return err.Trace()
+		}
+	}
 	if err := s.chatsRepo.RemoveUserFromChat(ctx, group.ChatId, userId); err != nil {
 		return err.Trace()
 	}
+	if err := s.groupsRepo.DeletePermissionOverride(ctx, userId, groupId); err != nil {
+		return err.Trace()
+	}
+	if actionerId != userId {
+		if err := s.audit(ctx, groupId, actionerId, models.AuditActionRemoveMember, userId, map[string]string{"role": userRole}, nil); err != nil {
+			return err.Trace()
+		}
+	} else {
+		if err := s.audit(ctx, groupId, userId, models.AuditActionLeave, 0, map[string]string{"role": userRole}, nil); err != nil {
+			return err.Trace()
+		}
+	}
 
 	count, err := s.chatsRepo.CountUsersInChat(ctx, group.ChatId)
 	if err != nil {
@@ -164,24 +405,142 @@ func (s *GroupService) RemoveUserFromGroup(ctx context.Context, groupId int, use
 		if err := s.chatsRepo.Delete(ctx, group.ChatId); err != nil {
 			return err.Trace()
 		}
+		return nil
+	}
+
+	payload := &models.SystemPayload{
+		Action:  models.SystemActionKick,
+		ActorId: actionerId,
+		UserId:  userId,
+	}
+	if actionerId == userId {
+		payload.Action = models.SystemActionLeave
+	}
+	if message, err = s.addSystemMessage(ctx, group, payload); err != nil {
+		return err.Trace()
 	}
 	return nil
 }
 
-func (s *GroupService) SetUsersRole(ctx context.Context, groupId int, userId int, role string) *errors.Error {
+func (s *GroupService) SetUsersRole(ctx context.Context, groupId int, userId int, role string) (err *errors.Error) {
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermManageRoles, "set role"); err != nil {
+		return err.Trace()
+	}
 	if !models.ValidateRole(role) {
-		return errors.New1Msg("invalid role: "+role, http.StatusBadRequest)
+		customRole, err := s.groupsRepo.GetCustomRole(ctx, groupId, role)
+		if err != nil {
+			if err.Code == http.StatusNotFound {
+				return errors.New1Msg("invalid role: "+role, http.StatusBadRequest)
+			}
+			return err.Trace()
+		}
+		if err := s.checkGrantable(ctx, actionerId, groupId, customRole.Permissions); err != nil {
+			return err.Trace()
+		}
+	}
+	exist, err := s.groupsRepo.CheckUserInGroup(ctx, userId, groupId)
+	if err != nil {
+		return err.Trace()
+	}
+	if !exist {
+		return errors.New1Msg("user is not in group", http.StatusBadRequest)
+	}
+
+	if err := s.checkCanManage(ctx, actionerId, userId, groupId); err != nil {
+		return err.Trace()
+	}
+	oldRole, err := s.perms.GetRole(ctx, userId, groupId)
+	if err != nil {
+		return err.Trace()
+	}
+	if role == models.RoleAdmin {
+		actionerRole, err := s.perms.GetRole(ctx, actionerId, groupId)
+		if err != nil {
+			return err.Trace()
+		}
+		if actionerRole != models.RoleOwner && actionerRole != models.RoleAdmin {
+			return errors.New(fmt.Sprintf("user (%d) tried grant admin in group (%d)", actionerId, groupId),
+				models.ErrPermissionDenied, http.StatusForbidden)
+		}
+	}
+
+	group, err := s.groupsRepo.GetById(ctx, groupId)
+	if err != nil {
+		return err.Trace()
+	}
+
+	var message *models.Message
+	defer func() {
+		if err == nil {
+			s.onMessageCreated(message)
+		}
+	}()
+	ctx, err = db.WithTx(ctx, s.groupsRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.groupsRepo.SetRole(ctx, userId, groupId, role); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, groupId, actionerId, models.AuditActionRoleChange, userId,
+		map[string]string{"role": oldRole}, map[string]string{"role": role}); err != nil {
+		return err.Trace()
+	}
+	if message, err = s.addSystemMessage(ctx, group, &models.SystemPayload{
+		Action:  models.SystemActionRoleChange,
+		ActorId: actionerId,
+		UserId:  userId,
+		Role:    role,
+	}); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// checkCanManage checks that actioner can change role and permissions of user:
+// owner can't be managed, admins can be managed only by owner, as only owner can remove them
+func (s *GroupService) checkCanManage(ctx context.Context, actionerId int, userId int, groupId int) *errors.Error {
+	userRole, err := s.perms.GetRole(ctx, userId, groupId)
+	if err != nil {
+		return err.Trace()
+	}
+	if userRole == models.RoleOwner {
+		return errors.New(fmt.Sprintf("user (%d) tried change owner (%d) of group (%d)", actionerId, userId, groupId),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+	if userRole == models.RoleAdmin {
+		actionerRole, err := s.perms.GetRole(ctx, actionerId, groupId)
+		if err != nil {
+			return err.Trace()
+		}
+		if actionerRole != models.RoleOwner {
+			return errors.New(fmt.Sprintf("user (%d) tried change admin (%d) of group (%d)", actionerId, userId, groupId),
+				models.ErrPermissionDenied, http.StatusForbidden)
+		}
+	}
+	return nil
+}
+
+func (s *GroupService) TransferOwnership(ctx context.Context, groupId int, userId int) (err *errors.Error) {
+	if userId == 0 {
+		return errors.New1Msg("userId is missing", http.StatusBadRequest)
 	}
 	actionerId := auth.ExtractUser(ctx)
-	actionerRole, err := s.groupsRepo.GetRole(ctx, actionerId, groupId)
+	if actionerId == userId {
+		return errors.New1Msg("user is already owner", http.StatusBadRequest)
+	}
+	role, err := s.perms.GetRole(ctx, actionerId, groupId)
 	if err != nil {
 		return err.Trace()
 	}
-	if actionerRole != models.RoleAdmin {
-		return errors.New(fmt.Sprintf("user (%d) tried set role in group (%d)", actionerId, groupId),
+	if role != models.RoleOwner {
+		return errors.New(fmt.Sprintf("user (%d) tried transfer ownership of group (%d)", actionerId, groupId),
 			models.ErrPermissionDenied, http.StatusForbidden)
 	}
-	exist, err := s.groupsRepo.CheckUserInGroup(ctx, groupId, userId)
+	exist, err := s.groupsRepo.CheckUserInGroup(ctx, userId, groupId)
 	if err != nil {
 		return err.Trace()
 	}
@@ -189,7 +548,39 @@ func (s *GroupService) SetUsersRole(ctx context.Context, groupId int, userId int
 		return errors.New1Msg("user is not in group", http.StatusBadRequest)
 	}
 
-	if err := s.groupsRepo.SetRole(ctx, userId, groupId, role); err != nil {
+	var message *models.Message
+	defer func() {
+		if err == nil {
+			s.onMessageCreated(message)
+		}
+	}()
+	ctx, err = db.WithTx(ctx, s.groupsRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.groupsRepo.SetRole(ctx, userId, groupId, models.RoleOwner); err != nil {
+		return err.Trace()
+	}
+	if err := s.groupsRepo.SetRole(ctx, actionerId, groupId, models.RoleAdmin); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, groupId, actionerId, models.AuditActionTransferOwnership, userId,
+		map[string]int{"owner_id": actionerId}, map[string]int{"owner_id": userId}); err != nil {
+		return err.Trace()
+	}
+
+	group, err := s.groupsRepo.GetById(ctx, groupId)
+	if err != nil {
+		return err.Trace()
+	}
+	if message, err = s.addSystemMessage(ctx, group, &models.SystemPayload{
+		Action:  models.SystemActionRoleChange,
+		ActorId: actionerId,
+		UserId:  userId,
+		Role:    models.RoleOwner,
+	}); err != nil {
 		return err.Trace()
 	}
 	return nil
@@ -210,13 +601,9 @@ func (s *GroupService) GetUsersByGroup(ctx context.Context, groupId int) ([]GetU
 	}
 	var resp []GetUsersDTO
 	for _, user := range users {
-		role, err := s.groupsRepo.GetRole(ctx, user, groupId)
+		role, err := s.perms.GetRole(ctx, user, groupId)
 		if err != nil {
-			if err.Code == http.StatusNotFound {
-				role = models.RoleMember
-			} else {
-				return nil, err.Trace()
-			}
+			return nil, err.Trace()
 		}
 		resp = append(resp, GetUsersDTO{
 			UserId: user,
@@ -229,11 +616,11 @@ func (s *GroupService) GetUsersByGroup(ctx context.Context, groupId int) ([]GetU
 
 func (s *GroupService) RemoveGroup(ctx context.Context, groupId int) (err *errors.Error) {
 	actionerId := auth.ExtractUser(ctx)
-	role, err := s.groupsRepo.GetRole(ctx, actionerId, groupId)
+	role, err := s.perms.GetRole(ctx, actionerId, groupId)
 	if err != nil {
 		return err.Trace()
 	}
-	if role != models.RoleAdmin {
+	if role != models.RoleOwner {
 		return errors.New(fmt.Sprintf("user (%d) tried remove group (%d)", actionerId, groupId),
 			models.ErrPermissionDenied, http.StatusForbidden)
 	}
@@ -243,17 +630,30 @@ func (s *GroupService) RemoveGroup(ctx context.Context, groupId int) (err *error
 		return err.Trace()
 	}
 
+	defer func() {
+		if err == nil {
+			s.onChatDeleted(group.ChatId)
+		}
+	}()
 	ctx, err = db.WithTx(ctx, s.chatsRepo)
 	if err != nil {
 		return err.Trace()
 	}
 	defer db.CommitOnDefer(ctx, &err)
 
+	if err := s.audit(ctx, group.Id, actionerId, models.AuditActionDeleteGroup, 0, newAuditGroupInfo(group), nil); err != nil {
+		return err.Trace()
+	}
 	if err := s.groupsRepo.Delete(ctx, group.Id); err != nil {
 		return err.Trace()
 	}
 	if err := s.chatsRepo.Delete(ctx, group.ChatId); err != nil {
 		return err.Trace()
 	}
+	if len(group.Avatar) != 0 {
+		if err := s.storage.Delete(ctx, group.Avatar); err != nil {
+			return err.Trace()
+		}
+	}
 	return nil
 }
@@ -0,0 +1,387 @@
+package groups
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/config"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	testGroupId = 1
+	testChatId  = 100
+
+	ownerId      = 1
+	adminId      = 2
+	memberId     = 3
+	otherAdminId = 4
+	joinerId     = 5
+)
+
+type testRepos struct {
+	chats        *mocks.ChatsRepo
+	groups       *mocks.GroupsRepo
+	invites      *mocks.InvitesRepo
+	messages     *mocks.MessagesRepo
+	restrictions *mocks.RestrictionsRepo
+	audit        *mocks.AuditRepo
+}
+
+// newTestGroupService returns service for group with owner, two admins and member,
+// membership and roles of other users are set up by tests
+func newTestGroupService(t *testing.T, group *models.Group) (*GroupService, *testRepos) {
+	r := &testRepos{
+		chats:        mocks.NewChatsRepo(t),
+		groups:       mocks.NewGroupsRepo(t),
+		invites:      mocks.NewInvitesRepo(t),
+		messages:     mocks.NewMessagesRepo(t),
+		restrictions: mocks.NewRestrictionsRepo(t),
+		audit:        mocks.NewAuditRepo(t),
+	}
+	roles := map[int]string{ownerId: models.RoleOwner, adminId: models.RoleAdmin, memberId: models.RoleMember, otherAdminId: models.RoleAdmin}
+
+	r.groups.On("CheckUserInGroup", mock.Anything, mock.Anything, testGroupId).Return(func(_ context.Context, userId int, _ int) (bool, *errors.Error) {
+		_, ok := roles[userId]
+		return ok, nil
+	}).Maybe()
+	r.groups.On("GetRole", mock.Anything, mock.Anything, testGroupId).Return(func(_ context.Context, userId int, _ int) (string, *errors.Error) {
+		role, ok := roles[userId]
+		if !ok {
+			return "", errors.New1Msg("role not found", http.StatusNotFound)
+		}
+		return role, nil
+	}).Maybe()
+	r.groups.On("GetPermissionOverride", mock.Anything, mock.Anything, testGroupId).Return(&models.PermissionOverride{}, nil).Maybe()
+	r.groups.On("GetById", mock.Anything, testGroupId).Return(group, nil).Maybe()
+	r.chats.On("GetById", mock.Anything, testChatId).Return(&models.Chat{Id: testChatId, Type: models.ChatTypeGroup}, nil).Maybe()
+	r.restrictions.On("Get", mock.Anything, testGroupId, mock.Anything, models.RestrictionBan).
+		Return(nil, errors.New1Msg("restriction not found", http.StatusNotFound)).Maybe()
+
+	s := NewGroupService(r.chats, r.groups, r.invites, r.messages, r.restrictions, r.audit,
+		mocks.NewFileStorage(t), &config.StorageConfig{})
+	return s, r
+}
+
+func newTestGroup() *models.Group {
+	return &models.Group{Id: testGroupId, ChatId: testChatId, Name: "test"}
+}
+
+// expectJoin sets up repos for user joining group with system message and audit entry
+func expectJoin(r *testRepos, userId int) {
+	r.audit.On("New", mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionJoin && e.ActorId == userId
+	})).Return(nil).Once()
+	r.chats.On("AddUserToChat", mock.Anything, testChatId, userId).Return(nil).Once()
+	r.groups.On("SetRole", mock.Anything, userId, testGroupId, models.RoleMember).Return(nil).Once()
+	r.messages.On("New", mock.Anything, mock.MatchedBy(func(m *models.Message) bool {
+		return m.Kind == models.MessageKindSystem && m.Payload.UserId == userId
+	})).Return(nil).Once()
+	r.chats.On("UpdateTime", mock.Anything, testChatId, mock.Anything).Return(nil).Once()
+}
+
+func TestJoinByInvite(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), joinerId)
+	s, r := newTestGroupService(t, newTestGroup())
+
+	expired := time.Now().Add(-time.Minute)
+	r.invites.On("GetByToken", mock.Anything, "expired").Return(&models.GroupInvite{Id: 1, GroupId: testGroupId, ExpireTime: &expired}, nil)
+	r.invites.On("GetByToken", mock.Anything, "revoked").Return(&models.GroupInvite{Id: 2, GroupId: testGroupId, Revoked: true}, nil)
+	r.invites.On("GetByToken", mock.Anything, "used").Return(&models.GroupInvite{Id: 3, GroupId: testGroupId, MaxUses: 1, Uses: 1}, nil)
+	r.invites.On("Use", mock.Anything, 3).Return(false, nil)
+
+	for _, token := range []string{"expired", "revoked", "used"} {
+		_, err := s.JoinByInvite(ctx, token)
+		require.NotNil(t, err, token)
+		require.Equal(t, http.StatusGone, err.Code, token)
+	}
+
+	valid := time.Now().Add(time.Hour)
+	r.invites.On("GetByToken", mock.Anything, "valid").Return(&models.GroupInvite{Id: 4, GroupId: testGroupId, ExpireTime: &valid, MaxUses: 2}, nil)
+	r.invites.On("Use", mock.Anything, 4).Return(true, nil)
+	expectJoin(r, joinerId)
+
+	resp, err := s.JoinByInvite(ctx, "valid")
+	require.Nil(t, err)
+	require.Equal(t, JoinStatusJoined, resp.Status)
+	require.Equal(t, testGroupId, resp.GroupId)
+}
+
+func TestJoinByInviteApproval(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), joinerId)
+	s, r := newTestGroupService(t, newTestGroup())
+
+	r.invites.On("GetByToken", mock.Anything, "approval").Return(&models.GroupInvite{Id: 1, GroupId: testGroupId, NeedApproval: true}, nil)
+	r.invites.On("Use", mock.Anything, 1).Return(true, nil)
+	r.invites.On("NewJoinRequest", mock.Anything, mock.MatchedBy(func(req *models.JoinRequest) bool {
+		return req.UserId == joinerId && req.GroupId == testGroupId && req.InviteId == 1
+	})).Return(nil)
+
+	resp, err := s.JoinByInvite(ctx, "approval")
+	require.Nil(t, err)
+	require.Equal(t, JoinStatusPending, resp.Status)
+}
+
+func TestCreateInvite(t *testing.T) {
+	s, r := newTestGroupService(t, newTestGroup())
+
+	_, err := s.CreateInvite(auth.CtxWithUser(context.Background(), adminId), &CreateInviteDTO{GroupId: testGroupId, MaxUses: -1})
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code)
+
+	_, err = s.CreateInvite(auth.CtxWithUser(context.Background(), memberId), &CreateInviteDTO{GroupId: testGroupId})
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code, "member should not create invites by default")
+
+	r.invites.On("New", mock.Anything, mock.Anything).Return(nil).Once()
+	r.audit.On("New", mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionCreateInvite && e.ActorId == adminId
+	})).Return(nil).Once()
+
+	invite, err := s.CreateInvite(auth.CtxWithUser(context.Background(), adminId), &CreateInviteDTO{
+		GroupId:   testGroupId,
+		ExpireSec: 60,
+		MaxUses:   10,
+	})
+	require.Nil(t, err)
+	require.NotEmpty(t, invite.Token)
+	require.Equal(t, 10, invite.MaxUses)
+	require.NotNil(t, invite.ExpireTime)
+	require.WithinDuration(t, time.Now().Add(time.Minute), *invite.ExpireTime, time.Second)
+}
+
+func TestJoinRequestAuthorization(t *testing.T) {
+	s, r := newTestGroupService(t, newTestGroup())
+	const requestId = 7
+	r.invites.On("GetJoinRequestById", mock.Anything, requestId).Return(&models.JoinRequest{
+		Id:      requestId,
+		GroupId: testGroupId,
+		UserId:  joinerId,
+	}, nil)
+
+	memberCtx := auth.CtxWithUser(context.Background(), memberId)
+	err := s.ApproveJoinRequest(memberCtx, requestId)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+	err = s.RejectJoinRequest(memberCtx, requestId)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+	_, err = s.GetJoinRequests(memberCtx, testGroupId)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+
+	err = s.ApproveJoinRequest(auth.CtxWithUser(context.Background(), joinerId), requestId)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code, "requester should not approve own request")
+
+	adminCtx := auth.CtxWithUser(context.Background(), adminId)
+	r.invites.On("DeleteJoinRequest", mock.Anything, requestId).Return(nil)
+	r.audit.On("New", mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionRejectJoin && e.ActorId == adminId && e.TargetId == joinerId
+	})).Return(nil).Once()
+	require.Nil(t, s.RejectJoinRequest(adminCtx, requestId))
+
+	expectJoin(r, joinerId)
+	r.audit.On("New", mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionApproveJoin && e.ActorId == adminId && e.TargetId == joinerId
+	})).Return(nil).Once()
+	require.Nil(t, s.ApproveJoinRequest(adminCtx, requestId))
+}
+
+func TestUsername(t *testing.T) {
+	s, r := newTestGroupService(t, newTestGroup())
+	ctx := auth.CtxWithUser(context.Background(), adminId)
+
+	r.groups.On("GetByUsername", mock.Anything, "taken_name").Return(&models.Group{Id: 2, Username: "taken_name"}, nil)
+	r.groups.On("GetByUsername", mock.Anything, "free_name").Return(nil, errors.New1Msg("group not found", http.StatusNotFound))
+
+	taken, err := s.CheckUsername(ctx, "taken_name")
+	require.Nil(t, err)
+	require.True(t, taken)
+	taken, err = s.CheckUsername(ctx, "free_name")
+	require.Nil(t, err)
+	require.False(t, taken)
+	_, err = s.CheckUsername(ctx, "ab")
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code)
+
+	// unique index of username reports collision, audit entry must not be written
+	r.groups.On("SetUsername", mock.Anything, testGroupId, "taken_name").
+		Return(errors.New1Msg("username is already taken", http.StatusBadRequest))
+	err = s.SetUsername(ctx, testGroupId, "taken_name")
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code)
+
+	err = s.SetUsername(auth.CtxWithUser(context.Background(), memberId), testGroupId, "free_name")
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+
+	r.groups.On("SetUsername", mock.Anything, testGroupId, "free_name").Return(nil)
+	r.audit.On("New", mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionUsername
+	})).Return(nil).Once()
+	require.Nil(t, s.SetUsername(ctx, testGroupId, "free_name"))
+}
+
+func TestGetAuditLog(t *testing.T) {
+	s, r := newTestGroupService(t, newTestGroup())
+	ctx := auth.CtxWithUser(context.Background(), adminId)
+
+	r.audit.On("GetByGroup", mock.Anything, mock.Anything).Return(func(_ context.Context, f *models.AuditFilter) ([]models.AuditEntry, *errors.Error) {
+		entries := make([]models.AuditEntry, 0, f.Count)
+		for id := f.LastId - 1; id > 0 && len(entries) < f.Count; id-- {
+			entries = append(entries, models.AuditEntry{Id: id, GroupId: f.GroupId})
+		}
+		return entries, nil
+	})
+
+	entries, err := s.GetAuditLog(ctx, &AuditLogDTO{GroupId: testGroupId, LastId: 200})
+	require.Nil(t, err)
+	require.Len(t, entries, defaultAuditCount, "default page size should be used")
+	require.Equal(t, 199, entries[0].Id)
+
+	entries, err = s.GetAuditLog(ctx, &AuditLogDTO{GroupId: testGroupId, LastId: entries[len(entries)-1].Id, Count: maxAuditCount})
+	require.Nil(t, err)
+	require.Len(t, entries, maxAuditCount)
+	require.Equal(t, 149, entries[0].Id, "next page should start after the last entry")
+
+	_, err = s.GetAuditLog(ctx, &AuditLogDTO{GroupId: testGroupId, Count: maxAuditCount + 1})
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code)
+
+	_, err = s.GetAuditLog(ctx, &AuditLogDTO{GroupId: testGroupId, Action: "unknown"})
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code)
+
+	_, err = s.GetAuditLog(auth.CtxWithUser(context.Background(), memberId), &AuditLogDTO{GroupId: testGroupId})
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+}
+
+func TestMembersCanInvite(t *testing.T) {
+	group := newTestGroup()
+	group.MembersCanInvite = true
+	s, r := newTestGroupService(t, group)
+	ctx := auth.CtxWithUser(context.Background(), memberId)
+
+	r.invites.On("New", mock.Anything, mock.Anything).Return(nil).Once()
+	r.audit.On("New", mock.Anything, mock.Anything).Return(nil).Once()
+	_, err := s.CreateInvite(ctx, &CreateInviteDTO{GroupId: testGroupId})
+	require.Nil(t, err, "member should create invites when members can invite")
+
+	_, err = s.GetInvites(ctx, testGroupId)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+
+	r.invites.On("GetById", mock.Anything, 1).Return(&models.GroupInvite{Id: 1, GroupId: testGroupId, CreatorId: adminId}, nil)
+	err = s.RevokeInvite(ctx, 1)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+
+	r.invites.On("GetJoinRequestById", mock.Anything, 7).Return(&models.JoinRequest{Id: 7, GroupId: testGroupId, UserId: joinerId}, nil)
+	err = s.ApproveJoinRequest(ctx, 7)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code, "members can invite should not allow approving join requests")
+}
+
+func TestLeaveSuccession(t *testing.T) {
+	s, r := newTestGroupService(t, newTestGroup())
+	ctx := auth.CtxWithUser(context.Background(), ownerId)
+
+	r.groups.On("GetUsersByGroup", mock.Anything, testGroupId).Return([]int{ownerId, memberId, adminId}, nil)
+	r.groups.On("SetRole", mock.Anything, adminId, testGroupId, models.RoleOwner).Return(nil).Once()
+	r.chats.On("RemoveUserFromChat", mock.Anything, testChatId, ownerId).Return(nil)
+	r.groups.On("DeletePermissionOverride", mock.Anything, ownerId, testGroupId).Return(nil)
+	r.chats.On("CountUsersInChat", mock.Anything, testChatId).Return(2, nil)
+	r.messages.On("New", mock.Anything, mock.MatchedBy(func(m *models.Message) bool {
+		return m.Payload.Action == models.SystemActionLeave
+	})).Return(nil).Once()
+	r.chats.On("UpdateTime", mock.Anything, testChatId, mock.Anything).Return(nil).Once()
+
+	var actions []string
+	r.audit.On("New", mock.Anything, mock.Anything).Return(func(_ context.Context, e *models.AuditEntry) *errors.Error {
+		require.Equal(t, ownerId, e.ActorId)
+		if e.Action == models.AuditActionSuccession {
+			require.Equal(t, adminId, e.TargetId, "admin should become owner")
+		}
+		actions = append(actions, e.Action)
+		return nil
+	})
+
+	require.Nil(t, s.Leave(ctx, testGroupId))
+	require.Equal(t, []string{models.AuditActionSuccession, models.AuditActionLeave}, actions)
+}
+
+func TestSetAdminRole(t *testing.T) {
+	s, r := newTestGroupService(t, newTestGroup())
+	adminCtx := auth.CtxWithUser(context.Background(), adminId)
+
+	for _, role := range []string{models.RoleMember, models.RoleAdmin} {
+		err := s.SetUsersRole(adminCtx, testGroupId, otherAdminId, role)
+		require.NotNil(t, err, "admin should not change role of other admin")
+		require.Equal(t, http.StatusForbidden, err.Code)
+	}
+	err := s.SetUsersRole(adminCtx, testGroupId, ownerId, models.RoleMember)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+
+	// only owner can demote admin, so admin can't demote and then remove other admin
+	r.groups.On("SetRole", mock.Anything, otherAdminId, testGroupId, models.RoleMember).Return(nil).Once()
+	r.audit.On("New", mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionRoleChange && e.TargetId == otherAdminId
+	})).Return(nil).Once()
+	r.messages.On("New", mock.Anything, mock.MatchedBy(func(m *models.Message) bool {
+		return m.Payload.Action == models.SystemActionRoleChange && m.Payload.UserId == otherAdminId
+	})).Return(nil).Once()
+	r.chats.On("UpdateTime", mock.Anything, testChatId, mock.Anything).Return(nil).Once()
+	require.Nil(t, s.SetUsersRole(auth.CtxWithUser(context.Background(), ownerId), testGroupId, otherAdminId, models.RoleMember))
+}
+
+type testEvents struct {
+	messages chan *models.Message
+}
+
+func (e *testEvents) OnGroupUpdated(*models.Group)       {}
+func (e *testEvents) OnMessageCreated(m *models.Message) { e.messages <- m }
+func (e *testEvents) OnChatMemberAdded(int, int)         {}
+func (e *testEvents) OnChatMemberRemoved(int, int)       {}
+func (e *testEvents) OnChatDeleted(int)                  {}
+
+func TestSystemMessagePushedAfterCommit(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), ownerId)
+	s, r := newTestGroupService(t, newTestGroup())
+	events := &testEvents{messages: make(chan *models.Message, 2)}
+	s.SetEventsSender(events)
+
+	messageId := 0
+	r.chats.On("AddUserToChat", mock.Anything, testChatId, joinerId).Return(nil).Twice()
+	r.groups.On("SetRole", mock.Anything, joinerId, testGroupId, models.RoleMember).Return(nil).Twice()
+	r.messages.On("New", mock.Anything, mock.Anything).Return(func(_ context.Context, m *models.Message) *errors.Error {
+		messageId++
+		m.Id = messageId
+		return nil
+	}).Twice()
+	r.chats.On("UpdateTime", mock.Anything, testChatId, mock.Anything).Return(nil).Twice()
+
+	// transaction is rolled back by audit error, message is not pushed
+	r.audit.On("New", mock.Anything, mock.Anything).Return(errors.New1Msg(models.ErrDatabaseError, http.StatusInternalServerError)).Once()
+	require.NotNil(t, s.AddUserToGroup(ctx, testGroupId, joinerId))
+
+	r.audit.On("New", mock.Anything, mock.Anything).Return(nil).Once()
+	require.Nil(t, s.AddUserToGroup(ctx, testGroupId, joinerId))
+
+	select {
+	case m := <-events.messages:
+		require.Equal(t, 2, m.Id)
+	case <-time.After(time.Second):
+		t.Fatal("message is not pushed")
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.Empty(t, events.messages)
+}
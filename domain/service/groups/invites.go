@@ -0,0 +1,258 @@
+package groups
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"messanger/domain/models"
+	"messanger/domain/service/auth"
+	"messanger/pkg/db"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+const inviteTokenLen = 18
+
+func (s *GroupService) CreateInvite(ctx context.Context, dto *CreateInviteDTO) (invite *models.GroupInvite, err *errors.Error) {
+	if dto.ExpireSec < 0 || dto.MaxUses < 0 {
+		return nil, errors.New1Msg("invalid invite params", http.StatusBadRequest)
+	}
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, dto.GroupId, models.PermInvite, "create invite"); err != nil {
+		return nil, err.Trace()
+	}
+
+	token, e := newInviteToken()
+	if e != nil {
+		return nil, errors.New(e, "create invite error", http.StatusInternalServerError)
+	}
+	invite = &models.GroupInvite{
+		GroupId:      dto.GroupId,
+		CreatorId:    actionerId,
+		Token:        token,
+		MaxUses:      dto.MaxUses,
+		NeedApproval: dto.NeedApproval,
+	}
+	if dto.ExpireSec > 0 {
+		expire := time.Now().Add(time.Duration(dto.ExpireSec) * time.Second)
+		invite.ExpireTime = &expire
+	}
+
+	ctx, err = db.WithTx(ctx, s.invitesRepo)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.invitesRepo.New(ctx, invite); err != nil {
+		return nil, err.Trace()
+	}
+	// token is secret, audit log keeps only invite params
+	after := *invite
+	after.Token = ""
+	if err := s.audit(ctx, dto.GroupId, actionerId, models.AuditActionCreateInvite, 0, nil, after); err != nil {
+		return nil, err.Trace()
+	}
+	return invite, nil
+}
+
+func (s *GroupService) GetInvites(ctx context.Context, groupId int) ([]models.GroupInvite, *errors.Error) {
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermManageInvites, "get invites"); err != nil {
+		return nil, err.Trace()
+	}
+	invites, err := s.invitesRepo.GetByGroup(ctx, groupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return invites, nil
+}
+
+func (s *GroupService) RevokeInvite(ctx context.Context, inviteId int) (err *errors.Error) {
+	if inviteId == 0 {
+		return errors.New1Msg("missing invite id", http.StatusBadRequest)
+	}
+	invite, err := s.invitesRepo.GetById(ctx, inviteId)
+	if err != nil {
+		return err.Trace()
+	}
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, invite.GroupId, models.PermManageInvites, "revoke invite"); err != nil {
+		return err.Trace()
+	}
+	ctx, err = db.WithTx(ctx, s.invitesRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.invitesRepo.Revoke(ctx, invite.Id); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, invite.GroupId, actionerId, models.AuditActionRevokeInvite, 0, nil, map[string]int{"invite_id": invite.Id}); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *GroupService) JoinByInvite(ctx context.Context, token string) (resp *JoinResponseDTO, err *errors.Error) {
+	if len(token) == 0 {
+		return nil, errors.New1Msg("missing invite token", http.StatusBadRequest)
+	}
+	invite, err := s.invitesRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if invite.Revoked {
+		return nil, errors.New1Msg("invite revoked", http.StatusGone)
+	}
+	if invite.ExpireTime != nil && time.Now().After(*invite.ExpireTime) {
+		return nil, errors.New1Msg("invite expired", http.StatusGone)
+	}
+
+	userId := auth.ExtractUser(ctx)
+	group, err := s.groupsRepo.GetById(ctx, invite.GroupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.checkNotBanned(ctx, group.Id, userId); err != nil {
+		return nil, err.Trace()
+	}
+
+	var message *models.Message
+	defer func() {
+		if err == nil && resp.Status == JoinStatusJoined {
+			s.onMemberAdded(group.ChatId, userId)
+			s.onMessageCreated(message)
+		}
+	}()
+	ctx, err = db.WithTx(ctx, s.invitesRepo)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	exist, err := s.groupsRepo.CheckUserInGroup(ctx, userId, group.Id)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if exist {
+		return nil, errors.New1Msg("user already exists", http.StatusBadRequest)
+	}
+
+	ok, err := s.invitesRepo.Use(ctx, invite.Id)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if !ok {
+		return nil, errors.New1Msg("invite usage limit reached", http.StatusGone)
+	}
+
+	if invite.NeedApproval {
+		if err := s.invitesRepo.NewJoinRequest(ctx, &models.JoinRequest{
+			GroupId:  group.Id,
+			UserId:   userId,
+			InviteId: invite.Id,
+		}); err != nil {
+			return nil, err.Trace()
+		}
+		return &JoinResponseDTO{GroupId: group.Id, Status: JoinStatusPending}, nil
+	}
+
+	if message, err = s.addMember(ctx, group, userId, userId); err != nil {
+		return nil, err.Trace()
+	}
+	return &JoinResponseDTO{GroupId: group.Id, Status: JoinStatusJoined}, nil
+}
+
+func (s *GroupService) GetJoinRequests(ctx context.Context, groupId int) ([]models.JoinRequest, *errors.Error) {
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermManageInvites, "get join requests"); err != nil {
+		return nil, err.Trace()
+	}
+	requests, err := s.invitesRepo.GetJoinRequestsByGroup(ctx, groupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return requests, nil
+}
+
+func (s *GroupService) ApproveJoinRequest(ctx context.Context, requestId int) (err *errors.Error) {
+	request, err := s.getJoinRequest(ctx, requestId, "approve join request")
+	if err != nil {
+		return err.Trace()
+	}
+	group, err := s.groupsRepo.GetById(ctx, request.GroupId)
+	if err != nil {
+		return err.Trace()
+	}
+
+	var message *models.Message
+	defer func() {
+		if err == nil {
+			s.onMemberAdded(group.ChatId, request.UserId)
+			s.onMessageCreated(message)
+		}
+	}()
+	ctx, err = db.WithTx(ctx, s.invitesRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.invitesRepo.DeleteJoinRequest(ctx, request.Id); err != nil {
+		return err.Trace()
+	}
+	if message, err = s.addMember(ctx, group, request.UserId, request.UserId); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, group.Id, auth.ExtractUser(ctx), models.AuditActionApproveJoin, request.UserId, nil, nil); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *GroupService) RejectJoinRequest(ctx context.Context, requestId int) (err *errors.Error) {
+	request, err := s.getJoinRequest(ctx, requestId, "reject join request")
+	if err != nil {
+		return err.Trace()
+	}
+
+	ctx, err = db.WithTx(ctx, s.invitesRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.invitesRepo.DeleteJoinRequest(ctx, request.Id); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, request.GroupId, auth.ExtractUser(ctx), models.AuditActionRejectJoin, request.UserId, nil, nil); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *GroupService) getJoinRequest(ctx context.Context, requestId int, action string) (*models.JoinRequest, *errors.Error) {
+	if requestId == 0 {
+		return nil, errors.New1Msg("missing request id", http.StatusBadRequest)
+	}
+	request, err := s.invitesRepo.GetJoinRequestById(ctx, requestId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, request.GroupId, models.PermManageInvites, action); err != nil {
+		return nil, err.Trace()
+	}
+	return request, nil
+}
+
+func newInviteToken() (string, error) {
+	b := make([]byte, inviteTokenLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,158 @@
+package groups
+
+import (
+	"context"
+	"messanger/domain/models"
+	"messanger/domain/service/auth"
+	"messanger/pkg/db"
+	"messanger/pkg/errors"
+	"net/http"
+)
+
+// Groups and channels with username are public: they can be found in directory and joined by anyone.
+
+const maxSearchLimit = 50
+
+func (s *GroupService) NewChannel(ctx context.Context, dto *CreateChannelDTO) (*models.Group, *errors.Error) {
+	channel := &models.Group{
+		Name:           dto.Name,
+		Description:    dto.Description,
+		Username:       dto.Username,
+		OnlyAdminsPost: true,
+	}
+	if err := s.newGroup(ctx, models.ChatTypeChannel, channel); err != nil {
+		return nil, err.Trace()
+	}
+	return channel, nil
+}
+
+// CheckUsername reports whether username is taken by group or channel
+func (s *GroupService) CheckUsername(ctx context.Context, username string) (bool, *errors.Error) {
+	if len(username) == 0 {
+		return false, errors.New1Msg("missing username", http.StatusBadRequest)
+	}
+	if !models.ValidateGroupUsername(username) {
+		return false, errors.New1Msg("invalid username", http.StatusBadRequest)
+	}
+	if _, err := s.groupsRepo.GetByUsername(ctx, username); err != nil {
+		if err.Code == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err.Trace()
+	}
+	return true, nil
+}
+
+// SetUsername sets public username of group, empty username makes group private
+func (s *GroupService) SetUsername(ctx context.Context, groupId int, username string) (err *errors.Error) {
+	if len(username) != 0 && !models.ValidateGroupUsername(username) {
+		return errors.New1Msg("invalid username", http.StatusBadRequest)
+	}
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermEditInfo, "set username"); err != nil {
+		return err.Trace()
+	}
+	group, err := s.groupsRepo.GetById(ctx, groupId)
+	if err != nil {
+		return err.Trace()
+	}
+
+	ctx, err = db.WithTx(ctx, s.groupsRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.groupsRepo.SetUsername(ctx, group.Id, username); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, group.Id, actionerId, models.AuditActionUsername, 0,
+		map[string]string{"username": group.Username}, map[string]string{"username": username}); err != nil {
+		return err.Trace()
+	}
+	group.Username = username
+	s.onGroupUpdated(group)
+	return nil
+}
+
+// FindPublic returns public group or channel by username
+func (s *GroupService) FindPublic(ctx context.Context, username string) (*models.PublicGroup, *errors.Error) {
+	group, err := s.GetPublic(ctx, username)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	count, err := s.chatsRepo.CountUsersInChat(ctx, group.ChatId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	chat, err := s.chatsRepo.GetById(ctx, group.ChatId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return &models.PublicGroup{
+		Group:   group,
+		Type:    chat.Type,
+		Members: count,
+	}, nil
+}
+
+// Search searches public groups and channels by name or username
+func (s *GroupService) Search(ctx context.Context, query string, limit int) ([]models.PublicGroup, *errors.Error) {
+	if len(query) == 0 {
+		return nil, errors.New1Msg("missing query", http.StatusBadRequest)
+	}
+	if limit <= 0 || limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	groups, err := s.groupsRepo.SearchPublic(ctx, query, limit)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return groups, nil
+}
+
+// JoinPublic adds current user to public group or channel, private ones can be joined by invite link
+func (s *GroupService) JoinPublic(ctx context.Context, username string) (group *models.Group, err *errors.Error) {
+	group, err = s.GetPublic(ctx, username)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	userId := auth.ExtractUser(ctx)
+
+	var message *models.Message
+	defer func() {
+		if err == nil {
+			s.onMemberAdded(group.ChatId, userId)
+			s.onMessageCreated(message)
+		}
+	}()
+	ctx, err = db.WithTx(ctx, s.chatsRepo)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if message, err = s.addMember(ctx, group, userId, userId); err != nil {
+		return nil, err.Trace()
+	}
+	return group, nil
+}
+
+func (s *GroupService) Leave(ctx context.Context, groupId int) *errors.Error {
+	if err := s.RemoveUserFromGroup(ctx, groupId, auth.ExtractUser(ctx)); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// GetPublic returns group or channel by username
+func (s *GroupService) GetPublic(ctx context.Context, username string) (*models.Group, *errors.Error) {
+	if len(username) == 0 {
+		return nil, errors.New1Msg("username is missing", http.StatusBadRequest)
+	}
+	group, err := s.groupsRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return group, nil
+}
@@ -0,0 +1,244 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"messanger/domain/models"
+	"messanger/domain/service/auth"
+	"messanger/pkg/db"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+// BanUser removes user from group and blocks adding the user back and joining by invite link
+func (s *GroupService) BanUser(ctx context.Context, dto *RestrictionDTO) (restriction *models.Restriction, err *errors.Error) {
+	restriction, err = s.newRestriction(ctx, models.RestrictionBan, dto)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	group, err := s.groupsRepo.GetById(ctx, dto.GroupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	member, err := s.groupsRepo.CheckUserInGroup(ctx, dto.UserId, dto.GroupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+
+	var message *models.Message
+	defer func() {
+		if err == nil && member {
+			s.onMemberRemoved(group.ChatId, dto.UserId)
+			s.onMessageCreated(message)
+		}
+	}()
+	ctx, err = db.WithTx(ctx, s.restrictionsRepo)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.restrictionsRepo.Set(ctx, restriction); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.audit(ctx, dto.GroupId, restriction.ActorId, models.AuditActionBan, dto.UserId, nil, restriction); err != nil {
+		return nil, err.Trace()
+	}
+	if !member {
+		return restriction, nil
+	}
+
+	if err := s.chatsRepo.RemoveUserFromChat(ctx, group.ChatId, dto.UserId); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.groupsRepo.DeletePermissionOverride(ctx, dto.UserId, dto.GroupId); err != nil {
+		return nil, err.Trace()
+	}
+	if message, err = s.addSystemMessage(ctx, group, &models.SystemPayload{
+		Action:  models.SystemActionBan,
+		ActorId: restriction.ActorId,
+		UserId:  dto.UserId,
+	}); err != nil {
+		return nil, err.Trace()
+	}
+	return restriction, nil
+}
+
+// MuteUser forbids member to post messages in group until restriction expires
+func (s *GroupService) MuteUser(ctx context.Context, dto *RestrictionDTO) (restriction *models.Restriction, err *errors.Error) {
+	restriction, err = s.newRestriction(ctx, models.RestrictionMute, dto)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	member, err := s.groupsRepo.CheckUserInGroup(ctx, dto.UserId, dto.GroupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if !member {
+		return nil, errors.New1Msg("user is not a group member", http.StatusBadRequest)
+	}
+
+	ctx, err = db.WithTx(ctx, s.restrictionsRepo)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.restrictionsRepo.Set(ctx, restriction); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.audit(ctx, dto.GroupId, restriction.ActorId, models.AuditActionMute, dto.UserId, nil, restriction); err != nil {
+		return nil, err.Trace()
+	}
+	return restriction, nil
+}
+
+func (s *GroupService) UnbanUser(ctx context.Context, groupId int, userId int) *errors.Error {
+	if err := s.deleteRestriction(ctx, models.RestrictionBan, models.AuditActionUnban, groupId, userId); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *GroupService) UnmuteUser(ctx context.Context, groupId int, userId int) *errors.Error {
+	if err := s.deleteRestriction(ctx, models.RestrictionMute, models.AuditActionUnmute, groupId, userId); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *GroupService) GetBans(ctx context.Context, groupId int) ([]models.Restriction, *errors.Error) {
+	bans, err := s.getRestrictions(ctx, models.RestrictionBan, groupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return bans, nil
+}
+
+func (s *GroupService) GetMutes(ctx context.Context, groupId int) ([]models.Restriction, *errors.Error) {
+	mutes, err := s.getRestrictions(ctx, models.RestrictionMute, groupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return mutes, nil
+}
+
+// newRestriction validates dto and checks that current user can restrict the user
+func (s *GroupService) newRestriction(ctx context.Context, restrictionType string, dto *RestrictionDTO) (*models.Restriction, *errors.Error) {
+	if dto.UserId == 0 {
+		return nil, errors.New1Msg("userId is missing", http.StatusBadRequest)
+	}
+	if dto.DurationSec < 0 {
+		return nil, errors.New1Msg("invalid duration", http.StatusBadRequest)
+	}
+	if !models.ValidateRestrictionReason(dto.Reason) {
+		return nil, errors.New1Msg("reason is too long", http.StatusBadRequest)
+	}
+
+	actionerId := auth.ExtractUser(ctx)
+	if actionerId == dto.UserId {
+		return nil, errors.New1Msg(fmt.Sprintf("can't %s yourself", restrictionType), http.StatusBadRequest)
+	}
+	if err := s.perms.Check(ctx, actionerId, dto.GroupId, models.PermRemoveMembers, restrictionType+" user"); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.checkCanRestrict(ctx, actionerId, dto.UserId, dto.GroupId); err != nil {
+		return nil, err.Trace()
+	}
+
+	restriction := &models.Restriction{
+		GroupId: dto.GroupId,
+		UserId:  dto.UserId,
+		ActorId: actionerId,
+		Type:    restrictionType,
+		Reason:  dto.Reason,
+	}
+	if dto.DurationSec != 0 {
+		expireTime := time.Now().Add(time.Duration(dto.DurationSec) * time.Second)
+		restriction.ExpireTime = &expireTime
+	}
+	return restriction, nil
+}
+
+// checkCanRestrict checks that actioner can ban or mute user: owner can't be restricted, admins can be restricted only by owner
+func (s *GroupService) checkCanRestrict(ctx context.Context, actionerId int, userId int, groupId int) *errors.Error {
+	userRole, err := s.perms.GetRole(ctx, userId, groupId)
+	if err != nil {
+		return err.Trace()
+	}
+	if userRole == models.RoleOwner {
+		return errors.New(fmt.Sprintf("user (%d) tried restrict owner (%d) of group (%d)", actionerId, userId, groupId),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+	if userRole == models.RoleAdmin {
+		actionerRole, err := s.perms.GetRole(ctx, actionerId, groupId)
+		if err != nil {
+			return err.Trace()
+		}
+		if actionerRole != models.RoleOwner {
+			return errors.New(fmt.Sprintf("user (%d) tried restrict admin (%d) of group (%d)", actionerId, userId, groupId),
+				models.ErrPermissionDenied, http.StatusForbidden)
+		}
+	}
+	return nil
+}
+
+func (s *GroupService) deleteRestriction(ctx context.Context, restrictionType string, auditAction string, groupId int, userId int) (err *errors.Error) {
+	if userId == 0 {
+		return errors.New1Msg("userId is missing", http.StatusBadRequest)
+	}
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermRemoveMembers, "un"+restrictionType+" user"); err != nil {
+		return err.Trace()
+	}
+	before, err := s.restrictionsRepo.Get(ctx, groupId, userId, restrictionType)
+	if err != nil {
+		return err.Trace()
+	}
+
+	ctx, err = db.WithTx(ctx, s.restrictionsRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.restrictionsRepo.Delete(ctx, groupId, userId, restrictionType); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, groupId, actionerId, auditAction, userId, before, nil); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *GroupService) getRestrictions(ctx context.Context, restrictionType string, groupId int) ([]models.Restriction, *errors.Error) {
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermRemoveMembers, "get "+restrictionType+"s"); err != nil {
+		return nil, err.Trace()
+	}
+	restrictions, err := s.restrictionsRepo.GetByGroup(ctx, groupId, restrictionType)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return restrictions, nil
+}
+
+// checkNotBanned returns forbidden error if user has active ban in group
+func (s *GroupService) checkNotBanned(ctx context.Context, groupId int, userId int) *errors.Error {
+	if _, err := s.restrictionsRepo.Get(ctx, groupId, userId, models.RestrictionBan); err != nil {
+		if err.Code != http.StatusNotFound {
+			return err.Trace()
+		}
+		return nil
+	}
+	return errors.New1Msg("user is banned in this group", http.StatusForbidden)
+}
+
+// removeExpiredRestrictions deletes expired bans and mutes, they are already ignored by queries
+func (s *GroupService) removeExpiredRestrictions() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s.restrictionsRepo.DeleteExpired(ctx)
+}
@@ -0,0 +1,212 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"messanger/domain/models"
+	"messanger/domain/service/auth"
+	"messanger/pkg/db"
+	"messanger/pkg/errors"
+	"net/http"
+)
+
+var builtinRoles = []string{models.RoleOwner, models.RoleAdmin, models.RoleMember}
+
+// GetRoles returns built-in and custom roles of group
+func (s *GroupService) GetRoles(ctx context.Context, groupId int) ([]models.GroupRole, *errors.Error) {
+	if groupId == 0 {
+		return nil, errors.New1Msg("groupId is missing", http.StatusBadRequest)
+	}
+	actionerId := auth.ExtractUser(ctx)
+	exist, err := s.groupsRepo.CheckUserInGroup(ctx, actionerId, groupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if !exist {
+		return nil, errors.New(fmt.Sprintf("user (%d) tried get roles of group (%d)", actionerId, groupId),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+
+	customRoles, err := s.groupsRepo.GetCustomRoles(ctx, groupId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	roles := make([]models.GroupRole, 0, len(builtinRoles)+len(customRoles))
+	for _, name := range builtinRoles {
+		perms, _ := models.BuiltinRolePermissions(name)
+		roles = append(roles, models.GroupRole{
+			GroupId:     groupId,
+			Name:        name,
+			Permissions: perms,
+		})
+	}
+	return append(roles, customRoles...), nil
+}
+
+func (s *GroupService) CreateRole(ctx context.Context, dto *RoleDTO) (role *models.GroupRole, err *errors.Error) {
+	if !models.ValidateRoleName(dto.Name) {
+		return nil, errors.New1Msg("invalid role name", http.StatusBadRequest)
+	}
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, dto.GroupId, models.PermManageRoles, "create role"); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.checkGrantable(ctx, actionerId, dto.GroupId, dto.Permissions); err != nil {
+		return nil, err.Trace()
+	}
+
+	role = &models.GroupRole{
+		GroupId:     dto.GroupId,
+		Name:        dto.Name,
+		Permissions: dto.Permissions,
+	}
+
+	ctx, err = db.WithTx(ctx, s.groupsRepo)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.groupsRepo.NewCustomRole(ctx, role); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.audit(ctx, dto.GroupId, actionerId, models.AuditActionCreateRole, 0, nil, role); err != nil {
+		return nil, err.Trace()
+	}
+	return role, nil
+}
+
+func (s *GroupService) UpdateRole(ctx context.Context, dto *RoleDTO) (err *errors.Error) {
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, dto.GroupId, models.PermManageRoles, "update role"); err != nil {
+		return err.Trace()
+	}
+	role, err := s.groupsRepo.GetCustomRole(ctx, dto.GroupId, dto.Name)
+	if err != nil {
+		return err.Trace()
+	}
+	if err := s.checkGrantable(ctx, actionerId, dto.GroupId, role.Permissions|dto.Permissions); err != nil {
+		return err.Trace()
+	}
+
+	before := *role
+	role.Permissions = dto.Permissions
+
+	ctx, err = db.WithTx(ctx, s.groupsRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.groupsRepo.UpdateCustomRole(ctx, role); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, dto.GroupId, actionerId, models.AuditActionUpdateRole, 0, before, role); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *GroupService) DeleteRole(ctx context.Context, groupId int, name string) (err *errors.Error) {
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, groupId, models.PermManageRoles, "delete role"); err != nil {
+		return err.Trace()
+	}
+	role, err := s.groupsRepo.GetCustomRole(ctx, groupId, name)
+	if err != nil {
+		return err.Trace()
+	}
+	if err := s.checkGrantable(ctx, actionerId, groupId, role.Permissions); err != nil {
+		return err.Trace()
+	}
+	ctx, err = db.WithTx(ctx, s.groupsRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.groupsRepo.DeleteCustomRole(ctx, groupId, role.Name); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, groupId, actionerId, models.AuditActionDeleteRole, 0, role, nil); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// SetPermissionOverride sets permissions allowed and denied to member on top of member's role
+func (s *GroupService) SetPermissionOverride(ctx context.Context, dto *PermissionOverrideDTO) (err *errors.Error) {
+	if dto.UserId == 0 {
+		return errors.New1Msg("userId is missing", http.StatusBadRequest)
+	}
+	if dto.Allow&dto.Deny != 0 {
+		return errors.New1Msg("permission can't be allowed and denied at the same time", http.StatusBadRequest)
+	}
+	actionerId := auth.ExtractUser(ctx)
+	if err := s.perms.Check(ctx, actionerId, dto.GroupId, models.PermManageRoles, "set permissions"); err != nil {
+		return err.Trace()
+	}
+	exist, err := s.groupsRepo.CheckUserInGroup(ctx, dto.UserId, dto.GroupId)
+	if err != nil {
+		return err.Trace()
+	}
+	if !exist {
+		return errors.New1Msg("user is not in group", http.StatusBadRequest)
+	}
+	if err := s.checkCanManage(ctx, actionerId, dto.UserId, dto.GroupId); err != nil {
+		return err.Trace()
+	}
+	if err := s.checkGrantable(ctx, actionerId, dto.GroupId, dto.Allow|dto.Deny); err != nil {
+		return err.Trace()
+	}
+
+	before, err := s.groupsRepo.GetPermissionOverride(ctx, dto.UserId, dto.GroupId)
+	if err != nil {
+		return err.Trace()
+	}
+	override := &models.PermissionOverride{
+		GroupId: dto.GroupId,
+		UserId:  dto.UserId,
+		Allow:   dto.Allow,
+		Deny:    dto.Deny,
+	}
+
+	ctx, err = db.WithTx(ctx, s.groupsRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.groupsRepo.SetPermissionOverride(ctx, override); err != nil {
+		return err.Trace()
+	}
+	if err := s.audit(ctx, dto.GroupId, actionerId, models.AuditActionSetPermissions, dto.UserId, before, override); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// GetPermissions returns effective permissions of current user in group
+func (s *GroupService) GetPermissions(ctx context.Context, groupId int) (models.Permissions, *errors.Error) {
+	if groupId == 0 {
+		return 0, errors.New1Msg("groupId is missing", http.StatusBadRequest)
+	}
+	perms, err := s.perms.Get(ctx, auth.ExtractUser(ctx), groupId)
+	if err != nil {
+		return 0, err.Trace()
+	}
+	return perms, nil
+}
+
+// checkGrantable checks that actioner has all perms, so members can't grant permissions they don't have
+func (s *GroupService) checkGrantable(ctx context.Context, actionerId int, groupId int, perms models.Permissions) *errors.Error {
+	actionerPerms, err := s.perms.Get(ctx, actionerId, groupId)
+	if err != nil {
+		return err.Trace()
+	}
+	if !actionerPerms.Has(perms) {
+		return errors.New(fmt.Sprintf("user (%d) tried grant permissions %s in group (%d)", actionerId, perms&^actionerPerms, groupId),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+	return nil
+}
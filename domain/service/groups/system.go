@@ -0,0 +1,46 @@
+package groups
+
+import (
+	"context"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"time"
+)
+
+// addSystemMessage writes system message into group timeline, it must be pushed to online members
+// by onMessageCreated after transaction commit.
+// Channels get only rename messages, so subscribers timeline is not flooded by membership changes,
+// nil message is returned for others.
+func (s *GroupService) addSystemMessage(ctx context.Context, group *models.Group, payload *models.SystemPayload) (*models.Message, *errors.Error) {
+	if payload.Action != models.SystemActionRename {
+		chat, err := s.chatsRepo.GetById(ctx, group.ChatId)
+		if err != nil {
+			return nil, err.Trace()
+		}
+		if chat.Type == models.ChatTypeChannel {
+			return nil, nil
+		}
+	}
+
+	message := &models.Message{
+		ChatId:  group.ChatId,
+		UserId:  payload.ActorId,
+		Time:    time.Now(),
+		Kind:    models.MessageKindSystem,
+		Payload: payload,
+	}
+	if err := s.messagesRepo.New(ctx, message); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.chatsRepo.UpdateTime(ctx, message.ChatId, message.Time); err != nil {
+		return nil, err.Trace()
+	}
+	return message, nil
+}
+
+// onMessageCreated must be called after transaction commit, so members don't get messages which are not saved
+func (s *GroupService) onMessageCreated(message *models.Message) {
+	if message != nil && s.events != nil {
+		go s.events.OnMessageCreated(message)
+	}
+}
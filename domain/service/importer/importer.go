@@ -0,0 +1,439 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"messanger/config"
+	"messanger/domain/models"
+	"messanger/domain/ports"
+	"messanger/domain/service/auth"
+	"messanger/pkg/db"
+	"messanger/pkg/errors"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const defaultBatchSize = 500
+
+type ImportService struct {
+	importsRepo  ports.ImportsRepo
+	usersRepo    ports.UsersRepo
+	chatsRepo    ports.ChatsRepo
+	groupsRepo   ports.GroupsRepo
+	messagesRepo ports.MessagesRepo
+
+	adminIds  []int
+	batchSize int
+}
+
+func NewImportService(
+	importsRepo ports.ImportsRepo,
+	usersRepo ports.UsersRepo,
+	chatsRepo ports.ChatsRepo,
+	groupsRepo ports.GroupsRepo,
+	messagesRepo ports.MessagesRepo,
+	cfg *config.ImportConfig,
+) *ImportService {
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &ImportService{
+		importsRepo:  importsRepo,
+		usersRepo:    usersRepo,
+		chatsRepo:    chatsRepo,
+		groupsRepo:   groupsRepo,
+		messagesRepo: messagesRepo,
+		adminIds:     cfg.AdminIds,
+		batchSize:    batchSize,
+	}
+}
+
+func (s *ImportService) Import(ctx context.Context, data *models.ImportData) (*models.ImportReport, *errors.Error) {
+	actionerId := auth.ExtractUser(ctx)
+	if !slices.Contains(s.adminIds, actionerId) {
+		return nil, errors.New(fmt.Sprintf("user (%d) tried to import history", actionerId),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+
+	report := &models.ImportReport{
+		Skipped:   make([]models.ImportRecord, 0),
+		Conflicts: make([]models.ImportRecord, 0),
+		Failed:    make([]models.ImportRecord, 0),
+	}
+
+	seenChats := make(map[string]bool)
+	for i := range data.Chats {
+		chat := &data.Chats[i]
+		if len(chat.Id) == 0 {
+			report.Skipped = append(report.Skipped, models.ImportRecord{
+				Kind:   models.ImportRecordChat,
+				Reason: "chat id is missing",
+			})
+			continue
+		}
+		if seenChats[chat.Id] {
+			report.Conflicts = append(report.Conflicts, models.ImportRecord{
+				Kind:   models.ImportRecordChat,
+				ChatId: chat.Id,
+				Reason: "duplicate chat id",
+			})
+			continue
+		}
+		seenChats[chat.Id] = true
+
+		if err := s.importChat(ctx, chat, report); err != nil {
+			report.Failed = append(report.Failed, models.ImportRecord{
+				Kind:   models.ImportRecordChat,
+				ChatId: chat.Id,
+				Reason: err.UserMessage,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+type importMember struct {
+	userId int
+	role   string
+}
+
+func (s *ImportService) importChat(ctx context.Context, data *models.ImportChat, report *models.ImportReport) *errors.Error {
+	members, err := s.mapMembers(ctx, data, report)
+	if err != nil {
+		return err.Trace()
+	}
+
+	skipChat := func(reason string) {
+		report.Skipped = append(report.Skipped, models.ImportRecord{
+			Kind:   models.ImportRecordChat,
+			ChatId: data.Id,
+			Reason: reason,
+		})
+	}
+
+	switch data.Type {
+	case models.ChatTypeUser:
+		if len(members) != 2 {
+			skipChat(fmt.Sprintf("user chat must have 2 known members, found %d", len(members)))
+			return nil
+		}
+	case models.ChatTypeGroup:
+		if len(data.Name) == 0 {
+			skipChat("group name is missing")
+			return nil
+		}
+		if len(members) == 0 {
+			skipChat("no known members")
+			return nil
+		}
+		s.setOwner(ctx, data, members)
+	default:
+		skipChat("invalid chat type: " + data.Type)
+		return nil
+	}
+
+	conflict := func(reason string) {
+		report.Conflicts = append(report.Conflicts, models.ImportRecord{
+			Kind:   models.ImportRecordChat,
+			ChatId: data.Id,
+			Reason: reason,
+		})
+	}
+
+	if data.Type == models.ChatTypeUser {
+		exists, err := s.userChatExists(ctx, members)
+		if err != nil {
+			return err.Trace()
+		}
+		if exists {
+			conflict("user chat already exists")
+			return nil
+		}
+	}
+
+	messages := s.mapMessages(data, members, report)
+
+	chat, err := s.createChat(ctx, data, members)
+	if err != nil {
+		if err.Code == http.StatusConflict {
+			conflict(err.UserMessage)
+			return nil
+		}
+		return err.Trace()
+	}
+
+	imported := 0
+	for start := 0; start < len(messages); start += s.batchSize {
+		end := min(start+s.batchSize, len(messages))
+		for i := start; i < end; i++ {
+			messages[i].ChatId = chat.Id
+		}
+		if err := s.saveMessages(ctx, chat.Id, messages[start:end]); err != nil {
+			// chat is removed with its messages so it can be imported again
+			if e := s.chatsRepo.Delete(ctx, chat.Id); e != nil {
+				return errors.New(err, fmt.Sprintf("import interrupted, chat (%d) has %d of %d messages: %s",
+					chat.Id, imported, len(messages), err.UserMessage), err.Code)
+			}
+			return err.Trace()
+		}
+		imported += end - start
+	}
+
+	report.ChatsCreated++
+	report.MessagesImported += imported
+	return nil
+}
+
+// userChatExists checks if both members of user chat already have chat with each other
+func (s *ImportService) userChatExists(ctx context.Context, members map[string]importMember) (bool, *errors.Error) {
+	userIds := make([]int, 0, len(members))
+	for _, member := range members {
+		userIds = append(userIds, member.userId)
+	}
+
+	chats, err := s.chatsRepo.GetByUserId(ctx, userIds[0])
+	if err != nil {
+		return false, err.Trace()
+	}
+	for _, chat := range chats {
+		if chat.Type != models.ChatTypeUser {
+			continue
+		}
+		companionId, err := s.chatsRepo.GetUserCompanionByChatId(ctx, userIds[0], chat.Id)
+		if err != nil {
+			return false, err.Trace()
+		}
+		if companionId == userIds[1] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *ImportService) mapMembers(ctx context.Context, data *models.ImportChat, report *models.ImportReport) (map[string]importMember, *errors.Error) {
+	members := make(map[string]importMember, len(data.Members))
+	users := make(map[int]string, len(data.Members))
+
+	skip := func(m *models.ImportMember, reason string) {
+		report.Skipped = append(report.Skipped, models.ImportRecord{
+			Kind:   models.ImportRecordMember,
+			ChatId: data.Id,
+			Id:     m.Id,
+			Reason: reason,
+		})
+	}
+
+	for i := range data.Members {
+		m := &data.Members[i]
+		if _, ok := members[m.Id]; ok {
+			report.Conflicts = append(report.Conflicts, models.ImportRecord{
+				Kind:   models.ImportRecordMember,
+				ChatId: data.Id,
+				Id:     m.Id,
+				Reason: "duplicate member id",
+			})
+			continue
+		}
+
+		phone, e := models.ParsePhone(m.Phone)
+		if e != nil {
+			skip(m, "invalid phone number")
+			continue
+		}
+		user, err := s.usersRepo.FindByPhone(ctx, phone)
+		if err != nil {
+			if err.Code != http.StatusNotFound {
+				return nil, err.Trace()
+			}
+			skip(m, "user not found")
+			continue
+		}
+		if externalId, ok := users[user.Id]; ok {
+			report.Conflicts = append(report.Conflicts, models.ImportRecord{
+				Kind:   models.ImportRecordMember,
+				ChatId: data.Id,
+				Id:     m.Id,
+				Reason: fmt.Sprintf("same user as member %s", externalId),
+			})
+			continue
+		}
+
+		role := m.Role
+		if len(role) == 0 {
+			role = models.RoleMember
+		}
+		if role != models.RoleOwner && !models.ValidateRole(role) {
+			skip(m, "invalid role: "+role)
+			continue
+		}
+
+		members[m.Id] = importMember{userId: user.Id, role: role}
+		users[user.Id] = m.Id
+	}
+	return members, nil
+}
+
+// setOwner leaves exactly one owner of imported group: the first member with owner role,
+// otherwise the importing admin if they are a member, the first admin or the first member
+func (s *ImportService) setOwner(ctx context.Context, data *models.ImportChat, members map[string]importMember) {
+	ordered := make([]string, 0, len(members))
+	for i := range data.Members {
+		id := data.Members[i].Id
+		if _, ok := members[id]; ok && !slices.Contains(ordered, id) {
+			ordered = append(ordered, id)
+		}
+	}
+
+	owner := ""
+	for _, id := range ordered {
+		if members[id].role != models.RoleOwner {
+			continue
+		}
+		if len(owner) == 0 {
+			owner = id
+			continue
+		}
+		m := members[id]
+		m.role = models.RoleAdmin
+		members[id] = m
+	}
+	if len(owner) == 0 {
+		actionerId := auth.ExtractUser(ctx)
+		for _, id := range ordered {
+			if members[id].userId == actionerId {
+				owner = id
+				break
+			}
+		}
+	}
+	if len(owner) == 0 {
+		for _, id := range ordered {
+			if members[id].role == models.RoleAdmin {
+				owner = id
+				break
+			}
+		}
+	}
+	if len(owner) == 0 {
+		owner = ordered[0]
+	}
+
+	m := members[owner]
+	m.role = models.RoleOwner
+	members[owner] = m
+}
+
+func (s *ImportService) mapMessages(data *models.ImportChat, members map[string]importMember, report *models.ImportReport) []models.Message {
+	messages := make([]models.Message, 0, len(data.Messages))
+	seen := make(map[string]bool, len(data.Messages))
+
+	skip := func(m *models.ImportMessage, reason string) {
+		report.Skipped = append(report.Skipped, models.ImportRecord{
+			Kind:   models.ImportRecordMessage,
+			ChatId: data.Id,
+			Id:     m.Id,
+			Reason: reason,
+		})
+	}
+
+	for i := range data.Messages {
+		m := &data.Messages[i]
+		if len(m.Id) != 0 {
+			if seen[m.Id] {
+				report.Conflicts = append(report.Conflicts, models.ImportRecord{
+					Kind:   models.ImportRecordMessage,
+					ChatId: data.Id,
+					Id:     m.Id,
+					Reason: "duplicate message id",
+				})
+				continue
+			}
+			seen[m.Id] = true
+		}
+
+		member, ok := members[m.From]
+		if !ok {
+			skip(m, "unknown sender: "+m.From)
+			continue
+		}
+		if len(m.Text) == 0 {
+			skip(m, "empty text")
+			continue
+		}
+		if m.Time.IsZero() {
+			skip(m, "missing time")
+			continue
+		}
+
+		messages = append(messages, models.Message{
+			UserId: member.userId,
+			Text:   m.Text,
+			Time:   m.Time,
+		})
+	}
+
+	slices.SortStableFunc(messages, func(m1, m2 models.Message) int {
+		return m1.Time.Compare(m2.Time)
+	})
+	return messages
+}
+
+func (s *ImportService) createChat(ctx context.Context, data *models.ImportChat, members map[string]importMember) (chat *models.Chat, err *errors.Error) {
+	chat = &models.Chat{Type: data.Type}
+
+	ctx, err = db.WithTx(ctx, s.chatsRepo)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.chatsRepo.New(ctx, chat); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.importsRepo.New(ctx, data.Id, chat.Id); err != nil {
+		return nil, err.Trace()
+	}
+
+	var group *models.Group
+	if data.Type == models.ChatTypeGroup {
+		group = &models.Group{ChatId: chat.Id, Name: data.Name}
+		if err := s.groupsRepo.New(ctx, group); err != nil {
+			return nil, err.Trace()
+		}
+	}
+
+	for _, member := range members {
+		if err := s.chatsRepo.AddUserToChat(ctx, chat.Id, member.userId); err != nil {
+			return nil, err.Trace()
+		}
+		if group != nil {
+			if err := s.groupsRepo.SetRole(ctx, member.userId, group.Id, member.role); err != nil {
+				return nil, err.Trace()
+			}
+		}
+	}
+	return chat, nil
+}
+
+func (s *ImportService) saveMessages(ctx context.Context, chatId int, messages []models.Message) (err *errors.Error) {
+	ctx, err = db.WithTx(ctx, s.messagesRepo)
+	if err != nil {
+		return err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	var lastTime time.Time
+	for i := range messages {
+		if err := s.messagesRepo.New(ctx, &messages[i]); err != nil {
+			return err.Trace()
+		}
+		lastTime = messages[i].Time
+	}
+	if err := s.chatsRepo.UpdateTime(ctx, chatId, lastTime); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package importer
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/config"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	adminId = 1
+	userId  = 2
+	chatId  = 10
+	groupId = 20
+)
+
+var phones = map[int]string{
+	adminId: "+79990000001",
+	userId:  "+79990000002",
+}
+
+type testRepos struct {
+	imports  *mocks.ImportsRepo
+	users    *mocks.UsersRepo
+	chats    *mocks.ChatsRepo
+	groups   *mocks.GroupsRepo
+	messages *mocks.MessagesRepo
+}
+
+func newTestImportService(t *testing.T) (*ImportService, *testRepos) {
+	r := &testRepos{
+		imports:  mocks.NewImportsRepo(t),
+		users:    mocks.NewUsersRepo(t),
+		chats:    mocks.NewChatsRepo(t),
+		groups:   mocks.NewGroupsRepo(t),
+		messages: mocks.NewMessagesRepo(t),
+	}
+	r.users.On("FindByPhone", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, phone string) (*models.User, *errors.Error) {
+			for id, p := range phones {
+				if p == phone {
+					return &models.User{Id: id, Phone: phone}, nil
+				}
+			}
+			return nil, errors.New1Msg("user not found", http.StatusNotFound)
+		}).Maybe()
+
+	s := NewImportService(r.imports, r.users, r.chats, r.groups, r.messages, &config.ImportConfig{
+		AdminIds:  []int{adminId},
+		BatchSize: 2,
+	})
+	return s, r
+}
+
+func expectChat(r *testRepos, chatType string) {
+	r.chats.On("New", mock.Anything, mock.Anything).Return(func(_ context.Context, chat *models.Chat) *errors.Error {
+		chat.Id = chatId
+		return nil
+	}).Once()
+	r.chats.On("AddUserToChat", mock.Anything, chatId, mock.Anything).Return(nil)
+	if chatType == models.ChatTypeGroup {
+		r.groups.On("New", mock.Anything, mock.Anything).Return(func(_ context.Context, group *models.Group) *errors.Error {
+			group.Id = groupId
+			return nil
+		}).Once()
+	}
+}
+
+func TestImportPermission(t *testing.T) {
+	s, _ := newTestImportService(t)
+	_, err := s.Import(auth.CtxWithUser(context.Background(), userId), &models.ImportData{})
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+}
+
+func TestImportGroup(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), adminId)
+	s, r := newTestImportService(t)
+
+	expectChat(r, models.ChatTypeGroup)
+	r.imports.On("New", mock.Anything, "c1", chatId).Return(nil).Once()
+	r.groups.On("SetRole", mock.Anything, adminId, groupId, models.RoleOwner).Return(nil).Once()
+	r.groups.On("SetRole", mock.Anything, userId, groupId, models.RoleMember).Return(nil).Once()
+
+	now := time.Now()
+	var saved []models.Message
+	r.messages.On("New", mock.Anything, mock.Anything).Return(func(_ context.Context, m *models.Message) *errors.Error {
+		saved = append(saved, *m)
+		return nil
+	})
+	r.chats.On("UpdateTime", mock.Anything, chatId, mock.Anything).Return(nil).Twice()
+
+	report, err := s.Import(ctx, &models.ImportData{Chats: []models.ImportChat{
+		{
+			Id:   "c1",
+			Type: models.ChatTypeGroup,
+			Name: "Team",
+			Members: []models.ImportMember{
+				{Id: "u1", Phone: phones[adminId]},
+				{Id: "u2", Phone: phones[userId]},
+				{Id: "u3", Phone: "+79990000003"},
+			},
+			Messages: []models.ImportMessage{
+				{Id: "m1", From: "u2", Text: "second", Time: now},
+				{Id: "m2", From: "u1", Text: "first", Time: now.Add(-time.Minute)},
+				{Id: "m3", From: "u3", Text: "unknown", Time: now},
+				{Id: "m1", From: "u2", Text: "duplicate", Time: now},
+				{Id: "m4", From: "u1", Text: "third", Time: now.Add(time.Minute)},
+			},
+		},
+		{Id: "c1", Type: models.ChatTypeGroup, Name: "Again"},
+		{Id: "c2", Type: models.ChatTypeGroup, Name: "Empty", Members: []models.ImportMember{{Id: "u3", Phone: "+79990000003"}}},
+	}})
+	require.Nil(t, err)
+	require.Equal(t, 1, report.ChatsCreated)
+	require.Equal(t, 3, report.MessagesImported)
+	require.Empty(t, report.Failed)
+	require.Len(t, report.Conflicts, 2, "duplicate message and chat")
+	require.Len(t, report.Skipped, 4, "unknown members of c1 and c2, message of unknown member, chat without members")
+
+	require.Len(t, saved, 3)
+	require.Equal(t, "first", saved[0].Text)
+	require.Equal(t, "second", saved[1].Text)
+	require.Equal(t, "third", saved[2].Text)
+	for _, m := range saved {
+		require.Equal(t, chatId, m.ChatId)
+	}
+}
+
+func TestImportConflicts(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), adminId)
+	s, r := newTestImportService(t)
+	members := []models.ImportMember{
+		{Id: "u1", Phone: phones[adminId]},
+		{Id: "u2", Phone: phones[userId]},
+	}
+
+	// group was imported before
+	r.chats.On("New", mock.Anything, mock.Anything).Return(func(_ context.Context, chat *models.Chat) *errors.Error {
+		chat.Id = chatId
+		return nil
+	}).Once()
+	r.imports.On("New", mock.Anything, "group", chatId).Return(errors.New1Msg("chat is already imported", http.StatusConflict)).Once()
+
+	// users already have a chat
+	r.chats.On("GetByUserId", mock.Anything, mock.Anything).Return([]models.Chat{
+		{Id: 5, Type: models.ChatTypeSaved},
+		{Id: 6, Type: models.ChatTypeUser},
+	}, nil).Once()
+	r.chats.On("GetUserCompanionByChatId", mock.Anything, mock.Anything, 6).Return(
+		func(_ context.Context, id int, _ int) (int, *errors.Error) {
+			if id == adminId {
+				return userId, nil
+			}
+			return adminId, nil
+		}).Once()
+
+	report, err := s.Import(ctx, &models.ImportData{Chats: []models.ImportChat{
+		{Id: "group", Type: models.ChatTypeGroup, Name: "Team", Members: members},
+		{Id: "user", Type: models.ChatTypeUser, Members: members},
+	}})
+	require.Nil(t, err)
+	require.Equal(t, 0, report.ChatsCreated)
+	require.Len(t, report.Conflicts, 2)
+	require.Equal(t, "group", report.Conflicts[0].ChatId)
+	require.Equal(t, "chat is already imported", report.Conflicts[0].Reason)
+	require.Equal(t, "user", report.Conflicts[1].ChatId)
+	require.Equal(t, "user chat already exists", report.Conflicts[1].Reason)
+}
+
+func TestImportFailed(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), adminId)
+	s, r := newTestImportService(t)
+
+	r.chats.On("GetByUserId", mock.Anything, mock.Anything).Return([]models.Chat{}, nil).Twice()
+	now := time.Now()
+	chat := models.ImportChat{
+		Type: models.ChatTypeUser,
+		Members: []models.ImportMember{
+			{Id: "u1", Phone: phones[adminId]},
+			{Id: "u2", Phone: phones[userId]},
+		},
+		Messages: []models.ImportMessage{
+			{From: "u1", Text: "1", Time: now},
+			{From: "u2", Text: "2", Time: now},
+			{From: "u1", Text: "3", Time: now},
+		},
+	}
+	first, second := chat, chat
+	first.Id, second.Id = "c1", "c2"
+
+	// second batch of the first chat fails, chat is removed
+	expectChat(r, models.ChatTypeUser)
+	r.imports.On("New", mock.Anything, "c1", chatId).Return(nil).Once()
+	r.messages.On("New", mock.Anything, mock.Anything).Return(nil).Twice()
+	r.chats.On("UpdateTime", mock.Anything, chatId, mock.Anything).Return(nil).Once()
+	r.messages.On("New", mock.Anything, mock.Anything).Return(errors.New1Msg(models.ErrDatabaseError, http.StatusInternalServerError)).Once()
+	r.chats.On("Delete", mock.Anything, chatId).Return(nil).Once()
+
+	// removing of the second chat fails too
+	expectChat(r, models.ChatTypeUser)
+	r.imports.On("New", mock.Anything, "c2", chatId).Return(nil).Once()
+	r.messages.On("New", mock.Anything, mock.Anything).Return(nil).Twice()
+	r.chats.On("UpdateTime", mock.Anything, chatId, mock.Anything).Return(nil).Once()
+	r.messages.On("New", mock.Anything, mock.Anything).Return(errors.New1Msg(models.ErrDatabaseError, http.StatusInternalServerError)).Once()
+	r.chats.On("Delete", mock.Anything, chatId).Return(errors.New1Msg(models.ErrDatabaseError, http.StatusInternalServerError)).Once()
+
+	report, err := s.Import(ctx, &models.ImportData{Chats: []models.ImportChat{first, second}})
+	require.Nil(t, err)
+	require.Equal(t, 0, report.ChatsCreated)
+	require.Equal(t, 0, report.MessagesImported)
+	require.Len(t, report.Failed, 2)
+	require.Equal(t, "c1", report.Failed[0].ChatId)
+	require.Equal(t, models.ErrDatabaseError, report.Failed[0].Reason)
+	require.Equal(t, "c2", report.Failed[1].ChatId)
+	require.Equal(t, "import interrupted, chat (10) has 2 of 3 messages: "+models.ErrDatabaseError, report.Failed[1].Reason)
+}
@@ -0,0 +1,59 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"messanger/domain/models"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+)
+
+func (s *MessagesService) AddBookmark(ctx context.Context, messageId int) (*models.Bookmark, *errors.Error) {
+	if messageId <= 0 {
+		return nil, errors.New1Msg("missing message id", http.StatusBadRequest)
+	}
+	userId := auth.ExtractUser(ctx)
+	m, err := s.repo.GetById(ctx, messageId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	ok, err := s.chatsRepo.CheckUserInChat(ctx, userId, m.ChatId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("user (%d) tried to bookmark a message (%d)", userId, messageId),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+
+	bookmark := &models.Bookmark{
+		UserId:    userId,
+		MessageId: messageId,
+	}
+	if err := s.bookmarksRepo.New(ctx, bookmark); err != nil {
+		return nil, err.Trace()
+	}
+	bookmark.Message = m
+	return bookmark, nil
+}
+
+func (s *MessagesService) GetBookmarks(ctx context.Context) ([]models.Bookmark, *errors.Error) {
+	userId := auth.ExtractUser(ctx)
+	bookmarks, err := s.bookmarksRepo.GetByUser(ctx, userId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return bookmarks, nil
+}
+
+func (s *MessagesService) RemoveBookmark(ctx context.Context, messageId int) *errors.Error {
+	if messageId <= 0 {
+		return errors.New1Msg("missing message id", http.StatusBadRequest)
+	}
+	userId := auth.ExtractUser(ctx)
+	if err := s.bookmarksRepo.Delete(ctx, userId, messageId); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package messages
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+	"testing"
+)
+
+func TestAddBookmark(t *testing.T) {
+	const userId, otherUserId, chatId, messageId = 1, 2, 3, 10
+	ctx := auth.CtxWithUser(context.Background(), userId)
+
+	message := &models.Message{Id: messageId, ChatId: chatId, UserId: otherUserId, Text: "hi"}
+	repo := mocks.NewMessagesRepo(t)
+	repo.On("GetById", mock.Anything, messageId).Return(message, nil)
+	repo.On("GetById", mock.Anything, mock.Anything).Return(nil, errors.New1Msg("message not found", http.StatusNotFound))
+
+	chatsRepo := mocks.NewChatsRepo(t)
+	chatsRepo.On("CheckUserInChat", mock.Anything, userId, chatId).Return(true, nil)
+	chatsRepo.On("CheckUserInChat", mock.Anything, otherUserId+1, chatId).Return(false, nil)
+
+	bookmarksRepo := mocks.NewBookmarksRepo(t)
+	bookmarksRepo.On("New", mock.Anything, mock.Anything).Return(func(_ context.Context, b *models.Bookmark) *errors.Error {
+		require.Equal(t, userId, b.UserId)
+		require.Equal(t, messageId, b.MessageId)
+		b.Id = 1
+		return nil
+	}).Once()
+	bookmarksRepo.On("New", mock.Anything, mock.Anything).Return(errors.New1Msg("message already saved", http.StatusBadRequest)).Once()
+
+	s := &MessagesService{repo: repo, chatsRepo: chatsRepo, bookmarksRepo: bookmarksRepo}
+
+	bookmark, err := s.AddBookmark(ctx, messageId)
+	require.Nil(t, err)
+	require.Equal(t, 1, bookmark.Id)
+	require.Equal(t, message, bookmark.Message)
+
+	_, err = s.AddBookmark(ctx, messageId)
+	require.NotNil(t, err, "message should be saved once")
+	require.Equal(t, http.StatusBadRequest, err.Code)
+
+	_, err = s.AddBookmark(ctx, 0)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code)
+
+	_, err = s.AddBookmark(ctx, messageId+1)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusNotFound, err.Code)
+
+	_, err = s.AddBookmark(auth.CtxWithUser(context.Background(), otherUserId+1), messageId)
+	require.NotNil(t, err, "message of other chat should not be saved")
+	require.Equal(t, http.StatusForbidden, err.Code)
+}
+
+func TestGetRemoveBookmarks(t *testing.T) {
+	const userId, messageId = 1, 10
+	ctx := auth.CtxWithUser(context.Background(), userId)
+
+	bookmarksRepo := mocks.NewBookmarksRepo(t)
+	bookmarksRepo.On("GetByUser", mock.Anything, userId).Return([]models.Bookmark{
+		{Id: 1, UserId: userId, MessageId: messageId, Message: &models.Message{Id: messageId}},
+	}, nil).Once()
+	bookmarksRepo.On("Delete", mock.Anything, userId, messageId).Return(nil).Once()
+
+	s := &MessagesService{bookmarksRepo: bookmarksRepo}
+
+	bookmarks, err := s.GetBookmarks(ctx)
+	require.Nil(t, err)
+	require.Len(t, bookmarks, 1)
+	require.Equal(t, messageId, bookmarks[0].Message.Id)
+
+	require.Nil(t, s.RemoveBookmark(ctx, messageId))
+
+	err = s.RemoveBookmark(ctx, 0)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code)
+}
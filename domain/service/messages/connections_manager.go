@@ -2,8 +2,10 @@ package messages
 
 import (
 	"context"
+	"encoding/json"
 	"messanger/domain/models"
 	"messanger/pkg/errors"
+	"slices"
 	"sync"
 	"time"
 )
@@ -19,6 +21,9 @@ type UserLastOnlineUpdater interface {
 type Conn interface {
 	Send(*Event) (ok bool)
 	Ping() (ok bool)
+	Close()
+	// SessionId returns session of access token the connection was opened with
+	SessionId() int
 }
 
 type UserConnections struct {
@@ -30,12 +35,29 @@ const (
 	EventTypeCreate = "create"
 	EventTypeUpdate = "update"
 	EventTypeDelete = "delete"
+	EventTypeError  = "error"
+
+	EventTypeGroupUpdated = "group_updated"
+	EventTypeChatAdded    = "chat_added"
+	EventTypeChatRemoved  = "chat_removed"
 )
 
 type Event struct {
 	Type   string `json:"type"`
 	ChatId int    `json:"chat_id"`
 	Data   any    `json:"data"`
+
+	encodeOnce sync.Once
+	encoded    []byte
+	encodeErr  error
+}
+
+// JSON returns encoded event, event is encoded once for all receivers
+func (e *Event) JSON() ([]byte, error) {
+	e.encodeOnce.Do(func() {
+		e.encoded, e.encodeErr = json.Marshal(e)
+	})
+	return e.encoded, e.encodeErr
 }
 
 type ConnectionsManager struct {
@@ -61,11 +83,9 @@ func (s *MessagesService) NewConnectionsManager() *ConnectionsManager {
 	go func() {
 		for {
 			time.Sleep(5 * time.Second)
-			for userId, connections := range m.userChats {
-				for i, conn := range *connections.connections {
-					if !conn.Ping() {
-						m.removeConn(userId, i)
-					}
+			for _, t := range m.snapshot(nil) {
+				if !t.conn.Ping() {
+					m.removeConn(t.userId, t.conn)
 				}
 			}
 		}
@@ -90,33 +110,91 @@ func (m *ConnectionsManager) insertConn(userId int, conn Conn, chats ...int) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if chats != nil {
-		connections := &[]Conn{conn}
-		m.userChats[userId] = UserConnections{
-			listenChats: chats,
-			connections: connections,
-		}
-		for _, chatId := range chats {
-			if _, ok := m.chatToUsers[chatId]; !ok {
-				m.chatToUsers[chatId] = make(map[int]*[]Conn)
-			}
-			m.chatToUsers[chatId][userId] = connections
+	if userConnections, ok := m.userChats[userId]; ok {
+		*userConnections.connections = append(*userConnections.connections, conn)
+		return
+	}
+
+	connections := &[]Conn{conn}
+	m.userChats[userId] = UserConnections{
+		listenChats: chats,
+		connections: connections,
+	}
+	for _, chatId := range chats {
+		if _, ok := m.chatToUsers[chatId]; !ok {
+			m.chatToUsers[chatId] = make(map[int]*[]Conn)
 		}
-	} else {
-		*m.userChats[userId].connections = append(*m.userChats[userId].connections, conn)
+		m.chatToUsers[chatId][userId] = connections
+	}
+}
+
+// subscribe makes online user listen chat, returns false if user is offline
+func (m *ConnectionsManager) subscribe(chatId int, userId int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	userConnections, ok := m.userChats[userId]
+	if !ok {
+		return false
+	}
+	if slices.Contains(userConnections.listenChats, chatId) {
+		return true
+	}
+	userConnections.listenChats = append(userConnections.listenChats, chatId)
+	m.userChats[userId] = userConnections
+
+	if _, ok := m.chatToUsers[chatId]; !ok {
+		m.chatToUsers[chatId] = make(map[int]*[]Conn)
+	}
+	m.chatToUsers[chatId][userId] = userConnections.connections
+	return true
+}
+
+// unsubscribe stops user listening chat, returns false if user is offline or didn't listen chat
+func (m *ConnectionsManager) unsubscribe(chatId int, userId int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.unsubscribeLocked(chatId, userId)
+}
+
+func (m *ConnectionsManager) unsubscribeLocked(chatId int, userId int) bool {
+	userConnections, ok := m.userChats[userId]
+	if !ok {
+		return false
+	}
+	i := slices.Index(userConnections.listenChats, chatId)
+	if i == -1 {
+		return false
 	}
+	userConnections.listenChats = slices.Delete(userConnections.listenChats, i, i+1)
+	m.userChats[userId] = userConnections
+
+	delete(m.chatToUsers[chatId], userId)
+	if len(m.chatToUsers[chatId]) == 0 {
+		delete(m.chatToUsers, chatId)
+	}
+	return true
 }
 
-func (m *ConnectionsManager) removeConn(userId int, i int) {
+func (m *ConnectionsManager) removeConn(userId int, conn Conn) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if len(*m.userChats[userId].connections) != 1 {
-		*m.userChats[userId].connections = append((*m.userChats[userId].connections)[:i], (*m.userChats[userId].connections)[i+1:]...)
+	userConnections, ok := m.userChats[userId]
+	if !ok {
+		return
+	}
+	i := slices.Index(*userConnections.connections, conn)
+	if i == -1 {
+		return
+	}
+	if len(*userConnections.connections) != 1 {
+		*userConnections.connections = slices.Delete(*userConnections.connections, i, i+1)
 		return
 	}
 
-	for _, chatId := range m.userChats[userId].listenChats {
+	for _, chatId := range userConnections.listenChats {
 		delete(m.chatToUsers[chatId], userId)
 		if len(m.chatToUsers[chatId]) == 0 {
 			delete(m.chatToUsers, chatId)
@@ -124,7 +202,43 @@ func (m *ConnectionsManager) removeConn(userId int, i int) {
 	}
 	delete(m.userChats, userId)
 
-	go m.usersUpdater.UpdateLastOnlineTime(context.Background(), userId, time.Now())
+	if m.usersUpdater != nil {
+		go m.usersUpdater.UpdateLastOnlineTime(context.Background(), userId, time.Now())
+	}
+}
+
+// DisconnectUser closes all connections of user, e.g. after its tokens were revoked
+func (m *ConnectionsManager) DisconnectUser(userId int) {
+	m.mu.RLock()
+	var connections []Conn
+	if userConnections, ok := m.userChats[userId]; ok {
+		connections = slices.Clone(*userConnections.connections)
+	}
+	m.mu.RUnlock()
+
+	for _, conn := range connections {
+		conn.Close()
+		m.removeConn(userId, conn)
+	}
+}
+
+// DisconnectSession closes connections of user opened with tokens of session, e.g. after the session was revoked
+func (m *ConnectionsManager) DisconnectSession(userId int, sessionId int) {
+	m.mu.RLock()
+	var connections []Conn
+	if userConnections, ok := m.userChats[userId]; ok {
+		for _, conn := range *userConnections.connections {
+			if conn.SessionId() == sessionId {
+				connections = append(connections, conn)
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, conn := range connections {
+		conn.Close()
+		m.removeConn(userId, conn)
+	}
 }
 
 func (m *ConnectionsManager) CheckOnlineList(usersId []int) []bool {
@@ -136,23 +250,95 @@ func (m *ConnectionsManager) CheckOnlineList(usersId []int) []bool {
 }
 
 func (m *ConnectionsManager) CheckOnline(userId int) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	_, ok := m.userChats[userId]
 	return ok
 }
 
-func (m *ConnectionsManager) sendEventToChat(chatId int, event *Event) {
+type connTarget struct {
+	userId int
+	conn   Conn
+}
+
+// snapshot returns connections listening chat, or all connections if chatId is nil
+func (m *ConnectionsManager) snapshot(chatId *int) []connTarget {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	var targets []connTarget
+	if chatId == nil {
+		for userId, userConnections := range m.userChats {
+			for _, conn := range *userConnections.connections {
+				targets = append(targets, connTarget{userId: userId, conn: conn})
+			}
+		}
+		return targets
+	}
+	for userId, connections := range m.chatToUsers[*chatId] {
+		for _, conn := range *connections {
+			targets = append(targets, connTarget{userId: userId, conn: conn})
+		}
+	}
+	return targets
+}
+
+func (m *ConnectionsManager) sendEventToUser(userId int, event *Event) {
+	m.mu.RLock()
+	var connections []Conn
+	if userConnections, ok := m.userChats[userId]; ok {
+		connections = slices.Clone(*userConnections.connections)
+	}
+	m.mu.RUnlock()
+
+	for _, conn := range connections {
+		if !conn.Send(event) {
+			m.removeConn(userId, conn)
+		}
+	}
+}
+
+// fanOutWorkers limits concurrent sends of one event, so channels with many subscribers
+// are not delivered one connection after another and don't spawn a goroutine per connection
+const fanOutWorkers = 32
+
+func (m *ConnectionsManager) sendEventToChat(chatId int, event *Event) {
 	event.ChatId = chatId
+	targets := m.snapshot(&chatId)
 
-	for userId, connections := range m.chatToUsers[chatId] {
-		for i, conn := range *connections {
-			if !conn.Send(event) {
-				m.removeConn(userId, i)
+	if len(targets) <= 1 {
+		for _, t := range targets {
+			if !t.conn.Send(event) {
+				m.removeConn(t.userId, t.conn)
 			}
 		}
+		return
+	}
+
+	jobs := make(chan connTarget)
+	var wg sync.WaitGroup
+	for range min(fanOutWorkers, len(targets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				if !t.conn.Send(event) {
+					m.removeConn(t.userId, t.conn)
+				}
+			}
+		}()
 	}
+	for _, t := range targets {
+		jobs <- t
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// OnMessageCreated pushes message created outside of MessagesService, e.g. system message
+func (m *ConnectionsManager) OnMessageCreated(msg *models.Message) {
+	m.onCreateMessage(msg)
 }
 
 func (m *ConnectionsManager) onCreateMessage(msg *models.Message) {
@@ -179,3 +365,52 @@ func (m *ConnectionsManager) onDeleteMessage(id int, chatId int) {
 		},
 	})
 }
+
+func (m *ConnectionsManager) OnGroupUpdated(group *models.Group) {
+	m.sendEventToChat(group.ChatId, &Event{
+		Type: EventTypeGroupUpdated,
+		Data: group,
+	})
+}
+
+// OnChatMemberAdded subscribes online user to chat, so user gets chat events without reconnecting
+func (m *ConnectionsManager) OnChatMemberAdded(chatId int, userId int) {
+	if !m.subscribe(chatId, userId) {
+		return
+	}
+	m.sendEventToUser(userId, &Event{
+		Type:   EventTypeChatAdded,
+		ChatId: chatId,
+	})
+}
+
+// OnChatMemberRemoved unsubscribes online user from chat
+func (m *ConnectionsManager) OnChatMemberRemoved(chatId int, userId int) {
+	if !m.unsubscribe(chatId, userId) {
+		return
+	}
+	m.sendEventToUser(userId, &Event{
+		Type:   EventTypeChatRemoved,
+		ChatId: chatId,
+	})
+}
+
+// OnChatDeleted unsubscribes all online members from deleted chat
+func (m *ConnectionsManager) OnChatDeleted(chatId int) {
+	m.mu.Lock()
+	usersId := make([]int, 0, len(m.chatToUsers[chatId]))
+	for userId := range m.chatToUsers[chatId] {
+		usersId = append(usersId, userId)
+	}
+	for _, userId := range usersId {
+		m.unsubscribeLocked(chatId, userId)
+	}
+	m.mu.Unlock()
+
+	for _, userId := range usersId {
+		m.sendEventToUser(userId, &Event{
+			Type:   EventTypeChatRemoved,
+			ChatId: chatId,
+		})
+	}
+}
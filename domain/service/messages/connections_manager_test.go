@@ -0,0 +1,129 @@
+package messages
+
+import (
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+)
+
+type testConn struct {
+	ok        bool
+	sessionId int
+	sent      atomic.Int32
+	closed    atomic.Bool
+}
+
+func (c *testConn) Send(*Event) bool {
+	c.sent.Add(1)
+	return c.ok
+}
+
+func (c *testConn) Ping() bool {
+	return c.ok
+}
+
+func (c *testConn) Close() {
+	c.closed.Store(true)
+}
+
+func (c *testConn) SessionId() int {
+	return c.sessionId
+}
+
+func TestSendEventToChat(t *testing.T) {
+	const chatId = 1
+	m := &ConnectionsManager{
+		chatToUsers: make(map[int]map[int]*[]Conn),
+		userChats:   make(map[int]UserConnections),
+	}
+
+	conns := make([]*testConn, 100)
+	for i := range conns {
+		conns[i] = &testConn{ok: i%10 != 0}
+		m.insertConn(i+1, conns[i], chatId)
+	}
+
+	m.sendEventToChat(chatId, &Event{Type: EventTypeCreate})
+
+	for i, conn := range conns {
+		require.EqualValues(t, 1, conn.sent.Load(), "conn %d should receive event once", i)
+		require.Equal(t, conn.ok, m.CheckOnline(i+1), "failed conn %d should be removed", i)
+	}
+	require.Len(t, m.chatToUsers[chatId], 90)
+}
+
+func TestChatMembership(t *testing.T) {
+	const userId, chatId = 1, 2
+	m := &ConnectionsManager{
+		chatToUsers: make(map[int]map[int]*[]Conn),
+		userChats:   make(map[int]UserConnections),
+	}
+
+	m.OnChatMemberAdded(chatId, userId)
+	require.Empty(t, m.chatToUsers, "offline user should not be subscribed")
+
+	conn := &testConn{ok: true}
+	m.insertConn(userId, conn, 1)
+
+	m.OnChatMemberAdded(chatId, userId)
+	require.EqualValues(t, 1, conn.sent.Load())
+	require.Contains(t, m.chatToUsers[chatId], userId)
+
+	m.sendEventToChat(chatId, &Event{Type: EventTypeCreate})
+	require.EqualValues(t, 2, conn.sent.Load())
+
+	m.OnChatMemberRemoved(chatId, userId)
+	require.EqualValues(t, 3, conn.sent.Load())
+	require.NotContains(t, m.chatToUsers, chatId)
+
+	m.sendEventToChat(chatId, &Event{Type: EventTypeCreate})
+	require.EqualValues(t, 3, conn.sent.Load(), "removed user should not receive chat events")
+
+	m.OnChatMemberAdded(chatId, userId)
+	m.OnChatDeleted(chatId)
+	require.EqualValues(t, 5, conn.sent.Load())
+	require.Equal(t, []int{1}, m.userChats[userId].listenChats)
+	require.NotContains(t, m.chatToUsers, chatId)
+}
+
+func TestDisconnectUser(t *testing.T) {
+	const userId, chatId = 1, 2
+	m := &ConnectionsManager{
+		chatToUsers: make(map[int]map[int]*[]Conn),
+		userChats:   make(map[int]UserConnections),
+	}
+
+	conns := []*testConn{{ok: true}, {ok: true}}
+	for _, conn := range conns {
+		m.insertConn(userId, conn, chatId)
+	}
+	other := &testConn{ok: true}
+	m.insertConn(userId+1, other, chatId)
+
+	m.DisconnectUser(userId)
+	for _, conn := range conns {
+		require.True(t, conn.closed.Load())
+	}
+	require.False(t, m.CheckOnline(userId))
+	require.NotContains(t, m.chatToUsers[chatId], userId)
+	require.False(t, other.closed.Load())
+	require.True(t, m.CheckOnline(userId+1))
+}
+
+func TestDisconnectSession(t *testing.T) {
+	const userId, chatId = 1, 2
+	m := &ConnectionsManager{
+		chatToUsers: make(map[int]map[int]*[]Conn),
+		userChats:   make(map[int]UserConnections),
+	}
+
+	revoked := &testConn{ok: true, sessionId: 10}
+	current := &testConn{ok: true, sessionId: 11}
+	m.insertConn(userId, revoked, chatId)
+	m.insertConn(userId, current, chatId)
+
+	m.DisconnectSession(userId, revoked.sessionId)
+	require.True(t, revoked.closed.Load())
+	require.False(t, current.closed.Load(), "connections of other sessions should stay open")
+	require.True(t, m.CheckOnline(userId))
+}
@@ -1,10 +1,14 @@
 package messages
 
-import "time"
+import (
+	"messanger/domain/models"
+	"time"
+)
 
 type CreateMessageDTO struct {
-	ChatId int    `json:"chat_id"`
-	Text   string `json:"text"`
+	ChatId    int    `json:"chat_id"`
+	Text      string `json:"text"`
+	StickerId int    `json:"sticker_id"`
 }
 
 type GetMessagesDTO struct {
@@ -14,10 +18,30 @@ type GetMessagesDTO struct {
 }
 
 type MessagesResponseDTO struct {
-	Id     int       `json:"id"`
-	UserId int       `json:"user_id"`
-	Text   string    `json:"text"`
-	Time   time.Time `json:"time"`
+	Id        int       `json:"id"`
+	UserId    int       `json:"user_id"`
+	Text      string    `json:"text"`
+	Time      time.Time `json:"time"`
+	Kind      string    `json:"kind"`
+	StickerId int       `json:"sticker_id,omitempty"`
+
+	Payload *models.SystemPayload `json:"payload,omitempty"`
+}
+
+func newMessagesResponse(messages []models.Message) []MessagesResponseDTO {
+	resp := make([]MessagesResponseDTO, len(messages))
+	for i := range messages {
+		resp[i] = MessagesResponseDTO{
+			Id:        messages[i].Id,
+			UserId:    messages[i].UserId,
+			Text:      messages[i].Text,
+			Time:      messages[i].Time,
+			Kind:      messages[i].Kind,
+			StickerId: messages[i].StickerId,
+			Payload:   messages[i].Payload,
+		}
+	}
+	return resp
 }
 
 type UpdateMessageDTO struct {
@@ -3,9 +3,12 @@ package messages
 import (
 	"context"
 	"fmt"
+	"messanger/config"
 	"messanger/domain/models"
 	"messanger/domain/ports"
 	"messanger/domain/service/auth"
+	"messanger/domain/service/permissions"
+	"messanger/domain/service/ratelimit"
 	"messanger/pkg/db"
 	"messanger/pkg/errors"
 	"net/http"
@@ -13,16 +16,42 @@ import (
 )
 
 type MessagesService struct {
-	repo        ports.MessagesRepo
-	chatsRepo   ports.ChatsRepo
-	usersRepo   ports.UsersRepo
-	connManager *ConnectionsManager
+	repo             ports.MessagesRepo
+	chatsRepo        ports.ChatsRepo
+	groupsRepo       ports.GroupsRepo
+	usersRepo        ports.UsersRepo
+	bookmarksRepo    ports.BookmarksRepo
+	stickersRepo     ports.StickersRepo
+	restrictionsRepo ports.RestrictionsRepo
+	connManager      *ConnectionsManager
+	perms            *permissions.Checker
+
+	cache       ports.Cache
+	userLimiter *ratelimit.Limiter
+	chatLimiter *ratelimit.Limiter
 }
 
-func NewMessagesService(repo ports.MessagesRepo, chatsRepo ports.ChatsRepo) *MessagesService {
+func NewMessagesService(
+	repo ports.MessagesRepo,
+	chatsRepo ports.ChatsRepo,
+	groupsRepo ports.GroupsRepo,
+	bookmarksRepo ports.BookmarksRepo,
+	stickersRepo ports.StickersRepo,
+	restrictionsRepo ports.RestrictionsRepo,
+	cache ports.Cache,
+	cfg *config.RateLimitConfig,
+) *MessagesService {
 	return &MessagesService{
-		repo:      repo,
-		chatsRepo: chatsRepo,
+		repo:             repo,
+		chatsRepo:        chatsRepo,
+		groupsRepo:       groupsRepo,
+		bookmarksRepo:    bookmarksRepo,
+		stickersRepo:     stickersRepo,
+		restrictionsRepo: restrictionsRepo,
+		perms:            permissions.NewChecker(groupsRepo, chatsRepo),
+		cache:            cache,
+		userLimiter:      ratelimit.NewLimiter(cache, cfg.UserMessagesPerMin, cfg.UserMessagesBurst),
+		chatLimiter:      ratelimit.NewLimiter(cache, cfg.ChatMessagesPerMin, cfg.ChatMessagesBurst),
 	}
 }
 
@@ -36,12 +65,44 @@ func (s *MessagesService) CreateMessage(ctx context.Context, dto *CreateMessageD
 		return errors.New(fmt.Sprintf("user (%d) tried to create a message in the chat (%d)", userId, dto.ChatId),
 			models.ErrPermissionDenied, http.StatusForbidden)
 	}
+	perm, action := models.PermSendMessages, "send message"
+	if dto.StickerId != 0 {
+		perm, action = models.PermSendMedia, "send sticker"
+	}
+	if err := s.perms.CheckChat(ctx, userId, dto.ChatId, perm, action); err != nil {
+		return err.Trace()
+	}
+	if err := s.checkMuted(ctx, userId, dto.ChatId); err != nil {
+		return err.Trace()
+	}
 
 	message := &models.Message{
 		ChatId: dto.ChatId,
 		UserId: userId,
 		Text:   dto.Text,
 		Time:   time.Now(),
+		Kind:   models.MessageKindText,
+	}
+
+	if dto.StickerId != 0 {
+		sticker, err := s.stickersRepo.GetStickerById(ctx, dto.StickerId)
+		if err != nil {
+			return err.Trace()
+		}
+		installed, err := s.stickersRepo.CheckPackInstalled(ctx, userId, sticker.PackId)
+		if err != nil {
+			return err.Trace()
+		}
+		if !installed {
+			return errors.New1Msg("sticker pack is not installed", http.StatusBadRequest)
+		}
+		message.Kind = models.MessageKindSticker
+		message.StickerId = sticker.Id
+		message.Text = sticker.Emoji
+	}
+
+	if err := s.checkRateLimit(ctx, userId, dto.ChatId); err != nil {
+		return err.Trace()
 	}
 
 	ctx, err = db.WithTx(ctx, s.repo)
@@ -76,13 +137,17 @@ func (s *MessagesService) UpdateMessage(ctx context.Context, id int, dto *Update
 		return errors.New(fmt.Sprintf("user (%d) tried to update a message (%d)", userId, id),
 			models.ErrPermissionDenied, http.StatusForbidden)
 	}
-	if err := s.repo.Update(ctx, id, dto.Text); err != nil {
-		return err.Trace()
-	}
 	m, err := s.repo.GetById(ctx, id)
 	if err != nil {
 		return err.Trace()
 	}
+	if m.Kind != models.MessageKindText {
+		return errors.New1Msg("only text messages can be edited", http.StatusBadRequest)
+	}
+	if err := s.repo.Update(ctx, id, dto.Text); err != nil {
+		return err.Trace()
+	}
+	m.Text = dto.Text
 	if s.connManager != nil {
 		go s.connManager.onUpdateMessage(m)
 	}
@@ -98,6 +163,10 @@ func (s *MessagesService) DeleteMessage(ctx context.Context, id int) *errors.Err
 	if err != nil {
 		return err.Trace()
 	}
+	// system messages are group history, their UserId is the actor of event
+	if m.Kind == models.MessageKindSystem {
+		return errors.New1Msg("system messages can not be deleted", http.StatusBadRequest)
+	}
 	if m.UserId != userId {
 		return errors.New(fmt.Sprintf("user (%d) tried to delete a message (%d)", userId, id),
 			models.ErrPermissionDenied, http.StatusForbidden)
@@ -149,17 +218,29 @@ func (s *MessagesService) GetFromChat(ctx context.Context, dto *GetMessagesDTO)
 		return nil, err.Trace()
 	}
 
-	resp := make([]MessagesResponseDTO, len(messages))
-	for i := range messages {
-		resp[i] = MessagesResponseDTO{
-			Id:     messages[i].Id,
-			UserId: messages[i].UserId,
-			Text:   messages[i].Text,
-			Time:   messages[i].Time,
-		}
+	return newMessagesResponse(messages), nil
+}
+
+const maxPreviewCount = 50
+
+// GetPublicPreview returns recent messages of public group or channel without joining it
+func (s *MessagesService) GetPublicPreview(ctx context.Context, username string, count int) ([]MessagesResponseDTO, *errors.Error) {
+	if len(username) == 0 {
+		return nil, errors.New1Msg("username is missing", http.StatusBadRequest)
+	}
+	if count <= 0 || count > maxPreviewCount {
+		count = maxPreviewCount
+	}
+	group, err := s.groupsRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	messages, err := s.repo.GetByChat(ctx, group.ChatId, 0, count)
+	if err != nil {
+		return nil, err.Trace()
 	}
 
-	return resp, nil
+	return newMessagesResponse(messages), nil
 }
 
 // GetById for system usage!
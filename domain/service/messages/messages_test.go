@@ -0,0 +1,35 @@
+package messages
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/domain/service/auth"
+	"net/http"
+	"testing"
+)
+
+func TestDeleteSystemMessage(t *testing.T) {
+	const userId, messageId = 1, 10
+	ctx := auth.CtxWithUser(context.Background(), userId)
+
+	repo := mocks.NewMessagesRepo(t)
+	repo.On("GetById", mock.Anything, messageId).Return(&models.Message{
+		Id:     messageId,
+		ChatId: 2,
+		UserId: userId,
+		Kind:   models.MessageKindSystem,
+		Payload: &models.SystemPayload{
+			Action:  models.SystemActionKick,
+			ActorId: userId,
+			UserId:  3,
+		},
+	}, nil)
+
+	s := &MessagesService{repo: repo}
+	err := s.DeleteMessage(ctx, messageId)
+	require.NotNil(t, err, "actor should not delete system message")
+	require.Equal(t, http.StatusBadRequest, err.Code)
+}
@@ -0,0 +1,40 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+const ErrMuted = "you are muted in this chat"
+
+// checkMuted returns forbidden error if user has active mute in group of chat
+func (s *MessagesService) checkMuted(ctx context.Context, userId int, chatId int) *errors.Error {
+	chat, err := s.chatsRepo.GetById(ctx, chatId)
+	if err != nil {
+		return err.Trace()
+	}
+	if chat.Type != models.ChatTypeGroup && chat.Type != models.ChatTypeChannel {
+		return nil
+	}
+	group, err := s.groupsRepo.GetGroupByChatId(ctx, chatId)
+	if err != nil {
+		return err.Trace()
+	}
+
+	mute, err := s.restrictionsRepo.Get(ctx, group.Id, userId, models.RestrictionMute)
+	if err != nil {
+		if err.Code != http.StatusNotFound {
+			return err.Trace()
+		}
+		return nil
+	}
+	msg := ErrMuted
+	if mute.ExpireTime != nil {
+		msg += " until " + mute.ExpireTime.Format(time.RFC3339)
+	}
+	return errors.New(fmt.Sprintf("muted user (%d) tried to send message in group (%d)", userId, group.Id), msg, http.StatusForbidden)
+}
@@ -0,0 +1,48 @@
+package messages
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/pkg/errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCheckMuted(t *testing.T) {
+	ctx := context.Background()
+	const groupChatId, userChatId, groupId = 1, 2, 3
+
+	chatsRepo := mocks.NewChatsRepo(t)
+	chatsRepo.On("GetById", mock.Anything, groupChatId).Return(&models.Chat{Id: groupChatId, Type: models.ChatTypeGroup}, nil)
+	chatsRepo.On("GetById", mock.Anything, userChatId).Return(&models.Chat{Id: userChatId, Type: models.ChatTypeUser}, nil)
+
+	groupsRepo := mocks.NewGroupsRepo(t)
+	groupsRepo.On("GetGroupByChatId", mock.Anything, groupChatId).Return(&models.Group{Id: groupId, ChatId: groupChatId}, nil)
+
+	expireTime := time.Now().Add(time.Hour)
+	restrictionsRepo := mocks.NewRestrictionsRepo(t)
+	restrictionsRepo.On("Get", mock.Anything, groupId, 1, models.RestrictionMute).Return(&models.Restriction{
+		GroupId:    groupId,
+		UserId:     1,
+		Type:       models.RestrictionMute,
+		ExpireTime: &expireTime,
+	}, nil)
+	restrictionsRepo.On("Get", mock.Anything, groupId, 2, models.RestrictionMute).Return(nil, errors.New1Msg("mute not found", http.StatusNotFound))
+
+	s := &MessagesService{
+		chatsRepo:        chatsRepo,
+		groupsRepo:       groupsRepo,
+		restrictionsRepo: restrictionsRepo,
+	}
+
+	err := s.checkMuted(ctx, 1, groupChatId)
+	require.NotNil(t, err, "muted user should not post")
+	require.Equal(t, http.StatusForbidden, err.Code)
+
+	require.Nil(t, s.checkMuted(ctx, 2, groupChatId))
+	require.Nil(t, s.checkMuted(ctx, 1, userChatId), "mutes should not apply to private chats")
+}
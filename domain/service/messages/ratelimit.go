@@ -0,0 +1,90 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"messanger/domain/models"
+	"messanger/pkg/errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const ErrTooManyMessages = "too many messages"
+
+// checkRateLimit is called after other checks of message, so rejected messages don't use up the limits
+func (s *MessagesService) checkRateLimit(ctx context.Context, userId int, chatId int) *errors.Error {
+	slowModeKey, err := s.checkSlowMode(ctx, userId, chatId)
+	if err != nil {
+		return err.Trace()
+	}
+	if err := s.checkLimiters(ctx, userId, chatId); err != nil {
+		// message is not sent, so it doesn't start slow mode interval
+		if len(slowModeKey) != 0 {
+			s.cache.Del(ctx, slowModeKey)
+		}
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *MessagesService) checkLimiters(ctx context.Context, userId int, chatId int) *errors.Error {
+	wait, err := s.userLimiter.Allow(ctx, "rl:user:"+strconv.Itoa(userId))
+	if err != nil {
+		return err.Trace()
+	}
+	if wait > 0 {
+		return tooManyMessages(fmt.Sprintf("user (%d) exceeded messages rate limit", userId), wait)
+	}
+
+	wait, err = s.chatLimiter.Allow(ctx, "rl:chat:"+strconv.Itoa(chatId))
+	if err != nil {
+		return err.Trace()
+	}
+	if wait > 0 {
+		return tooManyMessages(fmt.Sprintf("chat (%d) exceeded messages rate limit", chatId), wait)
+	}
+	return nil
+}
+
+// checkSlowMode allows one message per slow mode interval from each non admin member of group,
+// returns key of started interval or empty string if slow mode is off
+func (s *MessagesService) checkSlowMode(ctx context.Context, userId int, chatId int) (string, *errors.Error) {
+	chat, err := s.chatsRepo.GetById(ctx, chatId)
+	if err != nil {
+		return "", err.Trace()
+	}
+	if chat.Type != models.ChatTypeGroup {
+		return "", nil
+	}
+	group, err := s.groupsRepo.GetGroupByChatId(ctx, chatId)
+	if err != nil {
+		return "", err.Trace()
+	}
+	if group.SlowModeSec <= 0 {
+		return "", nil
+	}
+
+	role, err := s.perms.GetRole(ctx, userId, group.Id)
+	if err != nil {
+		return "", err.Trace()
+	}
+	if role == models.RoleOwner || role == models.RoleAdmin {
+		return "", nil
+	}
+
+	// interval is started atomically, messages sent to other instances at the same time are rejected
+	key := fmt.Sprintf("slow:%d:%d", chatId, userId)
+	ok, err := s.cache.CompareAndSet(ctx, key, 0, 1, time.Duration(group.SlowModeSec)*time.Second)
+	if err != nil {
+		return "", err.Trace()
+	}
+	if !ok {
+		return "", tooManyMessages(fmt.Sprintf("user (%d) is in slow mode in group (%d)", userId, group.Id), s.cache.TTL(ctx, key))
+	}
+	return key, nil
+}
+
+func tooManyMessages(msg string, wait time.Duration) *errors.Error {
+	return errors.New(msg, ErrTooManyMessages, http.StatusTooManyRequests).WithRetryAfter(wait)
+}
@@ -0,0 +1,116 @@
+package messages
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	cache "messanger/data/cache/local"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/domain/service/auth"
+	"messanger/domain/service/permissions"
+	"messanger/domain/service/ratelimit"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func newSlowModeService(t *testing.T, perMin int, burst int) *MessagesService {
+	const chatId, groupId = 1, 2
+
+	chatsRepo := mocks.NewChatsRepo(t)
+	chatsRepo.On("GetById", mock.Anything, chatId).Return(&models.Chat{Id: chatId, Type: models.ChatTypeGroup}, nil)
+	groupsRepo := mocks.NewGroupsRepo(t)
+	groupsRepo.On("GetGroupByChatId", mock.Anything, chatId).Return(&models.Group{Id: groupId, ChatId: chatId, SlowModeSec: 60}, nil)
+	groupsRepo.On("GetRole", mock.Anything, mock.Anything, groupId).Return(models.RoleMember, nil)
+
+	c := cache.NewCache()
+	return &MessagesService{
+		chatsRepo:   chatsRepo,
+		groupsRepo:  groupsRepo,
+		perms:       permissions.NewChecker(groupsRepo, chatsRepo),
+		cache:       c,
+		userLimiter: ratelimit.NewLimiter(c, perMin, burst),
+		chatLimiter: ratelimit.NewLimiter(c, 0, 0),
+	}
+}
+
+func TestSlowMode(t *testing.T) {
+	ctx := context.Background()
+	s := newSlowModeService(t, 0, 0)
+
+	require.Nil(t, s.checkRateLimit(ctx, 1, 1))
+	err := s.checkRateLimit(ctx, 1, 1)
+	require.NotNil(t, err, "second message should wait for slow mode interval")
+	require.Equal(t, http.StatusTooManyRequests, err.Code)
+	require.Nil(t, s.checkRateLimit(ctx, 2, 1), "interval should be per member")
+
+	// messages sent at the same time
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s.checkRateLimit(ctx, 3, 1) == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), allowed.Load())
+}
+
+func TestSlowModeRateLimited(t *testing.T) {
+	ctx := context.Background()
+	s := newSlowModeService(t, 60, 1)
+
+	wait, err := s.userLimiter.Allow(ctx, "rl:user:"+strconv.Itoa(1))
+	require.Nil(t, err)
+	require.Zero(t, wait)
+
+	err = s.checkRateLimit(ctx, 1, 1)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusTooManyRequests, err.Code)
+
+	sent, err := s.cache.Get(ctx, "slow:1:1")
+	require.Nil(t, err)
+	require.Zero(t, sent, "rejected message should not start slow mode interval")
+}
+
+func TestRejectedMessageNotLimited(t *testing.T) {
+	const userId, chatId, stickerId = 1, 3, 4
+	ctx := auth.CtxWithUser(context.Background(), userId)
+
+	repo := mocks.NewMessagesRepo(t)
+	repo.On("New", mock.Anything, mock.Anything).Return(nil).Once()
+	chatsRepo := mocks.NewChatsRepo(t)
+	chatsRepo.On("CheckUserInChat", mock.Anything, userId, chatId).Return(true, nil)
+	chatsRepo.On("GetById", mock.Anything, chatId).Return(&models.Chat{Id: chatId, Type: models.ChatTypeUser}, nil)
+	chatsRepo.On("UpdateTime", mock.Anything, chatId, mock.Anything).Return(nil).Once()
+	stickersRepo := mocks.NewStickersRepo(t)
+	stickersRepo.On("GetStickerById", mock.Anything, stickerId).Return(&models.Sticker{Id: stickerId, PackId: 5}, nil)
+	stickersRepo.On("CheckPackInstalled", mock.Anything, userId, 5).Return(false, nil)
+
+	c := cache.NewCache()
+	s := &MessagesService{
+		repo:         repo,
+		chatsRepo:    chatsRepo,
+		stickersRepo: stickersRepo,
+		perms:        permissions.NewChecker(mocks.NewGroupsRepo(t), chatsRepo),
+		cache:        c,
+		userLimiter:  ratelimit.NewLimiter(c, 1, 1),
+		chatLimiter:  ratelimit.NewLimiter(c, 1, 1),
+	}
+
+	err := s.CreateMessage(ctx, &CreateMessageDTO{ChatId: chatId, StickerId: stickerId})
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, err.Code)
+
+	require.Nil(t, s.CreateMessage(ctx, &CreateMessageDTO{ChatId: chatId, Text: "hi"}), "rejected sticker should not use up the limit")
+	err = s.CreateMessage(ctx, &CreateMessageDTO{ChatId: chatId, Text: "hi"})
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusTooManyRequests, err.Code)
+}
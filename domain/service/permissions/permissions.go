@@ -0,0 +1,177 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"messanger/domain/models"
+	"messanger/domain/ports"
+	"messanger/pkg/errors"
+	"net/http"
+)
+
+// Checker resolves group member permissions from role, custom roles and member overrides
+type Checker struct {
+	groupsRepo ports.GroupsRepo
+	chatsRepo  ports.ChatsRepo
+}
+
+func NewChecker(groupsRepo ports.GroupsRepo, chatsRepo ports.ChatsRepo) *Checker {
+	return &Checker{
+		groupsRepo: groupsRepo,
+		chatsRepo:  chatsRepo,
+	}
+}
+
+// GetRole returns role of user in group, users in group without role are members
+func (c *Checker) GetRole(ctx context.Context, userId int, groupId int) (string, *errors.Error) {
+	role, err := c.groupsRepo.GetRole(ctx, userId, groupId)
+	if err != nil {
+		if err.Code != http.StatusNotFound {
+			return "", err.Trace()
+		}
+		return models.RoleMember, nil
+	}
+	return role, nil
+}
+
+// Get returns effective permissions of user in group. Users who are not in group have no permissions.
+// Permissions of role are restricted or extended by group settings, then member override is applied.
+func (c *Checker) Get(ctx context.Context, userId int, groupId int) (models.Permissions, *errors.Error) {
+	exist, err := c.groupsRepo.CheckUserInGroup(ctx, userId, groupId)
+	if err != nil {
+		return 0, err.Trace()
+	}
+	if !exist {
+		return 0, nil
+	}
+
+	role, err := c.GetRole(ctx, userId, groupId)
+	if err != nil {
+		return 0, err.Trace()
+	}
+	if role == models.RoleOwner {
+		return models.PermAll, nil
+	}
+
+	group, err := c.groupsRepo.GetById(ctx, groupId)
+	if err != nil {
+		return 0, err.Trace()
+	}
+
+	perms, ok := models.BuiltinRolePermissions(role)
+	if !ok {
+		customRole, err := c.groupsRepo.GetCustomRole(ctx, groupId, role)
+		if err != nil {
+			if err.Code != http.StatusNotFound {
+				return 0, err.Trace()
+			}
+			perms, _ = models.BuiltinRolePermissions(models.RoleMember)
+		} else {
+			perms = customRole.Permissions
+		}
+	}
+
+	if role != models.RoleAdmin {
+		if group.OnlyAdminsPost {
+			perms &^= models.PermSendMessages | models.PermSendMedia
+		}
+		if group.MembersCanInvite && role == models.RoleMember {
+			perms |= models.PermInvite
+		}
+	}
+
+	override, err := c.groupsRepo.GetPermissionOverride(ctx, userId, groupId)
+	if err != nil {
+		return 0, err.Trace()
+	}
+	return (perms | override.Allow) &^ override.Deny, nil
+}
+
+// Check returns permission denied error if user has not perm in group, action is used in error message
+func (c *Checker) Check(ctx context.Context, userId int, groupId int, perm models.Permissions, action string) *errors.Error {
+	if groupId == 0 {
+		return errors.New1Msg("groupId is missing", http.StatusBadRequest)
+	}
+	perms, err := c.Get(ctx, userId, groupId)
+	if err != nil {
+		return err.Trace()
+	}
+	if !perms.Has(perm) {
+		return errors.New(fmt.Sprintf("user (%d) tried %s in group (%d)", userId, action, groupId),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+	return nil
+}
+
+// CheckChat is Check for chat, chats which are not groups or channels have no restrictions
+func (c *Checker) CheckChat(ctx context.Context, userId int, chatId int, perm models.Permissions, action string) *errors.Error {
+	chat, err := c.chatsRepo.GetById(ctx, chatId)
+	if err != nil {
+		return err.Trace()
+	}
+	if chat.Type != models.ChatTypeGroup && chat.Type != models.ChatTypeChannel {
+		return nil
+	}
+	group, err := c.groupsRepo.GetGroupByChatId(ctx, chatId)
+	if err != nil {
+		return err.Trace()
+	}
+	if err := c.Check(ctx, userId, group.Id, perm, action); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// EnsureSuccessor must be called before user leaves group. If leaving user is owner, or the last admin
+// of group without owner, the longest-standing admin or else the longest-standing member becomes owner.
+// It returns the new owner, 0 if ownership is not changed.
+func (c *Checker) EnsureSuccessor(ctx context.Context, groupId int, leavingUserId int) (int, *errors.Error) {
+	role, err := c.GetRole(ctx, leavingUserId, groupId)
+	if err != nil {
+		return 0, err.Trace()
+	}
+	if role != models.RoleOwner && role != models.RoleAdmin {
+		return 0, nil
+	}
+
+	users, err := c.groupsRepo.GetUsersByGroup(ctx, groupId)
+	if err != nil {
+		return 0, err.Trace()
+	}
+	var firstAdmin, firstMember int
+	for _, userId := range users {
+		if userId == leavingUserId {
+			continue
+		}
+		userRole, err := c.GetRole(ctx, userId, groupId)
+		if err != nil {
+			return 0, err.Trace()
+		}
+		switch userRole {
+		case models.RoleOwner:
+			return 0, nil
+		case models.RoleAdmin:
+			if role == models.RoleAdmin {
+				return 0, nil
+			}
+			if firstAdmin == 0 {
+				firstAdmin = userId
+			}
+		}
+		if firstMember == 0 {
+			firstMember = userId
+		}
+	}
+
+	successor := firstAdmin
+	if successor == 0 {
+		successor = firstMember
+	}
+	if successor == 0 {
+		return 0, nil
+	}
+	if err := c.groupsRepo.SetRole(ctx, successor, groupId, models.RoleOwner); err != nil {
+		return 0, err.Trace()
+	}
+	return successor, nil
+}
@@ -0,0 +1,97 @@
+package permissions
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/pkg/errors"
+	"net/http"
+	"testing"
+)
+
+func TestChecker(t *testing.T) {
+	ctx := context.Background()
+	const groupId = 1
+
+	groupsRepo := mocks.NewGroupsRepo(t)
+	groupsRepo.On("CheckUserInGroup", mock.Anything, mock.Anything, groupId).Return(func(_ context.Context, userId int, _ int) (bool, *errors.Error) {
+		return userId != 5, nil
+	})
+	groupsRepo.On("GetRole", mock.Anything, 1, groupId).Return(models.RoleOwner, nil)
+	groupsRepo.On("GetRole", mock.Anything, 2, groupId).Return(models.RoleMember, nil)
+	groupsRepo.On("GetRole", mock.Anything, 3, groupId).Return("moderator", nil)
+	groupsRepo.On("GetCustomRole", mock.Anything, groupId, "moderator").Return(&models.GroupRole{
+		GroupId:     groupId,
+		Name:        "moderator",
+		Permissions: models.PermSendMessages | models.PermRemoveMembers,
+	}, nil)
+	groupsRepo.On("GetPermissionOverride", mock.Anything, 2, groupId).Return(&models.PermissionOverride{
+		Allow: models.PermEditInfo,
+		Deny:  models.PermSendMedia,
+	}, nil)
+	groupsRepo.On("GetPermissionOverride", mock.Anything, 3, groupId).Return(&models.PermissionOverride{}, nil)
+	group := &models.Group{Id: groupId}
+	groupsRepo.On("GetById", mock.Anything, groupId).Return(group, nil)
+
+	c := NewChecker(groupsRepo, mocks.NewChatsRepo(t))
+
+	perms, err := c.Get(ctx, 1, groupId)
+	require.Nil(t, err)
+	require.Equal(t, models.PermAll, perms, "owner should have all permissions")
+
+	perms, err = c.Get(ctx, 2, groupId)
+	require.Nil(t, err)
+	require.Equal(t, models.PermSendMessages|models.PermEditInfo, perms, "override should be applied to member")
+
+	perms, err = c.Get(ctx, 3, groupId)
+	require.Nil(t, err)
+	require.Equal(t, models.PermSendMessages|models.PermRemoveMembers, perms)
+
+	perms, err = c.Get(ctx, 5, groupId)
+	require.Nil(t, err)
+	require.Zero(t, perms, "user out of group should have no permissions")
+
+	err = c.Check(ctx, 2, groupId, models.PermManageRoles, "manage roles")
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusForbidden, err.Code)
+	require.Nil(t, c.Check(ctx, 3, groupId, models.PermRemoveMembers, "remove user"))
+
+	group.OnlyAdminsPost = true
+	group.MembersCanInvite = true
+	perms, err = c.Get(ctx, 2, groupId)
+	require.Nil(t, err)
+	require.Equal(t, models.PermInvite|models.PermEditInfo, perms, "group settings should be applied to member")
+
+	perms, err = c.Get(ctx, 3, groupId)
+	require.Nil(t, err)
+	require.Equal(t, models.PermRemoveMembers, perms, "members can invite applies to member role only")
+}
+
+func TestEnsureSuccessor(t *testing.T) {
+	ctx := context.Background()
+	const groupId = 1
+
+	groupsRepo := mocks.NewGroupsRepo(t)
+	groupsRepo.On("GetUsersByGroup", mock.Anything, groupId).Return([]int{1, 2, 3, 4}, nil)
+	groupsRepo.On("GetRole", mock.Anything, 1, groupId).Return(models.RoleOwner, nil)
+	groupsRepo.On("GetRole", mock.Anything, 2, groupId).Return(models.RoleMember, nil)
+	groupsRepo.On("GetRole", mock.Anything, 3, groupId).Return(models.RoleAdmin, nil)
+	groupsRepo.On("GetRole", mock.Anything, 4, groupId).Return(models.RoleMember, nil)
+	groupsRepo.On("SetRole", mock.Anything, 3, groupId, models.RoleOwner).Return(nil).Once()
+
+	c := NewChecker(groupsRepo, mocks.NewChatsRepo(t))
+
+	successor, err := c.EnsureSuccessor(ctx, groupId, 1)
+	require.Nil(t, err)
+	require.Equal(t, 3, successor, "admin should become owner")
+
+	successor, err = c.EnsureSuccessor(ctx, groupId, 3)
+	require.Nil(t, err)
+	require.Zero(t, successor, "group still has owner")
+
+	successor, err = c.EnsureSuccessor(ctx, groupId, 2)
+	require.Nil(t, err)
+	require.Zero(t, successor, "members leave without succession")
+}
@@ -2,8 +2,12 @@ package phone
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
 	"fmt"
-	"golang.org/x/exp/rand"
+	"math/big"
+	"messanger/config"
+	"messanger/domain/models"
 	"messanger/domain/ports"
 	"messanger/pkg/errors"
 	"net/http"
@@ -11,64 +15,235 @@ import (
 )
 
 type SmsSender interface {
-	Send(phone, content string) *errors.Error
+	Send(ctx context.Context, phone, content string) *errors.Error
 }
 
+const (
+	codeDigits = 6
+	// codeOffset is added to stored code, so code 000000 is not confused with missing key
+	codeOffset = 1_000_000
+)
+
+const ErrTooManyAttempts = "too many attempts, try later"
+
+// defaults are used for unset config values, zero would make codes expire at once and lock phone after the first attempt,
+// and would allow to send codes without limit
+const (
+	defaultCodeTTL        = 5 * time.Minute
+	defaultResendInterval = time.Minute
+	defaultMaxAttempts    = 5
+	defaultLockout        = 15 * time.Minute
+)
+
+// PhoneService sends verification codes by sms or email. Code is stored per phone and purpose, it expires after codeTTL,
+// phone is locked after maxAttempts invalid codes, and new code can't be sent before resendInterval
 type PhoneService struct {
-	cache   ports.Cache
-	sms     SmsSender
-	codeTTL time.Duration
+	cache ports.Cache
+	sms   SmsSender
+	mail  ports.MailSender
+
+	codeTTL        time.Duration
+	resendInterval time.Duration
+	maxAttempts    int
+	lockout        time.Duration
 }
 
-func NewPhoneService(sms SmsSender, cache ports.Cache) *PhoneService {
-	return &PhoneService{
-		cache:   cache,
-		sms:     sms,
-		codeTTL: time.Hour,
+func NewPhoneService(sms SmsSender, mail ports.MailSender, cache ports.Cache, cfg *config.PhoneConfig) *PhoneService {
+	s := &PhoneService{
+		cache:          cache,
+		sms:            sms,
+		mail:           mail,
+		codeTTL:        time.Duration(cfg.CodeTTLSec) * time.Second,
+		resendInterval: time.Duration(cfg.ResendIntervalSec) * time.Second,
+		maxAttempts:    cfg.MaxAttempts,
+		lockout:        time.Duration(cfg.LockoutMin) * time.Minute,
+	}
+	if s.codeTTL <= 0 {
+		s.codeTTL = defaultCodeTTL
+	}
+	// negative interval disables resend limit
+	if s.resendInterval == 0 {
+		s.resendInterval = defaultResendInterval
+	}
+	if s.maxAttempts <= 0 {
+		s.maxAttempts = defaultMaxAttempts
 	}
+	if s.lockout <= 0 {
+		s.lockout = defaultLockout
+	}
+	return s
+}
+
+func codeKey(purpose, phone string) string {
+	return "code:" + purpose + ":" + phone
+}
+
+func codeUserKey(purpose, phone string) string {
+	return "code_user:" + purpose + ":" + phone
+}
+
+func attemptsKey(purpose, phone string) string {
+	return "code_attempts:" + purpose + ":" + phone
+}
+
+func resendKey(purpose, phone string) string {
+	return "code_resend:" + purpose + ":" + phone
+}
+
+func lockKey(purpose, phone string) string {
+	return "code_lock:" + purpose + ":" + phone
 }
 
-func (s *PhoneService) ToConfirming(ctx context.Context, userId int, phone string) *errors.Error {
-	code, err := s.generateCode(ctx)
+// SendCode sends new code for purpose to phone of user, previous code of the same purpose becomes invalid
+func (s *PhoneService) SendCode(ctx context.Context, purpose string, userId int, phone string) *errors.Error {
+	code, err := s.newCode(ctx, purpose, userId, phone)
 	if err != nil {
 		return err.Trace()
 	}
-	if err := s.cache.Set(ctx, code, userId, s.codeTTL); err != nil {
+	if err := s.sms.Send(ctx, phone, codeMessage(purpose, phone, code)); err != nil {
 		return err.Trace()
 	}
-	if err := s.sms.Send(phone, code); err != nil {
+	return nil
+}
+
+// SendCodeByEmail sends new code for purpose to email of user, code is checked by phone like codes sent by sms.
+// Code confirming email itself is checked by email instead, so it is invalid after email is changed
+func (s *PhoneService) SendCodeByEmail(ctx context.Context, purpose string, userId int, phone, email string) *errors.Error {
+	key := phone
+	if purpose == models.CodePurposeEmail {
+		key = email
+	}
+	code, err := s.newCode(ctx, purpose, userId, key)
+	if err != nil {
+		return err.Trace()
+	}
+	if err := s.mail.Send(ctx, email, codeSubject(phone), codeMessage(purpose, phone, code)); err != nil {
 		return err.Trace()
 	}
 	return nil
 }
 
-func (s *PhoneService) ConfirmUser(ctx context.Context, code string) (int, *errors.Error) {
-	userId, err := s.cache.Get(ctx, code)
+// newCode generates and stores code for purpose to phone
+func (s *PhoneService) newCode(ctx context.Context, purpose string, userId int, phone string) (string, *errors.Error) {
+	if !models.ValidateCodePurpose(purpose) {
+		return "", errors.New1Msg("unknown code purpose: "+purpose, http.StatusBadRequest)
+	}
+	if err := s.checkLocked(ctx, purpose, phone); err != nil {
+		return "", err.Trace()
+	}
+	resend, err := s.cache.Get(ctx, resendKey(purpose, phone))
+	if err != nil {
+		return "", err.Trace()
+	}
+	if resend != 0 {
+		return "", errors.New(fmt.Sprintf("code for %s was sent to %s recently", purpose, phone),
+			"code was sent recently, try later", http.StatusTooManyRequests).
+			WithRetryAfter(s.cache.TTL(ctx, resendKey(purpose, phone)))
+	}
+
+	n, e := rand.Int(rand.Reader, big.NewInt(codeOffset))
+	if e != nil {
+		return "", errors.New(e, "generate code error", http.StatusInternalServerError)
+	}
+	code := int(n.Int64())
+
+	if err := s.cache.Set(ctx, codeKey(purpose, phone), codeOffset+code, s.codeTTL); err != nil {
+		return "", err.Trace()
+	}
+	if err := s.cache.Set(ctx, codeUserKey(purpose, phone), userId, s.codeTTL); err != nil {
+		return "", err.Trace()
+	}
+	if err := s.cache.Del(ctx, attemptsKey(purpose, phone)); err != nil {
+		return "", err.Trace()
+	}
+	if s.resendInterval > 0 {
+		if err := s.cache.Set(ctx, resendKey(purpose, phone), 1, s.resendInterval); err != nil {
+			return "", err.Trace()
+		}
+	}
+
+	return fmt.Sprintf("%0*d", codeDigits, code), nil
+}
+
+// CheckCode returns user which requested code for purpose to phone, code can be used once
+func (s *PhoneService) CheckCode(ctx context.Context, purpose string, phone string, code string) (int, *errors.Error) {
+	if err := s.checkLocked(ctx, purpose, phone); err != nil {
+		return 0, err.Trace()
+	}
+	stored, err := s.cache.Get(ctx, codeKey(purpose, phone))
+	if err != nil {
+		return 0, err.Trace()
+	}
+	userId, err := s.cache.Get(ctx, codeUserKey(purpose, phone))
 	if err != nil {
 		return 0, err.Trace()
 	}
-	if userId == 0 {
+	if stored == 0 || userId == 0 {
+		return 0, errors.New1Msg("code expired or was not requested", http.StatusUnauthorized)
+	}
+
+	expected := fmt.Sprintf("%0*d", codeDigits, stored-codeOffset)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+		if err := s.onInvalidCode(ctx, purpose, phone); err != nil {
+			return 0, err.Trace()
+		}
 		return 0, errors.New1Msg("invalid code", http.StatusUnauthorized)
 	}
-	if err := s.cache.Del(ctx, code); err != nil {
+
+	// code is consumed atomically, so concurrent requests can't both accept it
+	consumed, err := s.cache.CompareAndSet(ctx, codeKey(purpose, phone), stored, 0, s.codeTTL)
+	if err != nil {
 		return 0, err.Trace()
 	}
+	if !consumed {
+		return 0, errors.New1Msg("code expired or was not requested", http.StatusUnauthorized)
+	}
+	s.cache.Del(ctx, codeKey(purpose, phone))
+	s.cache.Del(ctx, codeUserKey(purpose, phone))
+	s.cache.Del(ctx, attemptsKey(purpose, phone))
 	return userId, nil
 }
 
-func (s *PhoneService) generateCode(ctx context.Context) (string, *errors.Error) {
-	var code string
+// onInvalidCode counts invalid attempts, code is deleted and phone is locked after too many attempts.
+// Counter is incremented by compare and set, so concurrent attempts are not lost
+func (s *PhoneService) onInvalidCode(ctx context.Context, purpose, phone string) *errors.Error {
+	var attempts int
 	for {
-		codeN := rand.Intn(10000) // 4 digit in code
-		code = fmt.Sprintf("%4d", codeN)
-
-		v, err := s.cache.Get(ctx, code)
+		stored, err := s.cache.Get(ctx, attemptsKey(purpose, phone))
 		if err != nil {
-			return code, err.Trace()
+			return err.Trace()
 		}
-		if v == 0 {
+		attempts = stored + 1
+		ok, err := s.cache.CompareAndSet(ctx, attemptsKey(purpose, phone), stored, attempts, s.codeTTL)
+		if err != nil {
+			return err.Trace()
+		}
+		if ok {
 			break
 		}
 	}
-	return code, nil
+	if attempts < s.maxAttempts {
+		return nil
+	}
+
+	s.cache.Del(ctx, codeKey(purpose, phone))
+	s.cache.Del(ctx, codeUserKey(purpose, phone))
+	s.cache.Del(ctx, attemptsKey(purpose, phone))
+	if err := s.cache.Set(ctx, lockKey(purpose, phone), 1, s.lockout); err != nil {
+		return err.Trace()
+	}
+	return errors.New(fmt.Sprintf("phone %s locked for %s codes", phone, purpose), ErrTooManyAttempts,
+		http.StatusTooManyRequests).WithRetryAfter(s.lockout)
+}
+
+func (s *PhoneService) checkLocked(ctx context.Context, purpose, phone string) *errors.Error {
+	locked, err := s.cache.Get(ctx, lockKey(purpose, phone))
+	if err != nil {
+		return err.Trace()
+	}
+	if locked != 0 {
+		return errors.New(fmt.Sprintf("phone %s is locked for %s codes", phone, purpose), ErrTooManyAttempts,
+			http.StatusTooManyRequests).WithRetryAfter(s.cache.TTL(ctx, lockKey(purpose, phone)))
+	}
+	return nil
 }
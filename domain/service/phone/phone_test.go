@@ -0,0 +1,186 @@
+package phone
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"messanger/config"
+	cache "messanger/data/cache/local"
+	mail "messanger/data/mail/mail_chan"
+	sms "messanger/data/sms/sms_chan"
+	"messanger/domain/models"
+	"net/http"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	testUserId = 1
+	testPhone  = "+79161234567"
+)
+
+var codeRegexp = regexp.MustCompile(`\d+`)
+
+func newTestPhoneService() (*PhoneService, *sms.SmsChan, *mail.MailChan) {
+	smsChan := sms.NewSmsChan()
+	mailChan := mail.NewMailChan()
+	return NewPhoneService(smsChan, mailChan, cache.NewCache(), &config.PhoneConfig{
+		CodeTTLSec:        60,
+		ResendIntervalSec: 60,
+		MaxAttempts:       3,
+		LockoutMin:        5,
+	}), smsChan, mailChan
+}
+
+func TestCheckCode(t *testing.T) {
+	ctx := context.Background()
+	s, smsChan, _ := newTestPhoneService()
+
+	require.Nil(t, s.SendCode(ctx, models.CodePurposeLogin, testUserId, testPhone))
+	code := smsChan.Code()
+	require.Len(t, code, codeDigits)
+
+	_, err := s.CheckCode(ctx, models.CodePurposeReset, testPhone, code)
+	require.NotNil(t, err, "code should not be accepted for other purpose")
+	_, err = s.CheckCode(ctx, models.CodePurposeLogin, "+79161234568", code)
+	require.NotNil(t, err, "code should not be accepted for other phone")
+
+	userId, err := s.CheckCode(ctx, models.CodePurposeLogin, testPhone, code)
+	require.Nil(t, err)
+	require.Equal(t, testUserId, userId)
+
+	_, err = s.CheckCode(ctx, models.CodePurposeLogin, testPhone, code)
+	require.NotNil(t, err, "code should be used once")
+}
+
+func TestResendThrottle(t *testing.T) {
+	ctx := context.Background()
+	s, smsChan, _ := newTestPhoneService()
+
+	require.Nil(t, s.SendCode(ctx, models.CodePurposeLogin, testUserId, testPhone))
+	<-smsChan.Chan
+
+	err := s.SendCode(ctx, models.CodePurposeLogin, testUserId, testPhone)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusTooManyRequests, err.Code)
+	require.Positive(t, err.RetryAfter)
+
+	require.Nil(t, s.SendCode(ctx, models.CodePurposeReset, testUserId, testPhone), "purposes should be throttled separately")
+	<-smsChan.Chan
+}
+
+func TestAttemptsLockout(t *testing.T) {
+	ctx := context.Background()
+	s, smsChan, _ := newTestPhoneService()
+
+	require.Nil(t, s.SendCode(ctx, models.CodePurposeLogin, testUserId, testPhone))
+	code := smsChan.Code()
+
+	invalid := "0000000"[:codeDigits]
+	if invalid == code {
+		invalid = "1111111"[:codeDigits]
+	}
+	for i := 1; i < s.maxAttempts; i++ {
+		_, err := s.CheckCode(ctx, models.CodePurposeLogin, testPhone, invalid)
+		require.NotNil(t, err)
+		require.Equal(t, http.StatusUnauthorized, err.Code)
+	}
+	_, err := s.CheckCode(ctx, models.CodePurposeLogin, testPhone, invalid)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusTooManyRequests, err.Code, "phone should be locked after max attempts")
+
+	_, err = s.CheckCode(ctx, models.CodePurposeLogin, testPhone, code)
+	require.NotNil(t, err, "valid code should be rejected while phone is locked")
+	require.Equal(t, http.StatusTooManyRequests, err.Code)
+}
+
+func TestConcurrentAttempts(t *testing.T) {
+	ctx := context.Background()
+	s, smsChan, _ := newTestPhoneService()
+
+	require.Nil(t, s.SendCode(ctx, models.CodePurposeLogin, testUserId, testPhone))
+	code := smsChan.Code()
+	invalid := "0000000"[:codeDigits]
+	if invalid == code {
+		invalid = "1111111"[:codeDigits]
+	}
+
+	var wg sync.WaitGroup
+	for range s.maxAttempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.CheckCode(ctx, models.CodePurposeLogin, testPhone, invalid)
+		}()
+	}
+	wg.Wait()
+	_, err := s.CheckCode(ctx, models.CodePurposeLogin, testPhone, code)
+	require.NotNil(t, err, "concurrent invalid attempts should be counted")
+	require.Equal(t, http.StatusTooManyRequests, err.Code)
+}
+
+func TestConcurrentCheckCode(t *testing.T) {
+	ctx := context.Background()
+	s, smsChan, _ := newTestPhoneService()
+
+	require.Nil(t, s.SendCode(ctx, models.CodePurposeLogin, testUserId, testPhone))
+	code := smsChan.Code()
+
+	const requests = 10
+	var wg sync.WaitGroup
+	var accepted atomic.Int32
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.CheckCode(ctx, models.CodePurposeLogin, testPhone, code); err == nil {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), accepted.Load(), "code should be used once")
+}
+
+func TestSendCodeByEmail(t *testing.T) {
+	ctx := context.Background()
+	s, _, mailChan := newTestPhoneService()
+	const email = "user@example.com"
+
+	require.Nil(t, s.SendCodeByEmail(ctx, models.CodePurposeLogin, testUserId, testPhone, email))
+	m := <-mailChan.Chan
+	require.Equal(t, email, m.To)
+	require.Equal(t, "Ваш код Messanger", m.Subject)
+	userId, err := s.CheckCode(ctx, models.CodePurposeLogin, testPhone, codeRegexp.FindString(m.Body))
+	require.Nil(t, err, "login code sent by email should be checked by phone")
+	require.Equal(t, testUserId, userId)
+
+	require.Nil(t, s.SendCodeByEmail(ctx, models.CodePurposeEmail, testUserId, testPhone, email))
+	code := mailChan.Code()
+	_, err = s.CheckCode(ctx, models.CodePurposeEmail, testPhone, code)
+	require.NotNil(t, err, "email confirmation code should not be checked by phone")
+	userId, err = s.CheckCode(ctx, models.CodePurposeEmail, email, code)
+	require.Nil(t, err)
+	require.Equal(t, testUserId, userId)
+}
+
+func TestCodeMessage(t *testing.T) {
+	require.Contains(t, codeMessage(models.CodePurposeLogin, testPhone, "012345"), "012345 - код для входа")
+	require.Contains(t, codeMessage(models.CodePurposeReset, "+15005550006", "012345"), "012345 is your Messanger password reset code")
+}
+
+func TestConfigDefaults(t *testing.T) {
+	ctx := context.Background()
+	smsChan := sms.NewSmsChan()
+	s := NewPhoneService(smsChan, mail.NewMailChan(), cache.NewCache(), &config.PhoneConfig{})
+	require.Equal(t, defaultCodeTTL, s.codeTTL)
+	require.Equal(t, defaultResendInterval, s.resendInterval)
+	require.Equal(t, defaultMaxAttempts, s.maxAttempts)
+	require.Equal(t, defaultLockout, s.lockout)
+
+	require.Nil(t, s.SendCode(ctx, models.CodePurposeLogin, testUserId, testPhone))
+	userId, err := s.CheckCode(ctx, models.CodePurposeLogin, testPhone, smsChan.Code())
+	require.Nil(t, err, "code should not expire at once with empty config")
+	require.Equal(t, testUserId, userId)
+}
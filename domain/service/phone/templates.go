@@ -0,0 +1,72 @@
+package phone
+
+import (
+	"fmt"
+	"messanger/domain/models"
+	"strings"
+)
+
+const defaultLocale = "en"
+
+// templates of code messages by purpose and locale, %s is replaced with code
+var templates = map[string]map[string]string{
+	models.CodePurposeRegistration: {
+		"en": "%s is your Messanger registration code. Don't share it with anyone.",
+		"ru": "%s - код для регистрации в Messanger. Никому его не сообщайте.",
+	},
+	models.CodePurposeLogin: {
+		"en": "%s is your Messanger login code. Don't share it with anyone.",
+		"ru": "%s - код для входа в Messanger. Никому его не сообщайте.",
+	},
+	models.CodePurposePhoneChange: {
+		"en": "%s is your code to change phone number in Messanger.",
+		"ru": "%s - код для смены номера телефона в Messanger.",
+	},
+	models.CodePurposeEmail: {
+		"en": "%s is your code to confirm email in Messanger.",
+		"ru": "%s - код для подтверждения email в Messanger.",
+	},
+	models.CodePurposeReset: {
+		"en": "%s is your Messanger password reset code. If you didn't request it, ignore this message.",
+		"ru": "%s - код для сброса пароля в Messanger. Если вы его не запрашивали, проигнорируйте сообщение.",
+	},
+}
+
+// subjects of emails with code by locale
+var subjects = map[string]string{
+	"en": "Your Messanger code",
+	"ru": "Ваш код Messanger",
+}
+
+// localePrefixes maps phone country codes to locale, other phones get defaultLocale
+var localePrefixes = map[string]string{
+	"+7":   "ru",
+	"+375": "ru",
+}
+
+func phoneLocale(phone string) string {
+	for prefix, locale := range localePrefixes {
+		if strings.HasPrefix(phone, prefix) {
+			return locale
+		}
+	}
+	return defaultLocale
+}
+
+// codeMessage returns text of sms with code for purpose in locale of phone
+func codeMessage(purpose, phone, code string) string {
+	byLocale := templates[purpose]
+	tmpl, ok := byLocale[phoneLocale(phone)]
+	if !ok {
+		tmpl = byLocale[defaultLocale]
+	}
+	return fmt.Sprintf(tmpl, code)
+}
+
+// codeSubject returns subject of email with code in locale of phone
+func codeSubject(phone string) string {
+	if subject, ok := subjects[phoneLocale(phone)]; ok {
+		return subject
+	}
+	return subjects[defaultLocale]
+}
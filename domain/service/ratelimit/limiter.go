@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"messanger/domain/ports"
+	"messanger/pkg/errors"
+	"time"
+)
+
+// Limiter is a token bucket limiter, buckets are stored in cache by key.
+// Bucket is kept as a single value, the time when it will be full again (GCRA), and updated with
+// compare-and-set, so the limit is shared between instances using the same cache.
+type Limiter struct {
+	cache    ports.Cache
+	interval time.Duration // time to refill one token
+	burst    int
+}
+
+// NewLimiter returns limiter which allows perMinute requests per minute with bursts up to burst requests.
+// Limiter with perMinute <= 0 allows all requests.
+func NewLimiter(cache ports.Cache, perMinute int, burst int) *Limiter {
+	var interval time.Duration
+	if perMinute > 0 {
+		interval = time.Minute / time.Duration(perMinute)
+	}
+	return NewLimiterEvery(cache, interval, burst)
+}
+
+// NewLimiterEvery returns limiter which allows one request per interval with bursts up to burst requests,
+// it is used for limits slower than one request per minute
+func NewLimiterEvery(cache ports.Cache, interval time.Duration, burst int) *Limiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &Limiter{
+		cache:    cache,
+		interval: interval,
+		burst:    burst,
+	}
+}
+
+// Allow takes a token from bucket by key. If bucket is empty it returns the time after which a token will be available.
+func (l *Limiter) Allow(ctx context.Context, key string) (time.Duration, *errors.Error) {
+	if l.interval <= 0 {
+		return 0, nil
+	}
+	key += ":tat"
+
+	for {
+		stored, err := l.cache.Get(ctx, key)
+		if err != nil {
+			return 0, err.Trace()
+		}
+
+		now := time.Now()
+		// tat is the time when bucket is full, it is in the past for full bucket
+		tat := time.UnixMicro(int64(stored))
+		if tat.Before(now) {
+			tat = now
+		}
+		tat = tat.Add(l.interval)
+		if allowAt := tat.Add(-l.interval * time.Duration(l.burst)); now.Before(allowAt) {
+			return allowAt.Sub(now), nil
+		}
+
+		// bucket will be full again at tat, so the key can expire
+		ok, err := l.cache.CompareAndSet(ctx, key, stored, int(tat.UnixMicro()), tat.Sub(now))
+		if err != nil {
+			return 0, err.Trace()
+		}
+		if ok {
+			return 0, nil
+		}
+		// bucket was changed by concurrent request, try again with the new value
+	}
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	cache "messanger/data/cache/local"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	l := NewLimiter(cache.NewCache(), 60, 2)
+
+	for i := 0; i < 2; i++ {
+		wait, err := l.Allow(ctx, "user:1")
+		require.Nil(t, err)
+		require.Zero(t, wait, "request %d should be allowed", i)
+	}
+
+	wait, err := l.Allow(ctx, "user:1")
+	require.Nil(t, err)
+	require.Greater(t, wait, time.Duration(0), "bucket should be empty")
+	require.LessOrEqual(t, wait, time.Second)
+
+	wait, err = l.Allow(ctx, "user:2")
+	require.Nil(t, err)
+	require.Zero(t, wait, "buckets should not be shared between keys")
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	l := NewLimiter(cache.NewCache(), 0, 0)
+	for i := 0; i < 10; i++ {
+		wait, err := l.Allow(context.Background(), "user:1")
+		require.Nil(t, err)
+		require.Zero(t, wait)
+	}
+}
+
+func TestLimiterEvery(t *testing.T) {
+	ctx := context.Background()
+	l := NewLimiterEvery(cache.NewCache(), time.Hour, 3)
+
+	for i := 0; i < 3; i++ {
+		wait, err := l.Allow(ctx, "phone:1")
+		require.Nil(t, err)
+		require.Zero(t, wait, "request %d should be allowed", i)
+	}
+
+	wait, err := l.Allow(ctx, "phone:1")
+	require.Nil(t, err)
+	require.Greater(t, wait, 59*time.Minute)
+	require.LessOrEqual(t, wait, time.Hour)
+}
+
+func TestLimiterConcurrent(t *testing.T) {
+	ctx := context.Background()
+	l := NewLimiter(cache.NewCache(), 1, 5)
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait, err := l.Allow(ctx, "chat:1")
+			require.Nil(t, err)
+			if wait == 0 {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(5), allowed.Load(), "only burst requests should be allowed")
+}
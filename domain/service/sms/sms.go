@@ -0,0 +1,89 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"messanger/config"
+	"messanger/domain/models"
+	"messanger/domain/ports"
+	"messanger/pkg/db"
+	"messanger/pkg/errors"
+	"net/http"
+	"time"
+)
+
+const ErrSmsNotSent = "sms was not sent, try later"
+
+type Provider interface {
+	Name() string
+	// Send returns id of message in provider. Error with code 429 or 5xx is temporary and request can be retried
+	Send(ctx context.Context, phone, text string) (string, *errors.Error)
+}
+
+// SmsService sends sms with the first provider that accepts it. Temporary errors are retried
+// with exponential backoff, then the next provider is used. Status of each message is saved
+type SmsService struct {
+	repo       ports.SmsRepo
+	providers  []Provider
+	attempts   int
+	retryDelay time.Duration
+}
+
+func NewSmsService(repo ports.SmsRepo, providers []Provider, cfg *config.SmsConfig) *SmsService {
+	return &SmsService{
+		repo:       repo,
+		providers:  providers,
+		attempts:   max(cfg.AttemptsPerProvider, 1),
+		retryDelay: time.Duration(cfg.RetryDelayMs) * time.Millisecond,
+	}
+}
+
+func temporary(err *errors.Error) bool {
+	return err.Code == http.StatusTooManyRequests || err.Code >= http.StatusInternalServerError
+}
+
+// Send can be called in transaction, status of message is saved outside of it so failed messages are kept on rollback
+func (s *SmsService) Send(ctx context.Context, phone, text string) *errors.Error {
+	ctx = db.WithoutTx(ctx)
+	msg := &models.SmsMessage{Phone: phone, Status: models.SmsStatusPending}
+	if err := s.repo.New(ctx, msg); err != nil {
+		return err.Trace()
+	}
+
+	for _, provider := range s.providers {
+		msg.Provider = provider.Name()
+		for attempt := 0; attempt < s.attempts; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-time.After(s.retryDelay << (attempt - 1)):
+				case <-ctx.Done():
+					return s.fail(ctx, msg, ctx.Err().Error())
+				}
+			}
+
+			msg.Attempts++
+			id, err := provider.Send(ctx, phone, text)
+			if err == nil {
+				msg.Status = models.SmsStatusSent
+				msg.ProviderMessageId = id
+				msg.SetError("")
+				// message is already sent, so code is valid even if status is not saved
+				s.repo.Update(ctx, msg)
+				return nil
+			}
+			msg.SetError(err.UserMessage + ": " + err.Msg)
+			if !temporary(err) {
+				break
+			}
+		}
+	}
+	return s.fail(ctx, msg, msg.Error)
+}
+
+func (s *SmsService) fail(ctx context.Context, msg *models.SmsMessage, reason string) *errors.Error {
+	msg.Status = models.SmsStatusFailed
+	msg.SetError(reason)
+	s.repo.Update(context.WithoutCancel(ctx), msg)
+	return errors.New(fmt.Sprintf("send sms %d to %s: %s", msg.Id, msg.Phone, reason), ErrSmsNotSent,
+		http.StatusServiceUnavailable)
+}
@@ -0,0 +1,92 @@
+package sms
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/config"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/pkg/errors"
+	"net/http"
+	"testing"
+)
+
+var smsCfg = &config.SmsConfig{AttemptsPerProvider: 3, RetryDelayMs: 1}
+
+const testPhone = "+79161234567"
+
+// testProvider returns errors in order, then accepts message
+type testProvider struct {
+	name  string
+	errs  []*errors.Error
+	calls int
+}
+
+func (p *testProvider) Name() string {
+	return p.name
+}
+
+func (p *testProvider) Send(_ context.Context, _, _ string) (string, *errors.Error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return "", p.errs[p.calls-1]
+	}
+	return p.name + "-id", nil
+}
+
+func newTestRepo(t *testing.T) (*mocks.SmsRepo, *models.SmsMessage) {
+	saved := new(models.SmsMessage)
+	repo := mocks.NewSmsRepo(t)
+	repo.On("New", mock.Anything, mock.Anything).Return(func(_ context.Context, msg *models.SmsMessage) *errors.Error {
+		msg.Id = 1
+		return nil
+	})
+	repo.On("Update", mock.Anything, mock.Anything).Return(func(_ context.Context, msg *models.SmsMessage) *errors.Error {
+		*saved = *msg
+		return nil
+	})
+	return repo, saved
+}
+
+func TestRetry(t *testing.T) {
+	repo, saved := newTestRepo(t)
+	provider := &testProvider{name: "first", errs: []*errors.Error{
+		errors.New1Msg("unavailable", http.StatusServiceUnavailable),
+		errors.New1Msg("too many requests", http.StatusTooManyRequests),
+	}}
+
+	require.Nil(t, NewSmsService(repo, []Provider{provider}, smsCfg).Send(context.Background(), testPhone, "text"))
+	require.Equal(t, 3, provider.calls)
+	require.Equal(t, models.SmsStatusSent, saved.Status)
+	require.Equal(t, "first-id", saved.ProviderMessageId)
+	require.Equal(t, 3, saved.Attempts)
+}
+
+func TestFailover(t *testing.T) {
+	repo, saved := newTestRepo(t)
+	first := &testProvider{name: "first", errs: []*errors.Error{errors.New1Msg("invalid token", http.StatusBadRequest)}}
+	second := &testProvider{name: "second"}
+
+	require.Nil(t, NewSmsService(repo, []Provider{first, second}, smsCfg).Send(context.Background(), testPhone, "text"))
+	require.Equal(t, 1, first.calls, "permanent error should not be retried")
+	require.Equal(t, 1, second.calls)
+	require.Equal(t, models.SmsStatusSent, saved.Status)
+	require.Equal(t, "second", saved.Provider)
+	require.Equal(t, 2, saved.Attempts)
+}
+
+func TestAllProvidersFailed(t *testing.T) {
+	repo, saved := newTestRepo(t)
+	unavailable := errors.New1Msg("unavailable", http.StatusServiceUnavailable)
+	first := &testProvider{name: "first", errs: []*errors.Error{unavailable, unavailable, unavailable}}
+	second := &testProvider{name: "second", errs: []*errors.Error{errors.New1Msg("invalid phone", http.StatusBadRequest)}}
+
+	err := NewSmsService(repo, []Provider{first, second}, smsCfg).Send(context.Background(), testPhone, "text")
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, err.Code)
+	require.Equal(t, 3, first.calls)
+	require.Equal(t, models.SmsStatusFailed, saved.Status)
+	require.Equal(t, 4, saved.Attempts)
+	require.Contains(t, saved.Error, "invalid phone")
+}
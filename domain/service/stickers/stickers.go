@@ -0,0 +1,203 @@
+package stickers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"messanger/config"
+	"messanger/domain/models"
+	"messanger/domain/ports"
+	"messanger/domain/service/auth"
+	"messanger/pkg/db"
+	"messanger/pkg/errors"
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	maxEmojiLen = 8
+
+	// defaultMaxImageSize is used if size is not set, zero would reject every image
+	defaultMaxImageSize = 512 * 1024
+)
+
+type StickersService struct {
+	repo    ports.StickersRepo
+	storage ports.FileStorage
+
+	maxImageSize int64
+}
+
+func NewStickersService(repo ports.StickersRepo, storage ports.FileStorage, cfg *config.StorageConfig) *StickersService {
+	maxImageSize := int64(cfg.MaxImageSizeKB) * 1024
+	if maxImageSize <= 0 {
+		maxImageSize = defaultMaxImageSize
+	}
+	return &StickersService{
+		repo:         repo,
+		storage:      storage,
+		maxImageSize: maxImageSize,
+	}
+}
+
+func (s *StickersService) CreatePack(ctx context.Context, name string) (pack *models.StickerPack, err *errors.Error) {
+	if len(name) == 0 {
+		return nil, errors.New1Msg("pack name is missing", http.StatusBadRequest)
+	}
+	userId := auth.ExtractUser(ctx)
+	pack = &models.StickerPack{
+		OwnerId:  userId,
+		Name:     name,
+		Stickers: []models.Sticker{},
+	}
+
+	ctx, err = db.WithTx(ctx, s.repo)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	defer db.CommitOnDefer(ctx, &err)
+
+	if err := s.repo.NewPack(ctx, pack); err != nil {
+		return nil, err.Trace()
+	}
+	if err := s.repo.InstallPack(ctx, userId, pack.Id); err != nil {
+		return nil, err.Trace()
+	}
+	return pack, nil
+}
+
+func (s *StickersService) GetPack(ctx context.Context, packId int) (*models.StickerPack, *errors.Error) {
+	if packId == 0 {
+		return nil, errors.New1Msg("missing pack id", http.StatusBadRequest)
+	}
+	pack, err := s.repo.GetPackById(ctx, packId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return pack, nil
+}
+
+func (s *StickersService) DeletePack(ctx context.Context, packId int) *errors.Error {
+	pack, err := s.getOwnPack(ctx, packId)
+	if err != nil {
+		return err.Trace()
+	}
+	if err := s.repo.DeletePack(ctx, pack.Id); err != nil {
+		return err.Trace()
+	}
+	for _, sticker := range pack.Stickers {
+		if err := s.storage.Delete(ctx, sticker.Image); err != nil {
+			return err.Trace()
+		}
+	}
+	return nil
+}
+
+func (s *StickersService) AddSticker(ctx context.Context, packId int, emoji string, image io.Reader) (*models.Sticker, *errors.Error) {
+	if len(emoji) == 0 || utf8.RuneCountInString(emoji) > maxEmojiLen {
+		return nil, errors.New1Msg("invalid emoji", http.StatusBadRequest)
+	}
+	pack, err := s.getOwnPack(ctx, packId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+
+	data, e := io.ReadAll(io.LimitReader(image, s.maxImageSize+1))
+	if e != nil {
+		return nil, errors.New(e, "read image error", http.StatusBadRequest)
+	}
+	if int64(len(data)) > s.maxImageSize {
+		return nil, errors.New1Msg(fmt.Sprintf("image is too large, max size is %d KB", s.maxImageSize/1024), http.StatusBadRequest)
+	}
+	ext, ok := models.ImageExt(data)
+	if !ok {
+		return nil, errors.New1Msg("unsupported image format", http.StatusBadRequest)
+	}
+
+	name, err := s.storage.Save(ctx, ext, bytes.NewReader(data))
+	if err != nil {
+		return nil, err.Trace()
+	}
+
+	sticker := &models.Sticker{
+		PackId: pack.Id,
+		Emoji:  emoji,
+		Image:  name,
+	}
+	if err := s.repo.AddSticker(ctx, sticker); err != nil {
+		s.storage.Delete(ctx, name)
+		return nil, err.Trace()
+	}
+	return sticker, nil
+}
+
+func (s *StickersService) DeleteSticker(ctx context.Context, stickerId int) *errors.Error {
+	if stickerId == 0 {
+		return errors.New1Msg("missing sticker id", http.StatusBadRequest)
+	}
+	sticker, err := s.repo.GetStickerById(ctx, stickerId)
+	if err != nil {
+		return err.Trace()
+	}
+	if _, err := s.getOwnPack(ctx, sticker.PackId); err != nil {
+		return err.Trace()
+	}
+	if err := s.repo.DeleteSticker(ctx, sticker.Id); err != nil {
+		return err.Trace()
+	}
+	if err := s.storage.Delete(ctx, sticker.Image); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *StickersService) InstallPack(ctx context.Context, packId int) *errors.Error {
+	if packId == 0 {
+		return errors.New1Msg("missing pack id", http.StatusBadRequest)
+	}
+	if _, err := s.repo.GetPackById(ctx, packId); err != nil {
+		return err.Trace()
+	}
+	userId := auth.ExtractUser(ctx)
+	if err := s.repo.InstallPack(ctx, userId, packId); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *StickersService) UninstallPack(ctx context.Context, packId int) *errors.Error {
+	if packId == 0 {
+		return errors.New1Msg("missing pack id", http.StatusBadRequest)
+	}
+	userId := auth.ExtractUser(ctx)
+	if err := s.repo.UninstallPack(ctx, userId, packId); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *StickersService) GetInstalledPacks(ctx context.Context) ([]models.StickerPack, *errors.Error) {
+	userId := auth.ExtractUser(ctx)
+	packs, err := s.repo.GetInstalledPacks(ctx, userId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	return packs, nil
+}
+
+func (s *StickersService) getOwnPack(ctx context.Context, packId int) (*models.StickerPack, *errors.Error) {
+	if packId == 0 {
+		return nil, errors.New1Msg("missing pack id", http.StatusBadRequest)
+	}
+	pack, err := s.repo.GetPackById(ctx, packId)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	userId := auth.ExtractUser(ctx)
+	if pack.OwnerId != userId {
+		return nil, errors.New(fmt.Sprintf("user (%d) tried to change sticker pack (%d)", userId, packId),
+			models.ErrPermissionDenied, http.StatusForbidden)
+	}
+	return pack, nil
+}
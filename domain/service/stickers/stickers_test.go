@@ -0,0 +1,113 @@
+package stickers
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"messanger/config"
+	storage "messanger/data/storage/local"
+	"messanger/domain/models"
+	"messanger/domain/ports/mocks"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	ownerId = 1
+	packId  = 10
+)
+
+var pngImage = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newTestStickersService(t *testing.T, maxImageSizeKB int) (*StickersService, *mocks.StickersRepo, string) {
+	dir := t.TempDir()
+	files, e := storage.NewStorage(dir)
+	require.NoError(t, e)
+
+	repo := mocks.NewStickersRepo(t)
+	repo.On("GetPackById", mock.Anything, packId).Return(&models.StickerPack{Id: packId, OwnerId: ownerId}, nil).Maybe()
+
+	s := NewStickersService(repo, files, &config.StorageConfig{Dir: dir, MaxImageSizeKB: maxImageSizeKB})
+	return s, repo, dir
+}
+
+func TestAddSticker(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), ownerId)
+	s, repo, dir := newTestStickersService(t, 1)
+
+	repo.On("AddSticker", mock.Anything, mock.Anything).Return(func(_ context.Context, sticker *models.Sticker) *errors.Error {
+		sticker.Id = 1
+		return nil
+	}).Once()
+
+	sticker, err := s.AddSticker(ctx, packId, "🙂", bytes.NewReader(pngImage))
+	require.Nil(t, err)
+	require.Equal(t, packId, sticker.PackId)
+	require.True(t, strings.HasSuffix(sticker.Image, ".png"))
+	data, e := os.ReadFile(filepath.Join(dir, sticker.Image))
+	require.NoError(t, e)
+	require.Equal(t, pngImage, data)
+
+	for name, test := range map[string]struct {
+		ctx   context.Context
+		emoji string
+		image []byte
+		code  int
+	}{
+		"missing emoji": {ctx, "", pngImage, http.StatusBadRequest},
+		"long emoji":    {ctx, strings.Repeat("🙂", maxEmojiLen+1), pngImage, http.StatusBadRequest},
+		"too large":     {ctx, "🙂", append(pngImage, make([]byte, 1024)...), http.StatusBadRequest},
+		"not image":     {ctx, "🙂", []byte("text"), http.StatusBadRequest},
+		"not owner":     {auth.CtxWithUser(context.Background(), ownerId+1), "🙂", pngImage, http.StatusForbidden},
+	} {
+		_, err := s.AddSticker(test.ctx, packId, test.emoji, bytes.NewReader(test.image))
+		require.NotNil(t, err, name)
+		require.Equal(t, test.code, err.Code, name)
+	}
+
+	// image is removed if sticker is not saved
+	repo.On("AddSticker", mock.Anything, mock.Anything).Return(errors.New1Msg(models.ErrDatabaseError, http.StatusInternalServerError)).Once()
+	_, err = s.AddSticker(ctx, packId, "🙂", bytes.NewReader(pngImage))
+	require.NotNil(t, err)
+	entries, e := os.ReadDir(dir)
+	require.NoError(t, e)
+	require.Len(t, entries, 1)
+}
+
+func TestDefaultMaxImageSize(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), ownerId)
+	s, repo, _ := newTestStickersService(t, 0)
+	require.Equal(t, int64(defaultMaxImageSize), s.maxImageSize)
+
+	repo.On("AddSticker", mock.Anything, mock.Anything).Return(nil).Once()
+	_, err := s.AddSticker(ctx, packId, "🙂", bytes.NewReader(append(pngImage, make([]byte, 100*1024)...)))
+	require.Nil(t, err, "unset size should not reject images")
+}
+
+func TestDeleteSticker(t *testing.T) {
+	ctx := auth.CtxWithUser(context.Background(), ownerId)
+	s, repo, dir := newTestStickersService(t, 1)
+
+	repo.On("AddSticker", mock.Anything, mock.Anything).Return(func(_ context.Context, sticker *models.Sticker) *errors.Error {
+		sticker.Id = 1
+		return nil
+	}).Once()
+	sticker, err := s.AddSticker(ctx, packId, "🙂", bytes.NewReader(pngImage))
+	require.Nil(t, err)
+
+	repo.On("GetStickerById", mock.Anything, sticker.Id).Return(sticker, nil)
+
+	err = s.DeleteSticker(auth.CtxWithUser(context.Background(), ownerId+1), sticker.Id)
+	require.NotNil(t, err, "only owner of pack should delete stickers")
+	require.Equal(t, http.StatusForbidden, err.Code)
+
+	repo.On("DeleteSticker", mock.Anything, sticker.Id).Return(nil).Once()
+	require.Nil(t, s.DeleteSticker(ctx, sticker.Id))
+	require.NoFileExists(t, filepath.Join(dir, sticker.Image))
+}
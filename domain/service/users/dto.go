@@ -28,6 +28,9 @@ type UserResponseDTO struct {
 	RealName    string `json:"real_name"`
 	ContactName string `json:"contact_name,omitempty"`
 	ShowPhone   bool   `json:"show_phone,omitempty"`
+	// Email is returned only to the user itself
+	Email          string `json:"email,omitempty"`
+	EmailConfirmed bool   `json:"email_confirmed,omitempty"`
 }
 
 type UpdatePasswordDTO struct {
@@ -0,0 +1,91 @@
+package users
+
+import (
+	"context"
+	"messanger/domain/models"
+	"messanger/domain/service/auth"
+	"messanger/pkg/errors"
+	"net/http"
+)
+
+// UpdateEmail sets email of user and sends code confirming it, empty email removes it.
+// Email can be used for login and password reset only after it is confirmed
+func (s *UsersService) UpdateEmail(ctx context.Context, email string) *errors.Error {
+	if len(email) != 0 {
+		var e error
+		email, e = models.ParseEmail(email)
+		if e != nil {
+			return errors.New(e, "invalid email", http.StatusBadRequest)
+		}
+	}
+	userId := auth.ExtractUser(ctx)
+	user, err := s.usersRepo.GetById(ctx, userId)
+	if err != nil {
+		return err.Trace()
+	}
+	if user.Email == email && (len(email) == 0 || user.EmailConfirmed) {
+		return nil
+	}
+
+	if user.Email != email {
+		if err := s.usersRepo.UpdateEmail(ctx, userId, email); err != nil {
+			return err.Trace()
+		}
+		if len(email) == 0 {
+			return nil
+		}
+		user.Email = email
+	}
+	if err := s.sendEmailCode(ctx, user); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// SendEmailCode resends code confirming email of user
+func (s *UsersService) SendEmailCode(ctx context.Context) *errors.Error {
+	user, err := s.usersRepo.GetById(ctx, auth.ExtractUser(ctx))
+	if err != nil {
+		return err.Trace()
+	}
+	if len(user.Email) == 0 {
+		return errors.New1Msg("email is not set", http.StatusBadRequest)
+	}
+	if user.EmailConfirmed {
+		return errors.New1Msg("email is already confirmed", http.StatusBadRequest)
+	}
+	if err := s.sendEmailCode(ctx, user); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+func (s *UsersService) sendEmailCode(ctx context.Context, user *models.User) *errors.Error {
+	if err := s.phoneConf.SendCodeByEmail(ctx, models.CodePurposeEmail, user.Id, user.Phone, user.Email); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// ConfirmEmail checks code sent to current email of user
+func (s *UsersService) ConfirmEmail(ctx context.Context, code string) *errors.Error {
+	userId := auth.ExtractUser(ctx)
+	user, err := s.usersRepo.GetById(ctx, userId)
+	if err != nil {
+		return err.Trace()
+	}
+	if len(user.Email) == 0 {
+		return errors.New1Msg("email is not set", http.StatusBadRequest)
+	}
+	codeUserId, err := s.phoneConf.CheckCode(ctx, models.CodePurposeEmail, user.Email, code)
+	if err != nil {
+		return err.Trace()
+	}
+	if codeUserId != userId {
+		return errors.New1Msg("invalid code", http.StatusUnauthorized)
+	}
+	if err := s.usersRepo.ConfirmEmail(ctx, userId, user.Email); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 	"messanger/domain/models"
 	"messanger/domain/ports"
 	"messanger/domain/service/auth"
+	"messanger/domain/service/permissions"
 	"messanger/pkg/db"
 	"messanger/pkg/errors"
+	"messanger/pkg/password"
 	"net/http"
 	"regexp"
 	"sync"
@@ -15,9 +17,14 @@ import (
 
 type UsersService struct {
 	phoneConf    PhoneConfirmator
+	tokens       TokensRevoker
 	contactsRepo ports.ContactsRepo
 	usersRepo    ports.UsersRepo
 	chatsRepo    ports.ChatsRepo
+	groupsRepo   ports.GroupsRepo
+	auditRepo    ports.AuditRepo
+	perms        *permissions.Checker
+	events       EventsSender
 
 	createUserMu     sync.Mutex
 	updatePhoneMu    sync.Mutex
@@ -25,24 +32,47 @@ type UsersService struct {
 }
 
 type PhoneConfirmator interface {
-	ToConfirming(ctx context.Context, userId int, phone string) *errors.Error
-	ConfirmUser(ctx context.Context, code string) (int, *errors.Error)
+	SendCode(ctx context.Context, purpose string, userId int, phone string) *errors.Error
+	SendCodeByEmail(ctx context.Context, purpose string, userId int, phone, email string) *errors.Error
+	CheckCode(ctx context.Context, purpose string, phone string, code string) (int, *errors.Error)
+}
+
+// TokensRevoker signs user out of all devices, e.g. when credentials are changed
+type TokensRevoker interface {
+	RevokeUserTokens(ctx context.Context, userId int) *errors.Error
+}
+
+// EventsSender updates chats listened by online users
+type EventsSender interface {
+	OnChatMemberAdded(chatId int, userId int)
+	OnChatMemberRemoved(chatId int, userId int)
 }
 
 func NewUsersService(
 	usersRepo ports.UsersRepo,
 	contactsRepo ports.ContactsRepo,
 	chatsRepo ports.ChatsRepo,
+	groupsRepo ports.GroupsRepo,
+	auditRepo ports.AuditRepo,
 	phoneConf PhoneConfirmator,
+	tokens TokensRevoker,
 ) *UsersService {
 	return &UsersService{
 		usersRepo:    usersRepo,
 		contactsRepo: contactsRepo,
 		chatsRepo:    chatsRepo,
+		groupsRepo:   groupsRepo,
+		auditRepo:    auditRepo,
+		perms:        permissions.NewChecker(groupsRepo, chatsRepo),
 		phoneConf:    phoneConf,
+		tokens:       tokens,
 	}
 }
 
+func (s *UsersService) SetEventsSender(events EventsSender) {
+	s.events = events
+}
+
 var usernameRegexp = regexp.MustCompile("^[a-zA-Z0-9_]{4,32}$")
 
 func (s *UsersService) CreateUser(ctx context.Context, dto *CreateUserDTO) (err *errors.Error) {
@@ -58,26 +88,46 @@ func (s *UsersService) CreateUser(ctx context.Context, dto *CreateUserDTO) (err
 	if e != nil {
 		return errors.New(e, "invalid phone number", http.StatusBadRequest)
 	}
-
-	s.createUserMu.Lock()
-	defer s.createUserMu.Unlock()
-
-	if err := s.checkPhoneExist(ctx, dto.Phone); err != nil {
-		return err.Trace()
+	if e := models.ValidatePassword(dto.Password); e != nil {
+		return errors.New(e, e.Error(), http.StatusBadRequest)
 	}
-	if err := s.checkUsernameExist(ctx, dto.Name); err != nil {
-		return err.Trace()
+	hash, e := password.Hash(dto.Password)
+	if e != nil {
+		return errors.New(e, "hash password error", http.StatusInternalServerError)
 	}
 
 	user := &models.User{
 		Phone:      dto.Phone,
-		Password:   dto.Password,
+		Password:   hash,
 		Name:       dto.Name,
 		RealName:   dto.RealName,
 		ShowPhone:  true,
 		LastOnline: time.Now(),
 		Confirmed:  false,
 	}
+	if err := s.newUser(ctx, user); err != nil {
+		return err.Trace()
+	}
+	// code is sent after commit, so slow sms providers don't hold transaction and other registrations,
+	// if it fails user can request new code
+	if err := s.phoneConf.SendCode(ctx, models.CodePurposeRegistration, user.Id, user.Phone); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
+// newUser saves user with saved messages chat, phone and username are checked under lock
+func (s *UsersService) newUser(ctx context.Context, user *models.User) (err *errors.Error) {
+	s.createUserMu.Lock()
+	defer s.createUserMu.Unlock()
+
+	if err := s.checkPhoneExist(ctx, user.Phone); err != nil {
+		return err.Trace()
+	}
+	if err := s.checkUsernameExist(ctx, user.Name); err != nil {
+		return err.Trace()
+	}
+
 	ctx, err = db.WithTx(ctx, s.usersRepo)
 	if err != nil {
 		return err.Trace()
@@ -87,29 +137,53 @@ func (s *UsersService) CreateUser(ctx context.Context, dto *CreateUserDTO) (err
 	if err := s.usersRepo.New(ctx, user); err != nil {
 		return err.Trace()
 	}
-	if err := s.phoneConf.ToConfirming(ctx, user.Id, user.Phone); err != nil {
+	if err := s.createSavedChat(ctx, user.Id); err != nil {
 		return err.Trace()
 	}
 	return nil
 }
 
-func (s *UsersService) SendCode(ctx context.Context, phone string) *errors.Error {
+// confirmPurpose returns purpose of phone confirmation code, it is registration by default
+func confirmPurpose(purpose string) (string, *errors.Error) {
+	switch purpose {
+	case "":
+		return models.CodePurposeRegistration, nil
+	case models.CodePurposeRegistration, models.CodePurposePhoneChange:
+		return purpose, nil
+	}
+	return "", errors.New1Msg("invalid code purpose: "+purpose, http.StatusBadRequest)
+}
+
+// SendCode resends code confirming phone after registration or phone change
+func (s *UsersService) SendCode(ctx context.Context, phone, purpose string) *errors.Error {
 	phone, e := models.ParsePhone(phone)
 	if e != nil {
 		return errors.New(e, "invalid phone number", http.StatusBadRequest)
 	}
+	purpose, err := confirmPurpose(purpose)
+	if err != nil {
+		return err.Trace()
+	}
 	user, err := s.usersRepo.FindByPhone(ctx, phone)
 	if err != nil {
 		return err.Trace()
 	}
-	if err := s.phoneConf.ToConfirming(ctx, user.Id, user.Phone); err != nil {
+	if err := s.phoneConf.SendCode(ctx, purpose, user.Id, user.Phone); err != nil {
 		return err.Trace()
 	}
 	return nil
 }
 
-func (s *UsersService) ConfirmPhone(ctx context.Context, phone, code string) *errors.Error {
-	userId, err := s.phoneConf.ConfirmUser(ctx, code)
+func (s *UsersService) ConfirmPhone(ctx context.Context, phone, code, purpose string) *errors.Error {
+	phone, e := models.ParsePhone(phone)
+	if e != nil {
+		return errors.New(e, "invalid phone number", http.StatusBadRequest)
+	}
+	purpose, err := confirmPurpose(purpose)
+	if err != nil {
+		return err.Trace()
+	}
+	userId, err := s.phoneConf.CheckCode(ctx, purpose, phone, code)
 	if err != nil {
 		return err.Trace()
 	}
@@ -143,6 +217,17 @@ func (s *UsersService) CheckUsername(ctx context.Context, username string) (bool
 }
 
 func (s *UsersService) UpdateUser(ctx context.Context, dto *UpdateUserDTO) (err *errors.Error) {
+	userId := auth.ExtractUser(ctx)
+	var signOut bool
+	var phone string
+	// ctx is replaced by transaction, tokens are revoked and code is sent after commit,
+	// so slow sms providers don't hold transaction and rolled back changes don't sign user out
+	baseCtx := ctx
+	defer func() {
+		if err == nil && signOut {
+			err = s.onCredentialsUpdated(baseCtx, userId, phone)
+		}
+	}()
 	ctx, err = db.WithTx(ctx, s.usersRepo)
 	if err != nil {
 		return err.Trace()
@@ -163,16 +248,16 @@ func (s *UsersService) UpdateUser(ctx context.Context, dto *UpdateUserDTO) (err
 		isUpdating = true
 	}
 	if len(dto.Password) != 0 {
-		if err := s.UpdatePassword(ctx, dto.OldPassword, dto.Password); err != nil {
+		if err := s.updatePassword(ctx, dto.OldPassword, dto.Password); err != nil {
 			return err.Trace()
 		}
-		isUpdating = true
+		isUpdating, signOut = true, true
 	}
 	if len(dto.Phone) != 0 {
-		if err := s.UpdatePhone(ctx, dto.Phone); err != nil {
+		if phone, err = s.updatePhone(ctx, dto.Phone); err != nil {
 			return err.Trace()
 		}
-		isUpdating = true
+		isUpdating, signOut = true, true
 	}
 	if !isUpdating {
 		return errors.New1Msg("all fields is null", http.StatusBadRequest)
@@ -197,26 +282,39 @@ func (s *UsersService) UpdateUsername(ctx context.Context, name string) *errors.
 	return nil
 }
 
-func (s *UsersService) UpdatePassword(ctx context.Context, oldPass, newPass string) *errors.Error {
+// updatePassword sets new password, user must be signed out by onCredentialsUpdated after commit
+func (s *UsersService) updatePassword(ctx context.Context, oldPass, newPass string) *errors.Error {
 	if len(newPass) == 0 {
 		return errors.New1Msg("missing new password", http.StatusBadRequest)
 	}
+	if e := models.ValidatePassword(newPass); e != nil {
+		return errors.New(e, e.Error(), http.StatusBadRequest)
+	}
 	userId := auth.ExtractUser(ctx)
-	_, err := s.usersRepo.GetByIdWithPass(ctx, userId, oldPass)
+	user, err := s.usersRepo.GetByIdWithPass(ctx, userId)
 	if err != nil {
 		return err.Trace()
 	}
+	if ok, _ := password.Verify(oldPass, user.Password); !ok {
+		return errors.New1Msg("invalid password", http.StatusUnauthorized)
+	}
 
-	if err := s.usersRepo.UpdatePassword(ctx, userId, newPass); err != nil {
+	hash, e := password.Hash(newPass)
+	if e != nil {
+		return errors.New(e, "hash password error", http.StatusInternalServerError)
+	}
+	if err := s.usersRepo.UpdatePassword(ctx, userId, hash); err != nil {
 		return err.Trace()
 	}
 	return nil
 }
 
-func (s *UsersService) UpdatePhone(ctx context.Context, phone string) *errors.Error {
+// updatePhone sets new unconfirmed phone and returns it parsed, code confirming it must be sent
+// by onCredentialsUpdated after commit
+func (s *UsersService) updatePhone(ctx context.Context, phone string) (string, *errors.Error) {
 	phone, e := models.ParsePhone(phone)
 	if e != nil {
-		return errors.New(e, "invalid phone number", http.StatusBadRequest)
+		return "", errors.New(e, "invalid phone number", http.StatusBadRequest)
 	}
 
 	userId := auth.ExtractUser(ctx)
@@ -234,15 +332,27 @@ func (s *UsersService) UpdatePhone(ctx context.Context, phone string) *errors.Er
 
 		return nil
 	}(); err != nil {
-		return err
+		return "", err
 	}
 
-	if err := s.phoneConf.ToConfirming(ctx, userId, phone); err != nil {
-		return err.Trace()
-	}
 	if err := s.usersRepo.SetConfirm(ctx, userId, false); err != nil {
+		return "", err.Trace()
+	}
+	return phone, nil
+}
+
+// onCredentialsUpdated signs user out of all devices after password or phone change,
+// and sends code confirming new phone if it is changed
+func (s *UsersService) onCredentialsUpdated(ctx context.Context, userId int, phone string) *errors.Error {
+	if err := s.tokens.RevokeUserTokens(ctx, userId); err != nil {
 		return err.Trace()
 	}
+	if len(phone) != 0 {
+		// if it fails user can request new code
+		if err := s.phoneConf.SendCode(ctx, models.CodePurposePhoneChange, userId, phone); err != nil {
+			return err.Trace()
+		}
+	}
 	return nil
 }
 
@@ -282,6 +392,12 @@ func (s *UsersService) CreateChatWithUser(ctx context.Context, userId int) (chat
 	chat = &models.Chat{
 		Type: models.ChatTypeUser,
 	}
+	defer func() {
+		if err == nil && s.events != nil {
+			go s.events.OnChatMemberAdded(chat.Id, actionerId)
+			go s.events.OnChatMemberAdded(chat.Id, userId)
+		}
+	}()
 	ctx, err = db.WithTx(ctx, s.chatsRepo)
 	if err != nil {
 		return nil, err.Trace()
@@ -301,6 +417,17 @@ func (s *UsersService) CreateChatWithUser(ctx context.Context, userId int) (chat
 	return chat, nil
 }
 
+func (s *UsersService) createSavedChat(ctx context.Context, userId int) *errors.Error {
+	chat := new(models.Chat)
+	if err := s.chatsRepo.NewSaved(ctx, chat, userId); err != nil {
+		return err.Trace()
+	}
+	if err := s.chatsRepo.AddUserToChat(ctx, chat.Id, userId); err != nil {
+		return err.Trace()
+	}
+	return nil
+}
+
 func (s *UsersService) SetShowPhone(ctx context.Context, v bool) *errors.Error {
 	userId := auth.ExtractUser(ctx)
 	if err := s.usersRepo.SetShowPhone(ctx, userId, v); err != nil {
@@ -338,6 +465,8 @@ func (s *UsersService) FindUser(ctx context.Context, dto *FindUserDTO) (*UserRes
 	if actionerId == user.Id {
 		resp.Phone = user.Phone
 		resp.ShowPhone = user.ShowPhone
+		resp.Email = user.Email
+		resp.EmailConfirmed = user.EmailConfirmed
 		return resp, nil
 	}
 
@@ -402,13 +531,29 @@ func (s *UsersService) FindByName(ctx context.Context, name string) (*models.Use
 func (s *UsersService) DeleteUser(ctx context.Context) (err *errors.Error) {
 	userId := auth.ExtractUser(ctx)
 
+	var chats []models.Chat
+	// ctx is replaced by transaction, tokens are revoked after commit
+	baseCtx := ctx
+	defer func() {
+		if err != nil {
+			return
+		}
+		if s.events != nil {
+			for _, chat := range chats {
+				go s.events.OnChatMemberRemoved(chat.Id, userId)
+			}
+		}
+		if e := s.tokens.RevokeUserTokens(baseCtx, userId); e != nil {
+			err = e.Trace()
+		}
+	}()
 	ctx, err = db.WithTx(ctx, s.usersRepo)
 	if err != nil {
 		return err.Trace()
 	}
 	defer db.CommitOnDefer(ctx, &err)
 
-	chats, err := s.chatsRepo.GetByUserId(ctx, userId)
+	chats, err = s.chatsRepo.GetByUserId(ctx, userId)
 	if err != nil {
 		return err.Trace()
 	}
@@ -422,6 +567,34 @@ func (s *UsersService) DeleteUser(ctx context.Context) (err *errors.Error) {
 			if err := s.chatsRepo.Delete(ctx, chat.Id); err != nil {
 				return err.Trace()
 			}
+			continue
+		}
+		if chat.Type == models.ChatTypeGroup || chat.Type == models.ChatTypeChannel {
+			group, err := s.groupsRepo.GetGroupByChatId(ctx, chat.Id)
+			if err != nil {
+				return err.Trace()
+			}
+			successor, err := s.perms.EnsureSuccessor(ctx, group.Id, userId)
+			if err != nil {
+				return err.Trace()
+			}
+			if successor != 0 {
+				if err := s.auditRepo.New(ctx, &models.AuditEntry{
+					GroupId:  group.Id,
+					ActorId:  userId,
+					Action:   models.AuditActionSuccession,
+					TargetId: successor,
+				}); err != nil {
+					return err.Trace()
+				}
+			}
+			if err := s.auditRepo.New(ctx, &models.AuditEntry{
+				GroupId: group.Id,
+				ActorId: userId,
+				Action:  models.AuditActionLeave,
+			}); err != nil {
+				return err.Trace()
+			}
 		}
 	}
 
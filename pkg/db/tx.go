@@ -27,6 +27,15 @@ func WithTx(ctx context.Context, db any) (context.Context, *errors.Error) {
 	return ctx, nil
 }
 
+// WithoutTx returns ctx whose queries are not run in its transaction, e.g. to save status
+// which must be kept on rollback
+func WithoutTx(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, txKey{}, nil)
+}
+
 func Commit(ctx context.Context) *errors.Error {
 	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
 	if ok {
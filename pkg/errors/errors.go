@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"runtime"
 	"strconv"
+	"time"
 )
 
 type Error struct {
@@ -12,6 +13,7 @@ type Error struct {
 	Msg         string
 	UserMessage string
 	Code        int
+	RetryAfter  time.Duration
 }
 
 func New(msg any, userMsg any, code int) *Error {
@@ -38,6 +40,11 @@ func (e *Error) Error() string {
 	return e.TracePath + ":\n" + e.UserMessage + ": " + e.Msg
 }
 
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	e.RetryAfter = d
+	return e
+}
+
 func (e *Error) Trace() *Error {
 	e.TracePath = getCaller() + " > " + e.TracePath
 	return e
@@ -0,0 +1,83 @@
+// Package password hashes passwords with argon2id and verifies hashes of all supported versions
+package password
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+// params of argon2id, RFC 9106 second recommended option
+const (
+	time    = 3
+	memory  = 64 * 1024
+	threads = 4
+	keyLen  = 32
+	saltLen = 16
+)
+
+const prefixArgon2id = "$argon2id$"
+
+var encoding = base64.RawStdEncoding
+
+// Hash returns hash in PHC string format: $argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>.
+// Parameters are stored in hash, so they can be changed without breaking old hashes
+func Hash(password string) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, time, memory, threads, keyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", prefixArgon2id, argon2.Version, memory, time, threads,
+		encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+// Verify checks password against hash, needsRehash is true if hash is valid but made with legacy
+// algorithm or outdated parameters and should be replaced with Hash of password
+func Verify(password, hash string) (ok bool, needsRehash bool) {
+	if !strings.HasPrefix(hash, prefixArgon2id) {
+		return verifyLegacy(password, hash), true
+	}
+
+	var version int
+	var m uint32
+	var t uint32
+	var p uint8
+	parts := strings.Split(strings.TrimPrefix(hash, prefixArgon2id), "$")
+	if len(parts) != 4 {
+		return false, false
+	}
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false
+	}
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &m, &t, &p); err != nil {
+		return false, false
+	}
+	salt, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return false, false
+	}
+	key, err := encoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return false, false
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, t, m, p, uint32(len(key)))
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return false, false
+	}
+	return true, m != memory || t != time || p != threads || len(key) != keyLen || len(salt) != saltLen
+}
+
+// legacySalt is prefix of legacy hashes, which are sha256 of password appended to it
+var legacySalt = []byte("nwy9837ctp5")
+
+func verifyLegacy(password, hash string) bool {
+	sum := sha256.Sum256([]byte(password))
+	expected := append(append([]byte{}, legacySalt...), sum[:]...)
+	return subtle.ConstantTimeCompare(expected, []byte(hash)) == 1
+}
@@ -0,0 +1,51 @@
+package password
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	hash, err := Hash("password1")
+	require.NoError(t, err)
+
+	ok, needsRehash := Verify("password1", hash)
+	require.True(t, ok)
+	require.False(t, needsRehash)
+
+	ok, _ = Verify("password2", hash)
+	require.False(t, ok)
+
+	other, err := Hash("password1")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other, "hashes should be salted")
+}
+
+func TestVerifyLegacy(t *testing.T) {
+	sum := sha256.Sum256([]byte("password1"))
+	legacy := string(legacySalt) + string(sum[:])
+
+	ok, needsRehash := Verify("password1", legacy)
+	require.True(t, ok)
+	require.True(t, needsRehash, "legacy hash should be replaced")
+
+	ok, _ = Verify("password2", legacy)
+	require.False(t, ok)
+}
+
+func TestVerifyOutdatedParams(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	key := argon2.IDKey([]byte("password1"), salt, 1, 16*1024, 1, keyLen)
+	hash := fmt.Sprintf("$argon2id$v=%d$m=%d,t=1,p=1$%s$%s", argon2.Version, 16*1024,
+		encoding.EncodeToString(salt), encoding.EncodeToString(key))
+
+	ok, needsRehash := Verify("password1", hash)
+	require.True(t, ok)
+	require.True(t, needsRehash, "hash with outdated parameters should be replaced")
+
+	ok, _ = Verify("password1", "$argon2id$invalid")
+	require.False(t, ok)
+}
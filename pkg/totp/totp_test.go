@@ -0,0 +1,42 @@
+package totp
+
+import (
+	"encoding/base32"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCode(t *testing.T) {
+	// test vectors of RFC 6238 truncated to 6 digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	for unix, code := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		got, err := Code(secret, Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		require.Equal(t, code, got, "time %d", unix)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	now := time.Now()
+
+	code, err := Code(secret, Step(now.Add(-Period)))
+	require.NoError(t, err)
+	step, ok := Verify(secret, code, now, 0)
+	require.True(t, ok, "code of previous step should be accepted")
+
+	_, ok = Verify(secret, code, now, step)
+	require.False(t, ok, "used code should be rejected")
+
+	code, err = Code(secret, Step(now.Add(-2*Period)))
+	require.NoError(t, err)
+	_, ok = Verify(secret, code, now, 0)
+	require.False(t, ok, "outdated code should be rejected")
+}